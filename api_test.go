@@ -146,3 +146,200 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestAPIConvertFiles_JSON(t *testing.T) {
+	req := ConvertRequest{
+		SQL: "CREATE TABLE users (id INT NOT NULL); CREATE TABLE orders (id INT NOT NULL);",
+	}
+
+	body, _ := json.Marshal(req)
+	r := httptest.NewRequest("POST", "/api/convert/files", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handleConvertFiles(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	var resp ConvertResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	if len(resp.Files) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(resp.Files))
+	}
+
+	if !contains(resp.Files["orders.go"], "type Orders struct") {
+		t.Errorf("Expected Orders struct in orders.go, got: %s", resp.Files["orders.go"])
+	}
+}
+
+func TestAPIConvertFiles_Zip(t *testing.T) {
+	req := ConvertRequest{
+		SQL: "CREATE TABLE users (id INT NOT NULL)",
+	}
+
+	body, _ := json.Marshal(req)
+	r := httptest.NewRequest("POST", "/api/convert/files?format=zip", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handleConvertFiles(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/zip" {
+		t.Errorf("Expected application/zip, got %s", ct)
+	}
+
+	if !bytes.HasPrefix(w.Body.Bytes(), []byte("PK")) {
+		t.Error("Expected a zip archive body")
+	}
+}
+
+func TestAPIConvertFiles_InvalidSQL(t *testing.T) {
+	body, _ := json.Marshal(ConvertRequest{SQL: "not sql"})
+	r := httptest.NewRequest("POST", "/api/convert/files?format=zip", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handleConvertFiles(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestAPIDDL_Success(t *testing.T) {
+	req := DDLRequest{
+		Go:      "type User struct {\n\tID int64\n\tEmail *string\n}",
+		Dialect: "postgres",
+	}
+
+	body, _ := json.Marshal(req)
+	r := httptest.NewRequest("POST", "/api/ddl", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	newServeMux().ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp ConvertResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	if !contains(resp.Code, "CREATE TABLE \"user\"") || !contains(resp.Code, "id BIGSERIAL NOT NULL") {
+		t.Errorf("Expected PostgreSQL DDL, got:\n%s", resp.Code)
+	}
+}
+
+func TestAPIDDL_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		req  DDLRequest
+	}{
+		{"Empty source", DDLRequest{}},
+		{"Invalid Go", DDLRequest{Go: "type User struct {"}},
+		{"Unknown dialect", DDLRequest{Go: "type User struct { ID int }", Dialect: "oracle"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.req)
+			r := httptest.NewRequest("POST", "/api/ddl", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+
+			handleDDL(w, r)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestAPIParse_Success(t *testing.T) {
+	req := ParseRequest{
+		SQL:     "CREATE TABLE users (id SERIAL PRIMARY KEY, email TEXT NOT NULL, bogus);",
+		Dialect: "postgres",
+	}
+
+	body, _ := json.Marshal(req)
+	r := httptest.NewRequest("POST", "/api/parse", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	newServeMux().ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp ParseResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	if resp.Schema == nil || resp.Schema.Version != SchemaFormatVersion || resp.Schema.Dialect != "postgres" {
+		t.Fatalf("Expected a version %d postgres schema, got %+v", SchemaFormatVersion, resp.Schema)
+	}
+	table := resp.Schema.Tables[0]
+	if table.Name != "users" || len(table.Columns) != 2 || !table.Columns[0].AutoIncrement || table.PrimaryKey[0] != "id" {
+		t.Errorf("Unexpected table: %+v", table)
+	}
+	if len(resp.Warnings) != 1 {
+		t.Errorf("Expected 1 warning for the skipped line, got %v", resp.Warnings)
+	}
+}
+
+func TestAPIParse_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		req  ParseRequest
+	}{
+		{"Empty SQL", ParseRequest{}},
+		{"Invalid SQL", ParseRequest{SQL: "not sql"}},
+		{"Unknown dialect", ParseRequest{SQL: "CREATE TABLE t (id INT)", Dialect: "oracle"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.req)
+			r := httptest.NewRequest("POST", "/api/parse", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+
+			handleParse(w, r)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestAPIConvert_Schema(t *testing.T) {
+	body := []byte(`{"schema": {"version": 1, "tables": [{"name": "users", "columns": [{"name": "email", "sql_type": "VARCHAR", "nullable": true}]}]}}`)
+	r := httptest.NewRequest("POST", "/api/convert", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handleConvert(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp ConvertResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	if !contains(resp.Code, "type Users struct") || !contains(resp.Code, "Email *string") {
+		t.Errorf("Expected the struct of the schema JSON, got:\n%s", resp.Code)
+	}
+
+	body = []byte(`{"schema": {"version": 9, "tables": []}}`)
+	r = httptest.NewRequest("POST", "/api/convert", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+
+	handleConvert(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unsupported version, got %d", w.Code)
+	}
+}
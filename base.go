@@ -0,0 +1,333 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// gormModelType is the Config.BaseStruct value embedding gorm.Model
+const gormModelType = "gorm.Model"
+
+// baseFileName is the shared file holding the base struct in multi-file output
+const baseFileName = "base.go"
+
+// gormModelFields are the fields of gorm.Model, by column
+var gormModelFields = map[string]FieldDef{
+	"id":         {Name: "ID", Type: "uint"},
+	"created_at": {Name: "CreatedAt", Type: "time.Time"},
+	"updated_at": {Name: "UpdatedAt", Type: "time.Time"},
+	"deleted_at": {Name: "DeletedAt", Type: "gorm.DeletedAt", TypeImport: "gorm.io/gorm"},
+}
+
+// gormModelColumns are the columns gorm.Model stands for. Like in ParseGoStructs, its
+// ID is a BIGINT UNSIGNED auto-increment key and only deleted_at is nullable.
+var gormModelColumns = []FieldDef{
+	{Name: "Id", Type: "uint64", ColumnName: "id", PrimaryKey: true, AutoIncrement: true},
+	{Name: "CreatedAt", Type: "time.Time", ColumnName: "created_at"},
+	{Name: "UpdatedAt", Type: "time.Time", ColumnName: "updated_at"},
+	{Name: "DeletedAt", Type: "*time.Time", ColumnName: "deleted_at", Nullable: true},
+}
+
+// applyBaseStruct returns a copy of defs where the base columns of config.BaseStruct are
+// embedded from it, in the tables that have all of them with the same field name, type,
+// nullability and tags. For gorm.Model the fields take gorm.Model's names and types.
+// When no table embeds the base struct, it returns defs with a warning saying why.
+func applyBaseStruct(defs []StructDef, config Config) ([]StructDef, []string) {
+	if config.BaseStruct == "" {
+		return defs, nil
+	}
+	base, reason := baseColumns(defs, config)
+	if len(base) == 0 {
+		return defs, []string{fmt.Sprintf("no base struct %s extracted: %s", config.BaseStruct, reason)}
+	}
+
+	result := make([]StructDef, len(defs))
+	embedded := 0
+	var mismatches []string
+	for i, def := range defs {
+		result[i] = def
+		if mismatch := baseMismatch(def, base, config); mismatch != "" {
+			if hasColumnNames(def, base) {
+				mismatches = append(mismatches, def.TableName+": "+mismatch)
+			}
+			continue
+		}
+		embedded++
+
+		fields := make([]FieldDef, len(def.Fields))
+		copy(fields, def.Fields)
+		for _, column := range base {
+			field := &fields[fieldIndex(fields, column.ColumnName)]
+			field.Embedded = config.BaseStruct
+			if model, ok := gormModelFields[column.ColumnName]; ok && config.BaseStruct == gormModelType {
+				field.Name, field.Type, field.TypeImport = model.Name, model.Type, model.TypeImport
+			}
+		}
+		result[i].Fields = fields
+	}
+	if embedded == 0 {
+		reason := "no table has the columns " + strings.Join(columnNames(base), ", ")
+		if len(mismatches) > 0 {
+			reason = "no table matches it (" + strings.Join(mismatches, "; ") + ")"
+		}
+		return defs, []string{fmt.Sprintf("no base struct %s extracted: %s", config.BaseStruct, reason)}
+	}
+	return result, nil
+}
+
+// baseColumns returns the definitions the columns of the base struct must have:
+// those of gorm.Model, those of config.BaseColumns in the first table having all
+// of them, or the detected columns shared by the most tables. Without columns, it
+// returns the reason.
+func baseColumns(defs []StructDef, config Config) ([]FieldDef, string) {
+	if config.BaseStruct == gormModelType {
+		return gormModelColumns, ""
+	}
+	if len(config.BaseColumns) == 0 {
+		return detectBaseColumns(defs, config)
+	}
+
+	for _, def := range defs {
+		var base []FieldDef
+		for _, column := range config.BaseColumns {
+			i := fieldIndex(def.Fields, column)
+			if i < 0 || def.Fields[i].Association != "" {
+				break
+			}
+			base = append(base, def.Fields[i])
+		}
+		if len(base) == len(config.BaseColumns) {
+			return base, ""
+		}
+	}
+	return nil, fmt.Sprintf("no table has all the base columns %s", strings.Join(config.BaseColumns, ", "))
+}
+
+// detectBaseColumns returns the columns shared with the same definition by a set of
+// tables, in the order of the first of them. Of the candidate sets, it takes the one
+// whose base struct replaces the most fields. At least two columns shared by two
+// tables are needed; without them, it returns the reason.
+func detectBaseColumns(defs []StructDef, config Config) ([]FieldDef, string) {
+	type shared struct {
+		field  FieldDef
+		tables map[int]bool
+	}
+	var columns []*shared
+	bySignature := make(map[string]*shared)
+	for i, def := range defs {
+		for _, field := range def.Fields {
+			if field.Association != "" {
+				continue
+			}
+			signature := baseFieldSignature(field, config)
+			column, ok := bySignature[signature]
+			if !ok {
+				column = &shared{field: field, tables: make(map[int]bool)}
+				bySignature[signature] = column
+				columns = append(columns, column)
+			}
+			column.tables[i] = true
+		}
+	}
+
+	// The candidate sets are the tables of each column. A column shared by more
+	// tables is also shared by each candidate set it contains.
+	var best []FieldDef
+	var sharedNames []string
+	bestScore := 0
+	for _, candidate := range columns {
+		if len(candidate.tables) < 2 {
+			continue
+		}
+		if !containsFold(sharedNames, candidate.field.ColumnName) {
+			sharedNames = append(sharedNames, candidate.field.ColumnName)
+		}
+		var base []FieldDef
+		for _, column := range columns {
+			if containsTables(column.tables, candidate.tables) {
+				base = append(base, column.field)
+			}
+		}
+		if score := len(base) * len(candidate.tables); len(base) >= 2 && score > bestScore {
+			best, bestScore = base, score
+		}
+	}
+	switch {
+	case best != nil:
+		return best, ""
+	case len(sharedNames) > 0:
+		return nil, fmt.Sprintf("no tables share more than one column with the same definition (shared: %s)", strings.Join(sharedNames, ", "))
+	default:
+		return nil, "no column has the same definition in two tables"
+	}
+}
+
+// containsTables reports whether the table set a contains every table of b
+func containsTables(a, b map[int]bool) bool {
+	for table := range b {
+		if !a[table] {
+			return false
+		}
+	}
+	return true
+}
+
+// hasColumnNames reports whether a table has every base column, whatever its definition
+func hasColumnNames(def StructDef, base []FieldDef) bool {
+	for _, column := range base {
+		if fieldIndex(def.Fields, column.ColumnName) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// baseMismatch returns why a table doesn't have every base column with its
+// definition, or "" when it does
+func baseMismatch(def StructDef, base []FieldDef, config Config) string {
+	for _, column := range base {
+		i := fieldIndex(def.Fields, column.ColumnName)
+		if i < 0 || def.Fields[i].Association != "" {
+			return fmt.Sprintf("no column %s", column.ColumnName)
+		}
+		field := def.Fields[i]
+		if config.BaseStruct == gormModelType {
+			// gorm.Model brings its own names and tags
+			switch {
+			case field.Type != column.Type || field.TypeImport != "":
+				return fmt.Sprintf("%s is %s, not %s", column.ColumnName, field.Type, column.Type)
+			case field.PrimaryKey != column.PrimaryKey || field.AutoIncrement != column.AutoIncrement:
+				return fmt.Sprintf("%s differs in primary key or auto-increment", column.ColumnName)
+			}
+			continue
+		}
+		if baseFieldSignature(field, config) != baseFieldSignature(column, config) {
+			return fmt.Sprintf("%s has another definition", column.ColumnName)
+		}
+	}
+	return ""
+}
+
+// baseFieldSignature identifies the declaration of a field: the same signature
+// declares the same struct field
+func baseFieldSignature(field FieldDef, config Config) string {
+	return strings.Join([]string{field.Name, field.Type, field.TypeImport, fmt.Sprint(field.Nullable), generatedFieldComment(field), generateStructTags(field, config)}, "\x00")
+}
+
+// baseStructDef returns the definition of the base struct declared for defs, from the
+// embedded fields of the first table embedding it. It reports false when no table
+// embeds it or it is gorm.Model, which is not declared.
+func baseStructDef(defs []StructDef, config Config) (StructDef, bool) {
+	if config.BaseStruct == gormModelType {
+		return StructDef{}, false
+	}
+	for _, def := range defs {
+		var fields []FieldDef
+		for _, field := range def.Fields {
+			if field.Embedded != "" {
+				field.Embedded = ""
+				fields = append(fields, field)
+			}
+		}
+		if len(fields) > 0 {
+			return StructDef{Name: config.BaseStruct, Fields: fields}, true
+		}
+	}
+	return StructDef{}, false
+}
+
+// generateBaseStruct generates the base struct declared for defs, or ""
+func generateBaseStruct(defs []StructDef, config Config) string {
+	base, ok := baseStructDef(defs, config)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("// %s holds the columns shared by the tables embedding it\n", base.Name) + generateStruct(base, config)
+}
+
+// embeddedType returns the type embedded by a table for its base columns, or ""
+func embeddedType(def StructDef) string {
+	for _, field := range def.Fields {
+		if field.Embedded != "" {
+			return field.Embedded
+		}
+	}
+	return ""
+}
+
+// declaredDefs returns defs without the fields declared by the embedded base struct,
+// for the imports of files that don't declare it. DTOs repeat those fields, so with
+// them defs are returned as they are.
+func declaredDefs(defs []StructDef, config Config) []StructDef {
+	if config.BaseStruct == "" || config.AddDTOs {
+		return defs
+	}
+	result := make([]StructDef, len(defs))
+	for i, def := range defs {
+		var fields []FieldDef
+		for _, field := range def.Fields {
+			if field.Embedded == "" {
+				fields = append(fields, field)
+			}
+		}
+		def.Fields = fields
+		result[i] = def
+	}
+	return result
+}
+
+// baseImports returns the import of gorm.Model when defs embed it
+func baseImports(defs []StructDef) []string {
+	for _, def := range defs {
+		if embeddedType(def) == gormModelType {
+			return []string{"gorm.io/gorm"}
+		}
+	}
+	return nil
+}
+
+// generateReturnLiteral generates the statement returning a typeName composite literal
+// that sets fields to values. Fields embedded from the base struct are set in a literal
+// of it, which comes first like the embedded field.
+func generateReturnLiteral(typeName string, fields []FieldDef, values []string) string {
+	if len(fields) == 0 {
+		return fmt.Sprintf("\treturn %s{}\n", typeName)
+	}
+
+	var embedded, direct []int
+	for i, field := range fields {
+		if field.Embedded != "" {
+			embedded = append(embedded, i)
+		} else {
+			direct = append(direct, i)
+		}
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("\treturn %s{\n", typeName))
+	if len(embedded) > 0 {
+		baseType := fields[embedded[0]].Embedded
+		output.WriteString(fmt.Sprintf("\t\t%s: %s{\n", embeddedFieldName(baseType), baseType))
+		writeFieldValues(&output, "\t\t\t", fields, values, embedded)
+		output.WriteString("\t\t},\n")
+	}
+	writeFieldValues(&output, "\t\t", fields, values, direct)
+	output.WriteString("\t}\n")
+	return output.String()
+}
+
+// writeFieldValues writes the aligned "Name: value," lines of the fields at indexes
+func writeFieldValues(output *strings.Builder, indent string, fields []FieldDef, values []string, indexes []int) {
+	maxNameLen := 0
+	for _, i := range indexes {
+		maxNameLen = max(maxNameLen, len(fields[i].Name))
+	}
+	for _, i := range indexes {
+		output.WriteString(fmt.Sprintf("%s%s:%s%s,\n", indent, fields[i].Name, strings.Repeat(" ", maxNameLen-len(fields[i].Name)+1), values[i]))
+	}
+}
+
+// embeddedFieldName returns the name of an embedded field of a type, such as Model for gorm.Model
+func embeddedFieldName(typeName string) string {
+	return typeName[strings.LastIndex(typeName, ".")+1:]
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// baseSQL has two tables sharing id, created_at, updated_at and deleted_at, one with a
+// nullable created_at and no deleted_at, and a join table without them
+const baseSQL = `
+CREATE TABLE users (
+	id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	email VARCHAR(255) NOT NULL,
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL,
+	deleted_at DATETIME
+);
+CREATE TABLE posts (
+	id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	title VARCHAR(255) NOT NULL,
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL,
+	deleted_at DATETIME
+);
+CREATE TABLE drafts (
+	id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	body TEXT,
+	created_at DATETIME,
+	updated_at DATETIME NOT NULL
+);
+CREATE TABLE user_posts (user_id BIGINT UNSIGNED NOT NULL, post_id BIGINT UNSIGNED NOT NULL);`
+
+// TestGenerateGoCode_BaseStructDetected tests that the columns shared by the most tables are embedded
+func TestGenerateGoCode_BaseStructDetected(t *testing.T) {
+	structs, err := ParseSQL(baseSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	code := GenerateGoCode(structs, Config{AddJSONTag: true, BaseStruct: "Base"})
+
+	// id and updated_at are shared by three tables, but the four columns shared by users
+	// and posts replace more fields
+	for _, expected := range []string{
+		"// Base holds the columns shared by the tables embedding it\ntype Base struct {\n" +
+			"\tId        uint64     `json:\"id\"`\n" +
+			"\tCreatedAt time.Time  `json:\"created_at\"`\n" +
+			"\tUpdatedAt time.Time  `json:\"updated_at\"`\n" +
+			"\tDeletedAt *time.Time `json:\"deleted_at\"`\n}\n",
+		"type Users struct {\n\tBase\n\tEmail string `json:\"email\"`\n}\n",
+		"type Posts struct {\n\tBase\n\tTitle string `json:\"title\"`\n}\n",
+		"type Drafts struct {\n\tId        uint64     `json:\"id\"`\n",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, code)
+		}
+	}
+	typeCheck(t, code, nil)
+}
+
+// TestGenerateGoCode_BaseColumns tests configured base columns with the generated helpers
+func TestGenerateGoCode_BaseColumns(t *testing.T) {
+	structs, err := ParseSQL(baseSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	config := Config{BaseStruct: "Model", BaseColumns: []string{"id", "updated_at"}, AddRepository: true, AddDTOs: true, AddConstructors: true}
+	code := GenerateGoCode(structs, config)
+
+	for _, expected := range []string{
+		"type Model struct {\n\tId        uint64\n\tUpdatedAt time.Time\n}\n",
+		"type Drafts struct {\n\tModel\n\tBody      *string\n\tCreatedAt *time.Time\n}\n",
+		"\treturn Drafts{\n\t\tModel: Model{\n\t\t\tUpdatedAt: c.UpdatedAt,\n\t\t},\n\t\tBody:      c.Body,\n\t\tCreatedAt: c.CreatedAt,\n\t}\n",
+		"type UserPosts struct {\n\tUserId uint64\n",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, code)
+		}
+	}
+	typeCheck(t, code, nil)
+
+	// The base struct goes to base.go, and files embedding it import only what they declare
+	files := GenerateGoFiles(structs, Config{BaseStruct: "Model", BaseColumns: []string{"id", "created_at", "updated_at", "deleted_at"}})
+	if !strings.Contains(files[baseFileName], "import \"time\"\n\n// Model holds") {
+		t.Errorf("Expected the base struct in base.go, got:\n%s", files[baseFileName])
+	}
+	if strings.Contains(files["posts.go"], "type Model") || strings.Contains(files["posts.go"], "import") {
+		t.Errorf("Expected posts.go without the base struct and imports, got:\n%s", files["posts.go"])
+	}
+}
+
+// TestGenerateGoCode_GormModel tests embedding gorm.Model in the tables matching it
+func TestGenerateGoCode_GormModel(t *testing.T) {
+	structs, err := ParseSQL(baseSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	code := GenerateGoCode(structs, Config{AddGormTag: true, BaseStruct: gormModelType, AddRepository: true})
+
+	for _, expected := range []string{
+		"type Users struct {\n\tgorm.Model\n\tEmail string `gorm:\"column:email\"`\n}\n",
+		"row.ID = uint(id)",
+		"\"time\"\n\n\t\"gorm.io/gorm\"\n)",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, code)
+		}
+	}
+	// drafts has no deleted_at, so it keeps its fields
+	if !strings.Contains(code, "type Drafts struct {\n\tId ") {
+		t.Errorf("Expected drafts without gorm.Model, got:\n%s", code)
+	}
+
+	typeCheck(t, code, map[string]string{"gorm.io/gorm": `package gorm
+
+import (
+	"database/sql"
+	"time"
+)
+
+type DeletedAt sql.NullTime
+
+func (n *DeletedAt) Scan(value any) error { return (*sql.NullTime)(n).Scan(value) }
+
+type Model struct {
+	ID        uint
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt DeletedAt
+}
+`})
+}
+
+// TestGoCodeWarnings_BaseStruct tests the warnings saying why no base struct was extracted
+func TestGoCodeWarnings_BaseStruct(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		config   Config
+		expected string
+	}{
+		{
+			name:     "One shared column",
+			sql:      "CREATE TABLE users (id INT NOT NULL, email TEXT); CREATE TABLE posts (id INT NOT NULL, title TEXT);",
+			config:   Config{BaseStruct: "Base"},
+			expected: "no base struct Base extracted: no tables share more than one column with the same definition (shared: id)",
+		},
+		{
+			name:     "No shared column",
+			sql:      "CREATE TABLE users (id INT NOT NULL); CREATE TABLE posts (id BIGINT NOT NULL);",
+			config:   Config{BaseStruct: "Base"},
+			expected: "no base struct Base extracted: no column has the same definition in two tables",
+		},
+		{
+			name:     "Missing base columns",
+			sql:      baseSQL,
+			config:   Config{BaseStruct: "Base", BaseColumns: []string{"id", "version"}},
+			expected: "no base struct Base extracted: no table has all the base columns id, version",
+		},
+		{
+			name: "gorm.Model with nullable created_at",
+			sql: `CREATE TABLE users (
+				id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+				created_at DATETIME(3) NULL,
+				updated_at DATETIME(3) NULL,
+				deleted_at DATETIME(3) NULL
+			);
+			CREATE TABLE tags (name VARCHAR(20));`,
+			config:   Config{BaseStruct: gormModelType},
+			expected: "no base struct gorm.Model extracted: no table matches it (users: created_at is *time.Time, not time.Time)",
+		},
+		{
+			name:     "gorm.Model without its columns",
+			sql:      "CREATE TABLE tags (name VARCHAR(20));",
+			config:   Config{BaseStruct: gormModelType},
+			expected: "no base struct gorm.Model extracted: no table has the columns id, created_at, updated_at, deleted_at",
+		},
+		{
+			name:   "Extracted",
+			sql:    baseSQL,
+			config: Config{BaseStruct: gormModelType},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			structs, err := ParseSQL(tt.sql)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			warnings := GoCodeWarnings(structs, tt.config)
+			if strings.Join(warnings, "\n") != tt.expected {
+				t.Errorf("Expected warning %q, got %q", tt.expected, warnings)
+			}
+			if code := GenerateGoCode(structs, tt.config); tt.expected != "" && strings.Contains(code, "\tBase\n") {
+				t.Errorf("Expected no base struct, got:\n%s", code)
+			}
+		})
+	}
+}
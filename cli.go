@@ -0,0 +1,637 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Exit codes of the command-line interface
+const (
+	exitOK       = 0 // success
+	exitError    = 1 // parse or I/O error
+	exitUsage    = 2 // invalid command or flags
+	exitWarnings = 3 // code was generated, but some lines were skipped
+)
+
+// defaultAddr is the address the web server listens on when none is given
+const defaultAddr = ":7860"
+
+const usageText = `Usage:
+  sql-to-go generate [flags] [files...]   Generate Go structs from SQL
+  sql-to-go parse [flags] [files...]      Write the parsed schema as JSON
+  sql-to-go ddl [flags] [files...]        Generate CREATE TABLE DDL from Go structs
+  sql-to-go diff --old X --new Y [flags]  Compare two schemas and generate migrations
+  sql-to-go serve [--addr :7860]          Start the web interface and API
+
+Run "sql-to-go <command> -h" for the flags of a command.
+Without a command, sql-to-go starts the web server.
+`
+
+// stringList is a flag.Value collecting repeated flag values
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// runCLI runs the command line and returns the process exit code
+func runCLI(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		return runServe(nil, stderr)
+	}
+
+	switch args[0] {
+	case "generate", "gen":
+		return runGenerate(args[1:], stdin, stdout, stderr)
+	case "parse":
+		return runParse(args[1:], stdin, stdout, stderr)
+	case "ddl":
+		return runDDL(args[1:], stdin, stdout, stderr)
+	case "diff":
+		return runDiff(args[1:], stdout, stderr)
+	case "serve":
+		return runServe(args[1:], stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usageText)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "sql-to-go: unknown command %q\n\n%s", args[0], usageText)
+		return exitUsage
+	}
+}
+
+// runServe implements "sql-to-go serve"
+func runServe(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", defaultAddr, "address to listen on")
+	if err := fs.Parse(args); err != nil {
+		return flagExitCode(err)
+	}
+
+	if err := serve(*addr); err != nil {
+		fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// runGenerate implements "sql-to-go generate".
+// Settings come from the project configuration file, if any, overridden by flags.
+func runGenerate(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	configPath := fs.String("config", "", "project configuration file (default: sql-to-go.yaml, .yml or .json in the current directory)")
+	var inputs stringList
+	fs.Var(&inputs, "i", "input SQL file or glob (repeatable, default stdin)")
+	fs.Var(&inputs, "input", "alias for -i")
+	migrations := fs.String("migrations", "", "migrations directory (golang-migrate, goose or Flyway) to replay instead of -i")
+	database := fs.String("database", "", "database to introspect instead of -i: SQLite file, or MySQL/PostgreSQL DSN for --dialect")
+	schema := fs.String("schema", "", `schema JSON file written by "sql-to-go parse" to generate from instead of -i, or - for stdin`)
+	output := fs.String("o", "", "output directory (one file per table), single file with the extension of --target (.go, .proto, ...), or - for stdout")
+	fs.StringVar(output, "output", "", "alias for -o")
+	dialectName := fs.String("dialect", "mysql", "SQL dialect: mysql, postgres or sqlite")
+	target := fs.String("target", "go", "output: go (structs), ent (entgo.io schemas), proto (Protocol Buffers messages), typescript (interfaces), jsonschema, openapi, graphql or template (--template files)")
+	tags := fs.String("tags", "", "comma-separated struct tags: json,db,gorm,xml")
+	pkg := fs.String("package", "", `package name (default "main")`)
+	header := fs.Bool("header", false, `add the "Code generated ... DO NOT EDIT." header`)
+	buildTags := fs.String("build-tags", "", "build constraint expression for a //go:build line")
+	sourceHash := fs.Bool("source-hash", false, "add a source hash comment for staleness checks")
+	enumTypes := fs.Bool("enum-types", false, "generate named types for ENUM columns")
+	var templates stringList
+	fs.Var(&templates, "template", "text/template file or glob for --target template (repeatable)")
+	int64AsString := fs.Bool("int64-as-string", false, `encode int64 fields as JSON strings (json:",string")`)
+	jsonTypes := fs.Bool("json-types", false, "map JSON columns to a generated JSON[T] type implementing sql.Scanner and driver.Valuer")
+	jsonWrapper := fs.String("json-wrapper", "", `generic wrapper type for JSON columns instead of JSON[T], such as "gorm.io/datatypes.JSONType" (implies --json-types)`)
+	constructors := fs.Bool("constructors", false, "generate NewX functions returning rows with the literal DEFAULT values of their columns")
+	dtos := fs.Bool("dtos", false, "generate Create, Update and Response structs with conversion methods")
+	responseExclude := fs.String("response-exclude", "", "comma-separated columns or table.columns left out of Response structs (implies --dtos)")
+	baseStruct := fs.String("base-struct", "", `struct embedded by the tables for their shared columns, or "gorm.Model"`)
+	baseColumns := fs.String("base-columns", "", "comma-separated columns of --base-struct (default: the columns shared by the most tables)")
+	associations := fs.Bool("associations", false, "generate belongs-to, has-many and many-to-many fields for foreign keys")
+	repository := fs.Bool("repository", false, "generate Insert, Get, Update, Delete and List functions for database/sql")
+	sqlx := fs.Bool("sqlx", false, "generate sqlx named queries and Get/Select helpers (implies db tags)")
+	protoPackage := fs.String("proto-package", "", "package of .proto output (default --package)")
+	goPackage := fs.String("go-package", "", "go_package option of .proto output")
+	protoWrappers := fs.Bool("proto-wrappers", false, "use google.protobuf wrappers instead of optional for nullable columns")
+
+	if err := fs.Parse(args); err != nil {
+		return flagExitCode(err)
+	}
+
+	pc, err := loadCLIProjectConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+		return exitUsage
+	}
+
+	// Flags given on the command line override the configuration file.
+	// A source flag replaces the source of the file, so that conflicting flags are reported.
+	sourceSet := false
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "migrations", "database", "schema":
+			sourceSet = true
+		}
+	})
+	if sourceSet {
+		pc.Input = nil
+		pc.Migrations = ""
+		pc.Database = ""
+		pc.Schema = ""
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "migrations":
+			pc.Migrations = *migrations
+		case "database":
+			pc.Database = *database
+		case "schema":
+			pc.Schema = *schema
+		case "o", "output":
+			pc.Output = *output
+		case "dialect":
+			pc.Dialect = *dialectName
+		case "target":
+			pc.Target = *target
+		case "tags":
+			pc.Tags = strings.Split(*tags, ",")
+		case "package":
+			pc.Package = *pkg
+		case "header":
+			pc.Header = *header
+		case "build-tags":
+			pc.BuildTags = *buildTags
+		case "source-hash":
+			pc.SourceHash = *sourceHash
+		case "enum-types":
+			pc.EnumTypes = *enumTypes
+		case "int64-as-string":
+			pc.Int64AsString = *int64AsString
+		case "json-types":
+			pc.JSONTypes = *jsonTypes
+		case "json-wrapper":
+			pc.JSONWrapper = *jsonWrapper
+		case "constructors":
+			pc.Constructors = *constructors
+		case "dtos":
+			pc.DTOs = *dtos
+		case "response-exclude":
+			pc.ResponseExclude = strings.Split(*responseExclude, ",")
+		case "base-struct":
+			pc.BaseStruct = *baseStruct
+		case "base-columns":
+			pc.BaseColumns = strings.Split(*baseColumns, ",")
+		case "associations":
+			pc.Associations = *associations
+		case "repository":
+			pc.Repository = *repository
+		case "sqlx":
+			pc.SQLX = *sqlx
+		case "proto-package":
+			pc.ProtoPackage = *protoPackage
+		case "go-package":
+			pc.GoPackage = *goPackage
+		case "proto-wrappers":
+			pc.ProtoWrappers = *protoWrappers
+		}
+	})
+	if len(templates) > 0 {
+		pc.Templates = templates
+	}
+	inputs = append(inputs, fs.Args()...)
+	if len(inputs) > 0 {
+		pc.Input = inputs
+		if !sourceSet {
+			pc.Migrations = ""
+			pc.Database = ""
+			pc.Schema = ""
+		}
+	}
+	if err := pc.Validate(); err != nil {
+		fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+		return exitUsage
+	}
+
+	structs, warnings, err := loadSchema(pc, stdin)
+	for _, warning := range warnings {
+		fmt.Fprintf(stderr, "warning: %s\n", warning)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+		return exitError
+	}
+	structs = pc.Apply(structs)
+
+	gen, err := pc.Generator()
+	if err != nil {
+		fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+		return exitError
+	}
+	if gen, ok := gen.(WarningGenerator); ok {
+		generateWarnings := gen.Warnings(structs, pc.Config())
+		for _, warning := range generateWarnings {
+			fmt.Fprintf(stderr, "warning: %s\n", warning)
+		}
+		warnings = append(warnings, generateWarnings...)
+	}
+	if err := writeOutput(pc.Output, gen, structs, pc.Config(), stdout); err != nil {
+		fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+		return exitError
+	}
+
+	if len(warnings) > 0 {
+		return exitWarnings
+	}
+	return exitOK
+}
+
+// runParse implements "sql-to-go parse": it writes the schema parsed from SQL,
+// migrations or a database as schema JSON, which generate accepts with --schema
+func runParse(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("parse", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var inputs stringList
+	fs.Var(&inputs, "i", "input SQL file or glob (repeatable, default stdin)")
+	fs.Var(&inputs, "input", "alias for -i")
+	migrations := fs.String("migrations", "", "migrations directory (golang-migrate, goose or Flyway) to replay instead of -i")
+	database := fs.String("database", "", "database to introspect instead of -i: SQLite file, or MySQL/PostgreSQL DSN for --dialect")
+	output := fs.String("o", "", "output .json file, or - for stdout")
+	fs.StringVar(output, "output", "", "alias for -o")
+	dialectName := fs.String("dialect", "mysql", "SQL dialect: mysql, postgres or sqlite")
+
+	if err := fs.Parse(args); err != nil {
+		return flagExitCode(err)
+	}
+
+	pc := &ProjectConfig{
+		Dialect:    *dialectName,
+		Input:      append(inputs, fs.Args()...),
+		Migrations: *migrations,
+		Database:   *database,
+	}
+	if err := pc.Validate(); err != nil {
+		fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+		return exitUsage
+	}
+
+	structs, warnings, err := loadSchema(pc, stdin)
+	for _, warning := range warnings {
+		fmt.Fprintf(stderr, "warning: %s\n", warning)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+		return exitError
+	}
+
+	data, err := MarshalSchemaJSON(structs, pc.ParseOptions().Dialect)
+	if err == nil {
+		err = writeText(*output, string(data), stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+		return exitError
+	}
+
+	if len(warnings) > 0 {
+		return exitWarnings
+	}
+	return exitOK
+}
+
+// runDDL implements "sql-to-go ddl", the reverse of generate
+func runDDL(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("ddl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var inputs stringList
+	fs.Var(&inputs, "i", "input Go file or glob (repeatable, default stdin)")
+	fs.Var(&inputs, "input", "alias for -i")
+	output := fs.String("o", "", "output .sql file, or - for stdout")
+	fs.StringVar(output, "output", "", "alias for -o")
+	dialectName := fs.String("dialect", "mysql", "SQL dialect: mysql, postgres or sqlite")
+
+	if err := fs.Parse(args); err != nil {
+		return flagExitCode(err)
+	}
+	dialect, err := ParseDialect(*dialectName)
+	if err != nil {
+		fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+		return exitUsage
+	}
+
+	sources, err := readInputFiles(append(inputs, fs.Args()...), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+		return exitError
+	}
+
+	structs, warnings, err := ParseGoStructs(sources...)
+	for _, warning := range warnings {
+		fmt.Fprintf(stderr, "warning: %s\n", warning)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+		return exitError
+	}
+
+	if err := writeText(*output, GenerateDDL(structs, dialect), stdout); err != nil {
+		fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+		return exitError
+	}
+
+	if len(warnings) > 0 {
+		return exitWarnings
+	}
+	return exitOK
+}
+
+// runDiff implements "sql-to-go diff": it prints a report of the changes between
+// two schemas and writes the up and down migrations
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	oldPath := fs.String("old", "", "old schema: SQL file or migrations directory")
+	newPath := fs.String("new", "", "new schema: SQL file or migrations directory")
+	dialectName := fs.String("dialect", "mysql", "SQL dialect: mysql, postgres or sqlite")
+	upPath := fs.String("up", "", "file to write the up migration to")
+	downPath := fs.String("down", "", "file to write the down migration to")
+
+	if err := fs.Parse(args); err != nil {
+		return flagExitCode(err)
+	}
+	if *oldPath == "" || *newPath == "" || fs.NArg() > 0 {
+		fmt.Fprintln(stderr, "sql-to-go: diff requires --old and --new")
+		return exitUsage
+	}
+	dialect, err := ParseDialect(*dialectName)
+	if err != nil {
+		fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+		return exitUsage
+	}
+
+	opts := ParseOptions{Dialect: dialect}
+	var warnings []string
+	schemas := make([][]StructDef, 2)
+	for i, path := range []string{*oldPath, *newPath} {
+		structs, schemaWarnings, err := loadSchemaPath(path, opts)
+		warnings = append(warnings, schemaWarnings...)
+		if err != nil {
+			fmt.Fprintf(stderr, "sql-to-go: %s: %v\n", path, err)
+			return exitError
+		}
+		schemas[i] = structs
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(stderr, "warning: %s\n", warning)
+	}
+
+	if _, err := io.WriteString(stdout, DiffSchemas(schemas[0], schemas[1]).Report()); err != nil {
+		fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+		return exitError
+	}
+
+	up, down := GenerateMigration(schemas[0], schemas[1], dialect)
+	for _, migration := range []struct{ path, sql string }{{*upPath, up}, {*downPath, down}} {
+		if migration.path == "" {
+			continue
+		}
+		if err := writeText(migration.path, migration.sql, stdout); err != nil {
+			fmt.Fprintf(stderr, "sql-to-go: %v\n", err)
+			return exitError
+		}
+	}
+
+	if len(warnings) > 0 {
+		return exitWarnings
+	}
+	return exitOK
+}
+
+// loadSchemaPath parses a SQL file, or replays a migrations directory
+func loadSchemaPath(path string, opts ParseOptions) ([]StructDef, []string, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return ReplayMigrations(path, opts)
+	}
+
+	sql, err := readInputs([]string{path}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ParseSQLWithOptions(sql, opts)
+}
+
+// loadSchema parses the project's SQL input, replays its migrations directory,
+// introspects its database, or decodes its schema JSON. Without a configured
+// dialect, the dialect of the schema JSON is used.
+func loadSchema(pc *ProjectConfig, stdin io.Reader) ([]StructDef, []string, error) {
+	if pc.Schema != "" {
+		sources, err := readInputFiles([]string{pc.Schema}, stdin)
+		if err != nil {
+			return nil, nil, err
+		}
+		doc, err := DecodeSchemaDocument([]byte(sources[0]))
+		if err != nil {
+			return nil, nil, err
+		}
+		if pc.Dialect == "" {
+			pc.Dialect = doc.Dialect
+		}
+		structs, err := doc.StructDefs()
+		return structs, nil, err
+	}
+	if pc.Database != "" {
+		return Introspect(pc.Database, pc.ParseOptions().Dialect, pc.IntrospectOptions())
+	}
+	if pc.Migrations != "" {
+		return ReplayMigrations(pc.Migrations, pc.ParseOptions())
+	}
+
+	sql, err := readInputs(pc.Input, stdin)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ParseSQLWithOptions(sql, pc.ParseOptions())
+}
+
+// loadCLIProjectConfig loads the given configuration file, or the one in the current directory.
+// Paths in the file are made relative to the current directory.
+// Without a configuration file an empty one is returned.
+func loadCLIProjectConfig(path string) (*ProjectConfig, error) {
+	if path == "" {
+		path = FindProjectConfig(".")
+	}
+	if path == "" {
+		return &ProjectConfig{}, nil
+	}
+
+	pc, err := LoadProjectConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	for i, input := range pc.Input {
+		if input != "-" && !filepath.IsAbs(input) {
+			pc.Input[i] = filepath.Join(dir, input)
+		}
+	}
+	if pc.Migrations != "" && !filepath.IsAbs(pc.Migrations) {
+		pc.Migrations = filepath.Join(dir, pc.Migrations)
+	}
+	if pc.Schema != "" && pc.Schema != "-" && !filepath.IsAbs(pc.Schema) {
+		pc.Schema = filepath.Join(dir, pc.Schema)
+	}
+	// Only SQLite databases are files; other dialects take a DSN
+	if dialect, _ := ParseDialect(pc.Dialect); dialect == DialectSQLite && pc.Database != "" && !filepath.IsAbs(pc.Database) {
+		pc.Database = filepath.Join(dir, pc.Database)
+	}
+	for i, tmpl := range pc.Templates {
+		if !filepath.IsAbs(tmpl) {
+			pc.Templates[i] = filepath.Join(dir, tmpl)
+		}
+	}
+	if pc.Output != "" && pc.Output != "-" && !filepath.IsAbs(pc.Output) {
+		pc.Output = filepath.Join(dir, pc.Output)
+	}
+
+	return pc, nil
+}
+
+// flagExitCode maps a flag parsing error to an exit code
+func flagExitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	return exitUsage
+}
+
+// applyTagList enables the struct tags named in a comma-separated list
+func applyTagList(config *Config, list string) error {
+	for _, tag := range strings.Split(list, ",") {
+		switch strings.ToLower(strings.TrimSpace(tag)) {
+		case "":
+		case "json":
+			config.AddJSONTag = true
+		case "db":
+			config.AddDBTag = true
+		case "gorm":
+			config.AddGormTag = true
+		case "xml":
+			config.AddXMLTag = true
+		default:
+			return fmt.Errorf("unknown tag %q (expected json, db, gorm or xml)", tag)
+		}
+	}
+	return nil
+}
+
+// readInputs reads and concatenates the SQL from files and globs, or from stdin when none are given
+func readInputs(patterns []string, stdin io.Reader) (string, error) {
+	sources, err := readInputFiles(patterns, stdin)
+	if err != nil {
+		return "", err
+	}
+
+	// Keep statements from separate files apart even without a trailing semicolon
+	return strings.Join(sources, "\n;\n"), nil
+}
+
+// readInputFiles reads the files matching the globs, or stdin when none are given
+func readInputFiles(patterns []string, stdin io.Reader) ([]string, error) {
+	if len(patterns) == 0 || (len(patterns) == 1 && patterns[0] == "-") {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return []string{string(data)}, nil
+	}
+
+	var sources []string
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input pattern %q: %w", pattern, err)
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no input files match %q", pattern)
+		}
+
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, string(data))
+		}
+	}
+
+	return sources, nil
+}
+
+// writeOutput writes the files generated by gen to stdout, a single file with the
+// generator's extension (such as .go), or a directory with one file per table
+func writeOutput(output string, gen Generator, structs []StructDef, config Config, stdout io.Writer) error {
+	toStdout := output == "" || output == "-"
+	config.SingleFile = toStdout || (gen.Extension() != "" && strings.HasSuffix(output, gen.Extension()))
+
+	files, err := gen.Generate(structs, config)
+	if err != nil {
+		return err
+	}
+	if !config.SingleFile {
+		return writeFiles(output, files)
+	}
+
+	var content strings.Builder
+	for _, file := range files {
+		content.WriteString(file.Content)
+	}
+	if toStdout {
+		_, err := io.WriteString(stdout, content.String())
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(output, []byte(content.String()), 0o644)
+}
+
+// writeText writes generated DDL or JSON to stdout or a file
+func writeText(output, text string, stdout io.Writer) error {
+	if output == "" || output == "-" {
+		_, err := io.WriteString(stdout, text)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(output, []byte(text), 0o644)
+}
+
+// writeFiles writes multi-file output into dir, creating it and subdirectories if needed
+func writeFiles(dir string, files []File) error {
+	for _, file := range files {
+		path := filepath.Join(dir, file.Name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(file.Content), 0o644); err != nil {
+			return err
+		}
+	}
+	return os.MkdirAll(dir, 0o755)
+}
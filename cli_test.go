@@ -0,0 +1,356 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLIGenerate_StdinToStdout(t *testing.T) {
+	stdin := strings.NewReader("CREATE TABLE users (id INT NOT NULL, email VARCHAR(255))")
+	var stdout, stderr bytes.Buffer
+
+	code := runCLI([]string{"generate", "--tags", "json,db", "--package", "models"}, stdin, &stdout, &stderr)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+
+	out := stdout.String()
+	if !strings.HasPrefix(out, "package models\n") {
+		t.Errorf("Expected package models, got:\n%s", out)
+	}
+	if !strings.Contains(out, `json:"email" db:"email"`) {
+		t.Errorf("Expected json and db tags, got:\n%s", out)
+	}
+}
+
+func TestCLIGenerate_GlobToDirectory(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "001_users.sql"), []byte("CREATE TABLE users (id INT NOT NULL)"), 0o644)
+	os.WriteFile(filepath.Join(dir, "002_orders.sql"), []byte("CREATE TABLE order_items (id INT NOT NULL)"), 0o644)
+	outDir := filepath.Join(dir, "models")
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"generate", "-i", filepath.Join(dir, "*.sql"), "-o", outDir}, nil, &stdout, &stderr)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+
+	for _, name := range []string{"users.go", "order_items.go"} {
+		if _, err := os.Stat(filepath.Join(outDir, name)); err != nil {
+			t.Errorf("Expected %s to be written: %v", name, err)
+		}
+	}
+}
+
+func TestCLIGenerate_Target(t *testing.T) {
+	dir := t.TempDir()
+	stdin := strings.NewReader("CREATE TABLE users (id INT NOT NULL AUTO_INCREMENT PRIMARY KEY, email VARCHAR(255) NOT NULL)")
+	var stdout, stderr bytes.Buffer
+
+	code := runCLI([]string{"generate", "--target", "ent", "-o", filepath.Join(dir, "schema")}, stdin, &stdout, &stderr)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "schema", "users.go"))
+	if err != nil {
+		t.Fatalf("Expected users.go to be written: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "package schema\n") || !strings.Contains(string(data), `field.String("email").MaxLen(255),`) {
+		t.Errorf("Expected an ent schema, got:\n%s", data)
+	}
+}
+
+func TestCLIGenerate_ProtoFile(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "users.proto")
+	stdin := strings.NewReader("CREATE TABLE users (id INT NOT NULL AUTO_INCREMENT PRIMARY KEY, email VARCHAR(255))")
+	var stdout, stderr bytes.Buffer
+
+	code := runCLI([]string{"generate", "--target", "proto", "--proto-package", "acme.v1", "--go-package", "example.com/pb", "--proto-wrappers", "-o", output}, stdin, &stdout, &stderr)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Expected users.proto to be written: %v", err)
+	}
+	for _, want := range []string{"package acme.v1;", `option go_package = "example.com/pb";`, "google.protobuf.StringValue email = 2;"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %q in:\n%s", want, data)
+		}
+	}
+}
+
+func TestCLIGenerate_TypeScript(t *testing.T) {
+	stdin := strings.NewReader("CREATE TABLE users (id BIGINT NOT NULL PRIMARY KEY, email VARCHAR(255))")
+	var stdout, stderr bytes.Buffer
+
+	code := runCLI([]string{"generate", "--target", "typescript", "--int64-as-string"}, stdin, &stdout, &stderr)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "export interface Users {\n  id: string;\n  email: string | null;\n}") {
+		t.Errorf("Unexpected output:\n%s", stdout.String())
+	}
+}
+
+func TestCLIGenerate_OpenAPIDirectory(t *testing.T) {
+	dir := t.TempDir()
+	stdin := strings.NewReader("CREATE TABLE users (id INT NOT NULL PRIMARY KEY); CREATE TABLE posts (id INT NOT NULL PRIMARY KEY)")
+	var stdout, stderr bytes.Buffer
+
+	code := runCLI([]string{"generate", "--target", "openapi", "-o", dir}, stdin, &stdout, &stderr)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "openapi.json"))
+	if err != nil {
+		t.Fatalf("Expected openapi.json to be written: %v", err)
+	}
+	if !strings.Contains(string(data), `"Users": {`) || !strings.Contains(string(data), `"Posts": {`) {
+		t.Errorf("Expected both schemas in one document, got:\n%s", data)
+	}
+}
+
+func TestCLIGenerate_Template(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := writeTestFile(t, dir, "{{snake .Table.Name}}.txt.tmpl", "{{.Table.TableName}}: {{len .Table.Fields}} columns\n")
+	stdin := strings.NewReader("CREATE TABLE users (id INT, email TEXT); CREATE TABLE posts (id INT)")
+	var stdout, stderr bytes.Buffer
+
+	code := runCLI([]string{"generate", "--template", tmpl, "-o", filepath.Join(dir, "out")}, stdin, &stdout, &stderr)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "out", "users.txt"))
+	if err != nil || string(data) != "users: 2 columns\n" {
+		t.Errorf("Expected users.txt from the template, got %q (%v)", data, err)
+	}
+}
+
+func TestCLIGenerate_ExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		sql      string
+		expected int
+	}{
+		{"Parse error", []string{"generate"}, "not sql", exitError},
+		{"Warnings", []string{"generate"}, "CREATE TABLE users (id INT NOT NULL, bogus)", exitWarnings},
+		{"Base struct warnings", []string{"generate", "--base-struct", "Base"}, "CREATE TABLE users (id INT NOT NULL, email TEXT); CREATE TABLE posts (id INT NOT NULL, title TEXT)", exitWarnings},
+		{"Constructor warnings", []string{"generate", "--constructors"}, "CREATE TABLE users (id INT NOT NULL DEFAULT 1, created_at DATETIME DEFAULT NOW())", exitWarnings},
+		{"Unknown dialect", []string{"generate", "--dialect", "oracle"}, "", exitUsage},
+		{"Unknown tag", []string{"generate", "--tags", "yaml"}, "", exitUsage},
+		{"Unknown target", []string{"generate", "--target", "cobol"}, "", exitUsage},
+		{"Template target without templates", []string{"generate", "--target", "template"}, "", exitUsage},
+		{"Missing template", []string{"generate", "--template", "missing.tmpl"}, "CREATE TABLE t (id INT)", exitError},
+		{"Missing input", []string{"generate", "-i", "does-not-exist.sql"}, "", exitError},
+		{"Unknown command", []string{"frobnicate"}, "", exitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := runCLI(tt.args, strings.NewReader(tt.sql), &stdout, &stderr)
+			if code != tt.expected {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.expected, code, stderr.String())
+			}
+		})
+	}
+}
+
+func TestCLIGenerate_ConstructorWarnings(t *testing.T) {
+	stdin := strings.NewReader("CREATE TABLE users (id INT NOT NULL, status VARCHAR(10) NOT NULL DEFAULT 'new', created_at DATETIME DEFAULT NOW())")
+	var stdout, stderr bytes.Buffer
+
+	code := runCLI([]string{"generate", "--constructors"}, stdin, &stdout, &stderr)
+
+	if code != exitWarnings {
+		t.Fatalf("Expected exit code %d, got %d (stderr: %s)", exitWarnings, code, stderr.String())
+	}
+	if expected := "warning: users.created_at: DEFAULT NOW() has no Go literal and is left out of the constructor\n"; stderr.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "func NewUsers() Users {") {
+		t.Errorf("Expected the constructor to be generated, got:\n%s", stdout.String())
+	}
+}
+
+func TestCLIGenerate_ProjectConfig(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "schema.sql"), []byte("CREATE TABLE users (user_id INT NOT NULL)"), 0o644)
+	configPath := filepath.Join(dir, "sql-to-go.yaml")
+	os.WriteFile(configPath, []byte("input: [schema.sql]\noutput: models\npackage: models\ntags: [json]\nnaming:\n  initialisms: true\n  singular: true\n"), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"generate", "--config", configPath}, nil, &stdout, &stderr)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "models", "users.go"))
+	if err != nil {
+		t.Fatalf("Expected models/users.go: %v", err)
+	}
+	if !strings.Contains(string(data), "package models") || !strings.Contains(string(data), `UserID int `+"`"+`json:"user_id"`) {
+		t.Errorf("Unexpected output:\n%s", data)
+	}
+
+	// Flags override the configuration file
+	stdout.Reset()
+	code = runCLI([]string{"generate", "--config", configPath, "-o", "-", "--package", "db"}, nil, &stdout, &stderr)
+	if code != exitOK || !strings.HasPrefix(stdout.String(), "package db\n") {
+		t.Errorf("Expected flags to override the config, got exit %d:\n%s", code, stdout.String())
+	}
+}
+
+func TestCLIGenerate_Migrations(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "1_users.up.sql"), []byte("CREATE TABLE users (id INT NOT NULL)"), 0o644)
+	os.WriteFile(filepath.Join(dir, "2_email.up.sql"), []byte("ALTER TABLE users ADD COLUMN email TEXT"), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"generate", "--migrations", dir}, nil, &stdout, &stderr)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Email *string") {
+		t.Errorf("Expected the replayed column, got:\n%s", stdout.String())
+	}
+
+	code = runCLI([]string{"generate", "--migrations", dir, "-i", "schema.sql"}, nil, &stdout, &stderr)
+	if code != exitUsage {
+		t.Errorf("Expected exit code %d for -i with --migrations, got %d", exitUsage, code)
+	}
+}
+
+func TestCLIGenerate_Database(t *testing.T) {
+	path := createSQLiteDatabase(t, introspectSQL)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"generate", "--dialect", "sqlite", "--database", path}, nil, &stdout, &stderr)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+	for _, expected := range []string{"type Users struct", "type AuditLog struct", "CouponId"} {
+		if !strings.Contains(stdout.String(), expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, stdout.String())
+		}
+	}
+
+	code = runCLI([]string{"generate", "--database", "root@tcp(127.0.0.1:1)/app"}, nil, &stdout, &stderr)
+	if code != exitError || !strings.Contains(stderr.String(), "failed to connect to database") {
+		t.Errorf("Expected exit code %d for an unreachable MySQL server, got %d (stderr: %s)", exitError, code, stderr.String())
+	}
+}
+
+func TestCLIParse_GenerateFromSchema(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "schema.json")
+	stdin := strings.NewReader("CREATE TABLE users (id SERIAL PRIMARY KEY, email TEXT)")
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"parse", "--dialect", "postgres", "-o", schemaPath}, stdin, &stdout, &stderr)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		t.Fatalf("Expected schema.json: %v", err)
+	}
+	if !strings.Contains(string(data), `"dialect": "postgres"`) {
+		t.Errorf("Expected the dialect in the schema JSON, got:\n%s", data)
+	}
+
+	// The dialect of the schema JSON selects the placeholders of the repository
+	code = runCLI([]string{"generate", "--schema", schemaPath, "--repository"}, nil, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+	for _, expected := range []string{"type Users struct", "Email *string", "$1"} {
+		if !strings.Contains(stdout.String(), expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, stdout.String())
+		}
+	}
+
+	code = runCLI([]string{"generate", "--schema", schemaPath, "--migrations", dir}, nil, &stdout, &stderr)
+	if code != exitUsage {
+		t.Errorf("Expected exit code %d for --schema with --migrations, got %d", exitUsage, code)
+	}
+	code = runCLI([]string{"generate", "--schema", "-"}, strings.NewReader(`{"version": 2}`), &stdout, &stderr)
+	if code != exitError {
+		t.Errorf("Expected exit code %d for an unsupported schema version, got %d", exitError, code)
+	}
+}
+
+func TestCLIDDL(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "user.go"), []byte("package models\n\ntype User struct {\n\tID int\n\tName string\n}\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "order.go"), []byte("package models\n\ntype Order struct {\n\tID int\n\tUserID int\n\tUser *User\n}\n"), 0o644)
+	output := filepath.Join(dir, "schema.sql")
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"ddl", "--dialect", "sqlite", "-i", filepath.Join(dir, "*.go"), "-o", output}, nil, &stdout, &stderr)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Expected %s to be written: %v", output, err)
+	}
+	ddl := string(data)
+	for _, expected := range []string{"CREATE TABLE \"order\"", "CREATE TABLE \"user\"", "FOREIGN KEY (user_id) REFERENCES \"user\" (id)"} {
+		if !strings.Contains(ddl, expected) {
+			t.Errorf("Expected %q in DDL, got:\n%s", expected, ddl)
+		}
+	}
+}
+
+func TestCLIDiff(t *testing.T) {
+	dir := t.TempDir()
+	oldPath, newPath := filepath.Join(dir, "old.sql"), filepath.Join(dir, "new.sql")
+	os.WriteFile(oldPath, []byte("CREATE TABLE users (id INT NOT NULL PRIMARY KEY);"), 0o644)
+	os.WriteFile(newPath, []byte("CREATE TABLE users (id INT NOT NULL PRIMARY KEY, name TEXT NOT NULL);"), 0o644)
+	upPath, downPath := filepath.Join(dir, "up.sql"), filepath.Join(dir, "down.sql")
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"diff", "--old", oldPath, "--new", newPath, "--dialect", "postgres", "--up", upPath, "--down", downPath}, nil, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+
+	if expected := "~ table users\n    + column name TEXT NOT NULL\n"; stdout.String() != expected {
+		t.Errorf("Expected report %q, got %q", expected, stdout.String())
+	}
+	for path, expected := range map[string]string{
+		upPath:   "ALTER TABLE users ADD COLUMN name TEXT NOT NULL;\n",
+		downPath: "ALTER TABLE users DROP COLUMN name;\n",
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Expected %s to be written: %v", path, err)
+		}
+		if string(data) != expected {
+			t.Errorf("Expected %q in %s, got %q", expected, filepath.Base(path), data)
+		}
+	}
+
+	if code := runCLI([]string{"diff", "--old", oldPath}, nil, &stdout, &stderr); code != exitUsage {
+		t.Errorf("Expected exit code %d without --new, got %d", exitUsage, code)
+	}
+}
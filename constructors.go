@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// untypedLiteralTypes are the types Go gives to the untyped literals of goDefaultLiteral,
+// which need no conversion when assigned to a variable
+var untypedLiteralTypes = map[string]bool{"string": true, "bool": true, "int": true}
+
+// generateConstructor generates the NewX function returning a row with the literal
+// DEFAULT values of a table's columns, or "" when the table has none. Defaults without
+// a Go literal, such as NOW(), are left to the database: they are noted in the doc
+// comment and returned as warnings.
+func generateConstructor(def StructDef) (string, []string) {
+	var fields []FieldDef
+	var values, skipped, pointers, warnings []string
+	for _, field := range def.Fields {
+		if field.Association != "" || field.Default == "" {
+			continue
+		}
+		literal, ok := constructorLiteral(def, field)
+		if !ok {
+			skipped = append(skipped, fmt.Sprintf("// %s is left to the database: DEFAULT %s has no Go literal.\n", field.Name, field.Default))
+			warnings = append(warnings, fmt.Sprintf("%s.%s: DEFAULT %s has no Go literal and is left out of the constructor", def.TableName, field.ColumnName, field.Default))
+			continue
+		}
+
+		// Pointer fields point to a local variable of the field's type
+		if strings.HasPrefix(field.Type, "*") {
+			name := paramName(field.Name)
+			if baseType := field.Type[1:]; !untypedLiteralTypes[baseType] && !isEnumConstant(def, field, literal) {
+				literal = fmt.Sprintf("%s(%s)", baseType, literal)
+			}
+			pointers = append(pointers, fmt.Sprintf("\t%s := %s\n", name, literal))
+			literal = "&" + name
+		}
+		fields = append(fields, field)
+		values = append(values, literal)
+	}
+	if len(values) == 0 {
+		return "", warnings
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("// New%s returns the %s row with the DEFAULT values of its columns", def.Name, def.TableName))
+	if len(skipped) > 0 {
+		output.WriteString(".")
+	}
+	output.WriteString("\n")
+	for _, line := range skipped {
+		output.WriteString(line)
+	}
+	output.WriteString(fmt.Sprintf("func New%s() %s {\n", def.Name, def.Name))
+	for _, line := range pointers {
+		output.WriteString(line)
+	}
+	output.WriteString(generateReturnLiteral(def.Name, fields, values))
+	output.WriteString("}\n")
+	return output.String(), warnings
+}
+
+// constructorLiteral returns the Go literal of a field's DEFAULT. ENUM fields of a
+// named enum type use its constant for the value.
+func constructorLiteral(def StructDef, field FieldDef) (string, bool) {
+	if len(field.EnumValues) == 0 {
+		return goDefaultLiteral(field)
+	}
+
+	enumType := enumTypeName(def, field)
+	if strings.TrimPrefix(field.Type, "*") != enumType {
+		return goDefaultLiteral(field)
+	}
+	field.Type = "string"
+	literal, ok := goDefaultLiteral(field)
+	if !ok {
+		return "", false
+	}
+	for _, value := range field.EnumValues {
+		if fmt.Sprintf("%q", value) == literal {
+			return enumType + enumConstSuffix(value), true
+		}
+	}
+	// A default outside the ENUM values converts to the enum type
+	return literal, true
+}
+
+// isEnumConstant reports whether literal is a constant of the field's enum type
+func isEnumConstant(def StructDef, field FieldDef, literal string) bool {
+	return len(field.EnumValues) > 0 && strings.HasPrefix(literal, enumTypeName(def, field))
+}
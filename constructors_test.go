@@ -0,0 +1,101 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// constructorsSQL has literal, function and pointer defaults
+const constructorsSQL = `
+CREATE TABLE orders (
+	id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	status ENUM('pending','paid') NOT NULL DEFAULT 'pending',
+	quantity SMALLINT NOT NULL DEFAULT 1,
+	paid BOOLEAN NOT NULL DEFAULT FALSE,
+	note VARCHAR(255) DEFAULT 'n/a',
+	discount DECIMAL(5,2) DEFAULT 0,
+	rank TINYINT DEFAULT '3',
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	code CHAR(36) NOT NULL DEFAULT (uuid())
+);
+CREATE TABLE tags (id INT NOT NULL PRIMARY KEY, name TEXT);`
+
+// TestGenerateConstructor tests the NewX function of a table with literal and function defaults
+func TestGenerateConstructor(t *testing.T) {
+	structs, err := ParseSQL(constructorsSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := `// NewOrders returns the orders row with the DEFAULT values of its columns.
+// CreatedAt is left to the database: DEFAULT CURRENT_TIMESTAMP has no Go literal.
+// Code is left to the database: DEFAULT (uuid()) has no Go literal.
+func NewOrders() Orders {
+	note := "n/a"
+	discount := float64(0)
+	rank := int8(3)
+	return Orders{
+		Status:   "pending",
+		Quantity: 1,
+		Paid:     false,
+		Note:     &note,
+		Discount: &discount,
+		Rank:     &rank,
+	}
+}
+`
+	code, warnings := generateConstructor(structs[0])
+	if code != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, code)
+	}
+	expectedWarnings := []string{
+		"orders.created_at: DEFAULT CURRENT_TIMESTAMP has no Go literal and is left out of the constructor",
+		"orders.code: DEFAULT (uuid()) has no Go literal and is left out of the constructor",
+	}
+	if strings.Join(warnings, "\n") != strings.Join(expectedWarnings, "\n") {
+		t.Errorf("Expected warnings %q, got %q", expectedWarnings, warnings)
+	}
+	if warnings := GoCodeWarnings(structs, Config{AddConstructors: true}); len(warnings) != 2 {
+		t.Errorf("Expected 2 generation warnings, got %q", warnings)
+	}
+
+	// Tables without literal defaults get no constructor
+	if code, warnings := generateConstructor(structs[1]); code != "" || len(warnings) != 0 {
+		t.Errorf("Expected no constructor and no warnings, got %q:\n%s", warnings, code)
+	}
+
+	code = GenerateGoCode(structs, Config{AddConstructors: true})
+	if !strings.Contains(code, "}\n\n// NewOrders returns") || strings.Contains(code, "NewTags") {
+		t.Errorf("Expected NewOrders after its struct only, got:\n%s", code)
+	}
+	typeCheck(t, code, nil)
+}
+
+// TestGenerateConstructor_EnumTypes tests that ENUM defaults use the enum constants
+func TestGenerateConstructor_EnumTypes(t *testing.T) {
+	structs, err := ParseSQL(`CREATE TABLE orders (
+		status ENUM('pending','paid') NOT NULL DEFAULT 'pending',
+		previous ENUM('pending','paid') DEFAULT 'paid',
+		legacy ENUM('a','b') DEFAULT 'c'
+	);`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	code := GenerateGoCode(structs, Config{AddConstructors: true, AddEnumTypes: true})
+	for _, expected := range []string{
+		"\tprevious := OrdersPreviousPaid\n",
+		"\tlegacy := OrdersLegacy(\"c\")\n",
+		"\t\tStatus:   OrdersStatusPending,\n",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, code)
+		}
+	}
+	typeCheck(t, code, nil)
+
+	files := GenerateGoFiles(structs, Config{AddConstructors: true, AddEnumTypes: true})
+	if !strings.Contains(files["orders.go"], "func NewOrders() Orders {") {
+		t.Errorf("Expected NewOrders in orders.go, got:\n%s", files["orders.go"])
+	}
+}
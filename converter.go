@@ -1,18 +1,30 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strings"
 )
 
 // Pre-compiled regex patterns for better performance
 var (
-	tableNameRegex   = regexp.MustCompile(`(?i)CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?[` + "`" + `"']?([a-zA-Z0-9_]+)[` + "`" + `"']?\s*\(`)
+	tableNameRegex   = regexp.MustCompile(`(?i)CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?` + identPattern + `\s*\(`)
 	columnBlockRegex = regexp.MustCompile(`\(([\s\S]+)\)\s*(?:ENGINE|DEFAULT|AUTO_INCREMENT|COMMENT|;|$)`)
-	typeRegex        = regexp.MustCompile(`(?i)^(TINYINT|SMALLINT|MEDIUMINT|INT|INTEGER|BIGINT|FLOAT|DOUBLE|DECIMAL|NUMERIC|CHAR|VARCHAR|TEXT|TINYTEXT|MEDIUMTEXT|LONGTEXT|DATETIME|TIMESTAMP|DATE|TIME|BOOLEAN|BOOL|BLOB|TINYBLOB|MEDIUMBLOB|LONGBLOB|JSON|ENUM|SET)(?:\s*\(([^)]+)\))?(?:\s+(UNSIGNED))?`)
+	typeRegex        = regexp.MustCompile(`(?i)^(CHARACTER\s+VARYING|DOUBLE\s+PRECISION|TINYINT|SMALLINT|MEDIUMINT|INT|INTEGER|BIGINT|INT2|INT4|INT8|SMALLSERIAL|SERIAL|BIGSERIAL|FLOAT|FLOAT4|FLOAT8|REAL|DOUBLE|DECIMAL|NUMERIC|CHAR|CHARACTER|VARCHAR|TEXT|TINYTEXT|MEDIUMTEXT|LONGTEXT|CITEXT|UUID|DATETIME|TIMESTAMP|TIMESTAMPTZ|DATE|TIME|TIMETZ|BOOLEAN|BOOL|BLOB|TINYBLOB|MEDIUMBLOB|LONGBLOB|BYTEA|JSON|JSONB|ENUM|SET)\b(?:\s*\(([^)]+)\))?(?:\s+(UNSIGNED))?`)
 	notNullRegex     = regexp.MustCompile(`(?i)\bNOT\s+NULL\b`)
+	commentRegex     = regexp.MustCompile(`(?i)\bCOMMENT\s+'((?:[^']|'')*)'`)
+
+	// Primary keys and auto-increment columns
+	primaryKeyRegex       = regexp.MustCompile(`(?i)^(?:CONSTRAINT\s+` + identPattern + `\s+)?PRIMARY\s+KEY\s*(?:USING\s+\w+\s*)?\(([^)]+)\)`)
+	inlinePrimaryKeyRegex = regexp.MustCompile(`(?i)\bPRIMARY\s+KEY\b`)
+	autoIncrementRegex    = regexp.MustCompile(`(?i)\b(?:AUTO_INCREMENT|AUTOINCREMENT|GENERATED\s+(?:ALWAYS|BY\s+DEFAULT)\s+AS\s+IDENTITY)\b`)
+	// SQLite table option of tables without a rowid, after the column block
+	withoutRowIDRegex = regexp.MustCompile(`(?i)\)[^)]*\bWITHOUT\s+ROWID\b[^)]*$`)
 )
 
 // Config controls the code generation output
@@ -21,23 +33,121 @@ type Config struct {
 	AddGormTag bool // Add gorm:"column:field_name" tags
 	AddXMLTag  bool // Add xml:"field_name" tags
 	AddDBTag   bool // Add db:"field_name" tags (for sqlx)
+
+	PackageName   string // Package clause of the generated file (defaults to "main")
+	AddHeader     bool   // Add the "Code generated ... DO NOT EDIT." header
+	BuildTags     string // Build constraint expression for a //go:build line (e.g. "integration")
+	AddSourceHash bool   // Add a comment with the hash of the parsed schema
+	AddEnumTypes  bool   // Generate named string types with constants for ENUM columns
+	AddJSONTypes  bool   // Map JSON and JSONB columns to the generic JSON[T] type implementing sql.Scanner and driver.Valuer
+	JSONWrapper   string // Generic wrapper type used instead of JSON[T], qualified by import path (e.g. "gorm.io/datatypes.JSONType")
+
+	AddConstructors bool     // Generate NewX functions returning a row with the literal DEFAULT values of its columns
+	AddDTOs         bool     // Generate XCreate, XUpdate and XResponse structs with conversion methods
+	ResponseExclude []string // Columns left out of XResponse structs, by column name or table.column (e.g. "password_hash")
+
+	BaseStruct  string   // Struct embedded by the tables for their shared columns instead of repeating them, or "gorm.Model"
+	BaseColumns []string // Columns of BaseStruct (default: detected, the columns shared by the most tables)
+
+	AddAssociations bool // Add belongs-to, has-many and many-to-many fields for foreign keys
+
+	AddRepository bool    // Generate Insert, Get, Update, Delete and List functions for database/sql
+	AddSQLX       bool    // Generate sqlx named queries and Get/Select helpers instead (implies db tags)
+	Dialect       Dialect // Dialect of the repository queries: placeholders and quoting (defaults to MySQL)
+
+	ProtoPackage  string // Package of generated .proto files (defaults to PackageName)
+	GoPackage     string // go_package option of generated .proto files (e.g. "example.com/app/pb")
+	ProtoWrappers bool   // Use google.protobuf wrapper messages instead of optional for nullable columns
+
+	Int64AsString bool // Encode int64 and uint64 fields as JSON strings (json:",string"), typed as string in TypeScript
+
+	GraphQLScalars map[string]string // Custom GraphQL scalars by SQL type or table.column (e.g. "JSONB": "JSON")
+
+	SingleFile bool // Generator output in a single file instead of one file per table
 }
 
+// generatedHeader is the standard marker recognized by Go tooling for generated files
+// (see https://go.dev/s/generatedcode)
+const generatedHeader = "// Code generated by sql-to-go; DO NOT EDIT."
+
+// sourceHashPrefix starts the comment line that records the schema hash
+const sourceHashPrefix = "// sql-to-go source hash: "
+
 // StructDef represents the definition of a Go struct
 type StructDef struct {
-	Name   string     // Struct name in PascalCase
-	Fields []FieldDef // List of struct fields
+	Name        string       // Struct name in PascalCase
+	TableName   string       // Original table name from SQL
+	Fields      []FieldDef   // List of struct fields
+	ForeignKeys []ForeignKey // FOREIGN KEY constraints and inline REFERENCES clauses
+	Indexes     []Index      // Indexes and UNIQUE constraints
 }
 
 // FieldDef represents a single field in a struct
 type FieldDef struct {
-	Name       string // Field name in PascalCase
-	Type       string // Go type (e.g., "string", "*int", "time.Time")
-	ColumnName string // Original column name from SQL (snake_case)
+	Name       string   // Field name in PascalCase
+	Type       string   // Go type (e.g., "string", "*int", "time.Time")
+	TypeImport string   // Import path required by Type, if any (set by type overrides)
+	ColumnName string   // Original column name from SQL (snake_case)
+	SQLType    string   // SQL data type in upper case (e.g., "VARCHAR", "BIGINT")
+	Size       string   // Type arguments such as length or precision (e.g., "255", "10,2")
+	Nullable   bool     // Column accepts NULL
+	Unsigned   bool     // Integer column is UNSIGNED
+	EnumValues []string // Allowed values of an ENUM column
+	Comment    string   // Column comment (COMMENT '...' or COMMENT ON COLUMN)
+	Default    string   // DEFAULT expression as written (e.g. "'pending'", "0", "CURRENT_TIMESTAMP")
+	OnUpdate   string   // ON UPDATE expression of a MySQL column (e.g. "CURRENT_TIMESTAMP")
+
+	Generated       string // Expression of a generated column (GENERATED ALWAYS AS (expr) or AS (expr))
+	GeneratedStored bool   // Generated column is STORED rather than VIRTUAL
+
+	PrimaryKey    bool // Column is (part of) the primary key
+	AutoIncrement bool // Column is AUTO_INCREMENT, SERIAL or an identity column
+
+	// Association holds the GORM settings of a relation field such as
+	// "foreignKey:UserID;references:ID". Relation fields have no ColumnName.
+	Association string
+
+	// Embedded is the base struct declaring the field, which is embedded in the
+	// table's struct instead (see Config.BaseStruct)
+	Embedded string
+}
+
+// ParseOptions controls how SQL is parsed
+type ParseOptions struct {
+	Dialect Dialect // SQL dialect of the input (defaults to MySQL)
 }
 
-// ParseSQL parses a MySQL CREATE TABLE statement and converts it to Go struct definitions
+// ParseSQL parses MySQL CREATE TABLE statements and converts them to Go struct definitions.
+// The input may hold several statements separated by semicolons; one struct is
+// returned per table, in order of creation.
 func ParseSQL(sql string) ([]StructDef, error) {
+	structs, warnings, err := ParseSQLWithOptions(sql, ParseOptions{})
+	for _, warning := range warnings {
+		log.Printf("Warning: %s", warning)
+	}
+	return structs, err
+}
+
+// ParseSQLWithOptions parses CREATE TABLE statements like ParseSQL for the given dialect.
+// ALTER TABLE, RENAME TABLE and DROP TABLE statements that follow are applied to the
+// parsed tables, so the result reflects the final schema.
+// Lines that cannot be parsed as columns are skipped and reported as warnings
+// instead of being logged.
+func ParseSQLWithOptions(sql string, opts ParseOptions) ([]StructDef, []string, error) {
+	schema := NewSchema(opts)
+	if err := schema.ApplySQL(sql); err != nil {
+		return nil, schema.Warnings(), err
+	}
+
+	if len(schema.Tables()) == 0 {
+		return nil, schema.Warnings(), fmt.Errorf("failed to extract table name from SQL")
+	}
+
+	return schema.Tables(), schema.Warnings(), nil
+}
+
+// parseCreateTable parses a single CREATE TABLE statement into a struct definition
+func parseCreateTable(sql string, opts ParseOptions) (StructDef, []string, error) {
 	// Clean up the SQL string - normalize whitespace
 	sql = strings.TrimSpace(sql)
 	sql = normalizeWhitespace(sql)
@@ -45,7 +155,7 @@ func ParseSQL(sql string) ([]StructDef, error) {
 	// Extract table name using pre-compiled regex
 	matches := tableNameRegex.FindStringSubmatch(sql)
 	if len(matches) < 2 {
-		return nil, fmt.Errorf("failed to extract table name from SQL")
+		return StructDef{}, nil, fmt.Errorf("failed to extract table name from SQL")
 	}
 
 	tableName := matches[1]
@@ -58,38 +168,98 @@ func ParseSQL(sql string) ([]StructDef, error) {
 		// Fallback: try simple parentheses matching
 		start := strings.Index(sql, "(")
 		if start == -1 {
-			return nil, fmt.Errorf("failed to extract column definitions")
+			return StructDef{}, nil, fmt.Errorf("failed to extract column definitions")
 		}
 		// Find matching closing parenthesis
 		end := findMatchingParen(sql, start)
 		if end == -1 {
-			return nil, fmt.Errorf("failed to find closing parenthesis")
+			return StructDef{}, nil, fmt.Errorf("failed to find closing parenthesis")
 		}
 		columnMatches = []string{"", sql[start+1 : end]}
 	}
 	if len(columnMatches) < 2 {
-		return nil, fmt.Errorf("failed to extract column definitions")
+		return StructDef{}, nil, fmt.Errorf("failed to extract column definitions")
 	}
 
 	columnBlock := columnMatches[1]
 
 	// Parse individual columns
-	fields, err := parseColumns(columnBlock)
+	structDef, warnings, err := parseColumns(columnBlock, opts.Dialect)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse columns: %w", err)
+		return StructDef{}, warnings, fmt.Errorf("failed to parse columns of table %s: %w", tableName, err)
 	}
+	structDef.Name = structName
+	structDef.TableName = tableName
+	if opts.Dialect == DialectSQLite && !withoutRowIDRegex.MatchString(sql) {
+		setSQLiteRowID(&structDef)
+	}
+
+	return structDef, warnings, nil
+}
+
+// splitStatements splits a SQL script into statements on top-level semicolons.
+// SQL comments are dropped and semicolons inside quoted strings are ignored.
+func splitStatements(sql string) []string {
+	var result []string
+	var current strings.Builder
+	var quote byte
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+
+		if quote != 0 {
+			current.WriteByte(c)
+			if c == '\\' && quote == '\'' && i+1 < len(sql) {
+				i++
+				current.WriteByte(sql[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
 
-	structDef := StructDef{
-		Name:   structName,
-		Fields: fields,
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			current.WriteByte(c)
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			// Line comment: skip to end of line
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+			current.WriteByte(' ')
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			// Block comment: skip past the closing marker
+			end := strings.Index(sql[i+2:], "*/")
+			if end == -1 {
+				i = len(sql)
+			} else {
+				i += end + 3
+			}
+			current.WriteByte(' ')
+		case c == ';':
+			if stmt := strings.TrimSpace(current.String()); stmt != "" {
+				result = append(result, stmt)
+			}
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+
+	if stmt := strings.TrimSpace(current.String()); stmt != "" {
+		result = append(result, stmt)
 	}
 
-	return []StructDef{structDef}, nil
+	return result
 }
 
-// parseColumns parses the column definitions from the SQL CREATE TABLE statement
-func parseColumns(columnBlock string) ([]FieldDef, error) {
-	var fields []FieldDef
+// parseColumns parses the column definitions, keys and indexes from the SQL CREATE TABLE statement.
+// Lines that are not valid columns are skipped and returned as warnings.
+func parseColumns(columnBlock string, dialect Dialect) (StructDef, []string, error) {
+	var def StructDef
+	var primaryKey []string
+	var warnings []string
 
 	// Split by comma, but be careful of commas inside parentheses
 	lines := splitColumns(columnBlock)
@@ -100,30 +270,45 @@ func parseColumns(columnBlock string) ([]FieldDef, error) {
 			continue
 		}
 
-		// Skip constraint definitions (PRIMARY KEY, FOREIGN KEY, INDEX, etc.)
+		if fk, ok := parseForeignKey(line); ok {
+			def.ForeignKeys = append(def.ForeignKeys, fk)
+			continue
+		}
+		if matches := primaryKeyRegex.FindStringSubmatch(line); matches != nil {
+			primaryKey = splitIdentList(matches[2])
+			continue
+		}
+		if index, ok := parseIndex(line); ok {
+			def.Indexes = append(def.Indexes, index)
+			continue
+		}
+
+		// Skip other constraint definitions (PRIMARY KEY, INDEX, etc.)
 		if isConstraint(line) {
 			continue
 		}
 
-		field, err := parseColumnDefinition(line)
+		field, err := parseColumnDefinition(line, dialect)
 		if err != nil {
-			// Log warning for skipped columns
-			log.Printf("Warning: skipping line (not a valid column): %s - error: %v", line, err)
+			// Report skipped columns
+			warnings = append(warnings, fmt.Sprintf("skipping line (not a valid column): %s - error: %v", line, err))
 			continue
 		}
 
-		fields = append(fields, field)
+		def.Fields = append(def.Fields, field)
+		addInlineConstraints(&def, field, line)
 	}
 
-	if len(fields) == 0 {
-		return nil, fmt.Errorf("no valid columns found")
+	if len(def.Fields) == 0 {
+		return StructDef{}, warnings, fmt.Errorf("no valid columns found")
 	}
+	setPrimaryKey(def.Fields, primaryKey)
 
-	return fields, nil
+	return def, warnings, nil
 }
 
 // parseColumnDefinition parses a single column definition
-func parseColumnDefinition(line string) (FieldDef, error) {
+func parseColumnDefinition(line string, dialect Dialect) (FieldDef, error) {
 	// Remove quotes (backticks, single, double)
 	line = strings.TrimSpace(line)
 
@@ -134,35 +319,88 @@ func parseColumnDefinition(line string) (FieldDef, error) {
 	}
 
 	// Extract data type
-	dataType := extractDataType(restOfLine)
+	dataType, typeArgs := extractDataType(restOfLine)
 	if dataType == "" {
 		return FieldDef{}, fmt.Errorf("could not extract data type from: %s", line)
 	}
 
-	// Remove COMMENT and DEFAULT sections before checking NOT NULL
-	// to avoid false positives from comments containing "NOT NULL"
-	checkLine := removeCommentsAndDefaults(restOfLine)
+	// Remove COMMENT and DEFAULT sections and generation expressions before checking
+	// NOT NULL to avoid false positives from comments containing "NOT NULL"
+	generated, stored := parseGenerated(restOfLine)
+	checkLine := removeCommentsAndDefaults(strings.Replace(restOfLine, generated, "", 1))
+
+	// Key attributes may follow a DEFAULT clause, so only the comment and quoted strings are cut
+	keyLine := removeQuoted(restOfLine, "'")
+	if idx := strings.Index(strings.ToUpper(keyLine), "COMMENT"); idx != -1 {
+		keyLine = keyLine[:idx]
+	}
+	isPrimaryKey := inlinePrimaryKeyRegex.MatchString(keyLine)
 
 	// Check if column is nullable using word boundary regex
-	isNullable := !notNullRegex.MatchString(checkLine)
+	// SERIAL and primary key columns are implicitly NOT NULL
+	isNullable := !notNullRegex.MatchString(checkLine) && !strings.HasSuffix(dataType, "SERIAL") && !isPrimaryKey
 
 	// Detect UNSIGNED attribute
 	isUnsigned := strings.Contains(strings.ToUpper(restOfLine), "UNSIGNED")
 
-	// Map SQL type to Go type
-	goType := mapSQLTypeToGo(dataType, isNullable, isUnsigned)
-
 	field := FieldDef{
-		Name:       toPascalCase(columnName),
-		Type:       goType,
-		ColumnName: columnName, // Store original column name for tag generation
+		Name:          toPascalCase(columnName),
+		ColumnName:    columnName, // Store original column name for tag generation
+		Nullable:      isNullable,
+		Unsigned:      isUnsigned,
+		PrimaryKey:    isPrimaryKey,
+		AutoIncrement: autoIncrementRegex.MatchString(keyLine) || strings.HasSuffix(dataType, "SERIAL"),
+		Default:       parseDefault(restOfLine),
+		OnUpdate:      parseOnUpdate(keyLine),
+
+		Generated:       generated,
+		GeneratedStored: stored,
+	}
+	if matches := commentRegex.FindStringSubmatch(restOfLine); matches != nil {
+		field.Comment = strings.ReplaceAll(matches[1], "''", "'")
 	}
+	setColumnType(&field, dataType, typeArgs)
+
+	// Map SQL type to Go type
+	field.Type = goTypeForColumn(field, dialect)
 
 	return field, nil
 }
 
-// extractDataType extracts the data type from a column definition
-func extractDataType(definition string) string {
+// setColumnType stores the SQL type and its arguments in field
+func setColumnType(field *FieldDef, dataType, typeArgs string) {
+	field.SQLType = strings.TrimSuffix(dataType, "(1)")
+	field.Size = ""
+	field.EnumValues = nil
+
+	switch dataType {
+	case "ENUM":
+		field.EnumValues = parseEnumValues(typeArgs)
+	case "SET":
+		// SET values are not a size
+	default:
+		field.Size = strings.ReplaceAll(typeArgs, " ", "")
+	}
+}
+
+// goTypeForColumn maps the SQL type and nullability of a column to a Go type
+func goTypeForColumn(field FieldDef, dialect Dialect) string {
+	sqlType := field.SQLType
+	if sqlType == "TINYINT" && field.Size == "1" {
+		sqlType = "TINYINT(1)"
+	}
+
+	// SQLite stores INTEGER as a 64-bit value
+	if dialect == DialectSQLite && (sqlType == "INTEGER" || sqlType == "INT") {
+		sqlType = "BIGINT"
+	}
+
+	return mapSQLTypeToGo(sqlType, field.Nullable, field.Unsigned)
+}
+
+// extractDataType extracts the data type and its parenthesized arguments
+// (size, precision or ENUM values) from a column definition
+func extractDataType(definition string) (string, string) {
 	// Use pre-compiled regex with UNSIGNED support
 	matches := typeRegex.FindStringSubmatch(definition)
 	if len(matches) > 0 {
@@ -174,16 +412,53 @@ func extractDataType(definition string) string {
 
 		// Special case for TINYINT(1) which is typically used for boolean
 		if dataType == "TINYINT" && size == "1" {
-			return "TINYINT(1)"
+			return "TINYINT(1)", size
+		}
+
+		// Multi-word PostgreSQL types map to their common names
+		switch dataType {
+		case "CHARACTER VARYING":
+			return "VARCHAR", size
+		case "DOUBLE PRECISION":
+			return "DOUBLE", size
 		}
 
-		return dataType
+		return dataType, size
 	}
 
-	return ""
+	return "", ""
 }
 
-// mapSQLTypeToGo maps MySQL data types to Go types
+// parseEnumValues parses the quoted value list of an ENUM type, e.g. 'a', 'b'
+func parseEnumValues(args string) []string {
+	var values []string
+	var current strings.Builder
+	inQuote := false
+
+	for i := 0; i < len(args); i++ {
+		c := args[i]
+		switch {
+		case c == '\'' && inQuote && i+1 < len(args) && args[i+1] == '\'':
+			// Doubled quote is an escaped quote
+			current.WriteByte(c)
+			i++
+		case c == '\'':
+			inQuote = !inQuote
+		case c == ',' && !inQuote:
+			values = append(values, current.String())
+			current.Reset()
+		case inQuote:
+			current.WriteByte(c)
+		}
+	}
+	if current.Len() > 0 || len(values) > 0 {
+		values = append(values, current.String())
+	}
+
+	return values
+}
+
+// mapSQLTypeToGo maps MySQL, PostgreSQL and SQLite data types to Go types
 func mapSQLTypeToGo(sqlType string, nullable bool, unsigned bool) string {
 	sqlType = strings.ToUpper(sqlType)
 
@@ -198,31 +473,31 @@ func mapSQLTypeToGo(sqlType string, nullable bool, unsigned bool) string {
 		} else {
 			baseType = "int8"
 		}
-	case "SMALLINT":
+	case "SMALLINT", "INT2", "SMALLSERIAL":
 		if unsigned {
 			baseType = "uint16"
 		} else {
 			baseType = "int16"
 		}
-	case "MEDIUMINT", "INT", "INTEGER":
+	case "MEDIUMINT", "INT", "INTEGER", "INT4", "SERIAL":
 		if unsigned {
 			baseType = "uint32"
 		} else {
 			baseType = "int"
 		}
-	case "BIGINT":
+	case "BIGINT", "INT8", "BIGSERIAL":
 		if unsigned {
 			baseType = "uint64"
 		} else {
 			baseType = "int64"
 		}
-	case "FLOAT", "DOUBLE", "DECIMAL", "NUMERIC":
+	case "FLOAT", "FLOAT4", "FLOAT8", "REAL", "DOUBLE", "DECIMAL", "NUMERIC":
 		baseType = "float64"
-	case "CHAR", "VARCHAR", "TEXT", "TINYTEXT", "MEDIUMTEXT", "LONGTEXT", "JSON":
+	case "CHAR", "CHARACTER", "VARCHAR", "TEXT", "TINYTEXT", "MEDIUMTEXT", "LONGTEXT", "CITEXT", "UUID", "JSON", "JSONB":
 		baseType = "string"
-	case "DATETIME", "TIMESTAMP", "DATE", "TIME":
+	case "DATETIME", "TIMESTAMP", "TIMESTAMPTZ", "DATE", "TIME", "TIMETZ":
 		baseType = "time.Time"
-	case "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB":
+	case "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BYTEA":
 		// []byte is already nullable (nil), so don't use pointer
 		return "[]byte"
 	default:
@@ -239,6 +514,55 @@ func mapSQLTypeToGo(sqlType string, nullable bool, unsigned bool) string {
 	return baseType
 }
 
+// addInlineConstraints adds the REFERENCES and UNIQUE clauses of a column definition to def
+func addInlineConstraints(def *StructDef, field FieldDef, definition string) {
+	if fk, ok := parseInlineReference(field.ColumnName, definition); ok {
+		def.ForeignKeys = append(def.ForeignKeys, fk)
+	}
+
+	// Look for UNIQUE outside of comments, defaults and REFERENCES clauses
+	checkLine := removeCommentsAndDefaults(definition)
+	if loc := referencesRegex.FindStringIndex(checkLine); loc != nil {
+		checkLine = checkLine[:loc[0]]
+	}
+	if inlineUniqueRegex.MatchString(checkLine) {
+		def.Indexes = append(def.Indexes, Index{Columns: []string{field.ColumnName}, Unique: true})
+	}
+}
+
+// setPrimaryKey marks the columns of a table-level PRIMARY KEY constraint.
+// An empty list leaves the fields unchanged.
+func setPrimaryKey(fields []FieldDef, columns []string) {
+	if len(columns) == 0 {
+		return
+	}
+	for i := range fields {
+		fields[i].PrimaryKey = containsFold(columns, fields[i].ColumnName)
+		if fields[i].PrimaryKey {
+			setNotNull(&fields[i])
+		}
+	}
+}
+
+// setNotNull makes a column NOT NULL, as primary key columns always are
+func setNotNull(field *FieldDef) {
+	field.Nullable = false
+	field.Type = strings.TrimPrefix(field.Type, "*")
+}
+
+// setSQLiteRowID marks the rowid alias of a SQLite table as auto-increment: a single
+// INTEGER PRIMARY KEY column, which SQLite assigns on insert. Tables created WITHOUT
+// ROWID have no alias.
+func setSQLiteRowID(def *StructDef) {
+	key := primaryKeyColumns(*def)
+	if len(key) != 1 {
+		return
+	}
+	if field := &def.Fields[fieldIndex(def.Fields, key[0])]; field.SQLType == "INTEGER" {
+		field.AutoIncrement = true
+	}
+}
+
 // isConstraint checks if a line is a constraint definition rather than a column
 func isConstraint(line string) bool {
 	upperLine := strings.ToUpper(line)
@@ -250,6 +574,8 @@ func isConstraint(line string) bool {
 		"INDEX ",
 		"CONSTRAINT",
 		"CHECK ",
+		"FULLTEXT ",
+		"SPATIAL ",
 	}
 
 	for _, keyword := range constraintKeywords {
@@ -314,6 +640,16 @@ func toPascalCase(s string) string {
 	return result.String()
 }
 
+// toCamelCase converts a snake_case or PascalCase name to camelCase
+// (created_at -> createdAt, UserID -> userId)
+func toCamelCase(s string) string {
+	pascal := toPascalCase(toSnakeCase(s))
+	if pascal == "" {
+		return s
+	}
+	return strings.ToLower(pascal[:1]) + pascal[1:]
+}
+
 // normalizeWhitespace replaces multiple spaces/tabs/newlines with single space
 func normalizeWhitespace(s string) string {
 	// Replace multiple whitespace with single space
@@ -374,6 +710,28 @@ func extractColumnName(line string) (string, string) {
 	return parts[0], strings.Join(parts[1:], " ")
 }
 
+// removeQuoted empties the strings quoted with any of the quote characters, so that
+// their contents are not read as keywords
+func removeQuoted(s, quotes string) string {
+	var output strings.Builder
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+			output.WriteByte(c)
+		case quote != 0:
+		case strings.IndexByte(quotes, c) != -1:
+			quote = c
+			output.WriteByte(c)
+		default:
+			output.WriteByte(c)
+		}
+	}
+	return output.String()
+}
+
 // removeCommentsAndDefaults removes COMMENT and DEFAULT clauses from column definition
 // to prevent false positives when checking for NOT NULL
 func removeCommentsAndDefaults(line string) string {
@@ -420,28 +778,227 @@ func GenerateGoCode(defs []StructDef, config Config) string {
 		return ""
 	}
 
-	var output strings.Builder
+	typed, _ := applyBaseStruct(generatedDefs(defs, config), config)
 
-	// Determine if we need time import
-	needsTime := needsTimeImport(defs)
+	var body strings.Builder
 
-	// Generate package and imports
-	output.WriteString("package main\n\n")
-	if needsTime {
-		output.WriteString("import \"time\"\n\n")
+	// Generate the base struct before the structs embedding it
+	if base := generateBaseStruct(typed, config); base != "" {
+		body.WriteString(base)
+		body.WriteString("\n")
 	}
 
 	// Generate each struct
-	for i, def := range defs {
+	for i, def := range typed {
 		if i > 0 {
-			output.WriteString("\n")
+			body.WriteString("\n")
+		}
+		body.WriteString(generateStruct(def, config))
+		if config.AddConstructors {
+			if constructor, _ := generateConstructor(def); constructor != "" {
+				body.WriteString("\n")
+				body.WriteString(constructor)
+			}
+		}
+		if config.AddDTOs {
+			body.WriteString("\n")
+			body.WriteString(generateDTOs(def, config))
+		}
+	}
+
+	// Generate ENUM types after the structs that use them
+	if config.AddEnumTypes {
+		if enums := generateEnumTypes(typed); enums != "" {
+			body.WriteString("\n")
+			body.WriteString(enums)
+		}
+	}
+
+	var imports []string
+	if usesJSONType(typed, config) {
+		if config.JSONWrapper == "" {
+			body.WriteString("\n")
+			body.WriteString(generateJSONType())
+			imports = append(imports, jsonTypeImports...)
+		}
+		imports = append(imports, jsonWrapperImports(typed, config)...)
+	}
+
+	// Generate the repository functions after the types they use
+	switch {
+	case config.AddSQLX:
+		for _, def := range typed {
+			body.WriteString("\n")
+			body.WriteString(generateSQLX(def, config))
+		}
+		imports = append(imports, sqlxImports...)
+	case config.AddRepository:
+		body.WriteString("\n")
+		body.WriteString(generateDBTX())
+		for _, def := range typed {
+			body.WriteString("\n")
+			body.WriteString(generateRepository(def, config))
+		}
+		imports = append(imports, dbtxImports...)
+	}
+
+	imports = append(imports, baseImports(typed)...)
+	declared := declaredDefs(typed, config)
+	if base, ok := baseStructDef(typed, config); ok {
+		declared = append(declared, base)
+	}
+	return generateGoFile(defs, goImports(declared, imports...), body.String(), config)
+}
+
+// GoCodeWarnings returns the warnings of generating Go code from defs, such as
+// defaults left out of the constructors or a base struct that no table embeds
+func GoCodeWarnings(defs []StructDef, config Config) []string {
+	typed, warnings := applyBaseStruct(generatedDefs(defs, config), config)
+	if config.AddConstructors {
+		for _, def := range typed {
+			_, skipped := generateConstructor(def)
+			warnings = append(warnings, skipped...)
+		}
+	}
+	return warnings
+}
+
+// generatedDefs returns defs with the association fields, enum types and JSON types enabled in config
+func generatedDefs(defs []StructDef, config Config) []StructDef {
+	if config.AddAssociations {
+		defs = applyAssociations(defs)
+	}
+	if config.AddEnumTypes {
+		defs = applyEnumTypes(defs)
+	}
+	if config.AddJSONTypes {
+		defs = applyJSONTypes(defs, config)
+	}
+	return defs
+}
+
+// generateGoFile assembles a complete Go file: header, package clause, imports and body.
+// The source definitions are used for the source hash.
+func generateGoFile(source []StructDef, imports []string, body string, config Config) string {
+	var output strings.Builder
+
+	// Generate file header, build constraint, package and imports
+	output.WriteString(generateFileHeader(source, config))
+	output.WriteString(fmt.Sprintf("package %s\n\n", packageName(config)))
+	switch len(imports) {
+	case 0:
+	case 1:
+		output.WriteString(fmt.Sprintf("import %q\n\n", imports[0]))
+	default:
+		output.WriteString("import (\n")
+		for i, imp := range imports {
+			// Separate standard library imports from the rest, like goimports
+			if i > 0 && isStdImport(imports[i-1]) && !isStdImport(imp) {
+				output.WriteString("\n")
+			}
+			output.WriteString(fmt.Sprintf("\t%q\n", imp))
 		}
-		output.WriteString(generateStruct(def, config))
+		output.WriteString(")\n\n")
 	}
 
+	output.WriteString(body)
 	return output.String()
 }
 
+// goImports returns the packages imported by the generated structs and the extra
+// packages, standard library first, each group sorted
+func goImports(defs []StructDef, extra ...string) []string {
+	seen := make(map[string]bool)
+	for _, imp := range extra {
+		seen[imp] = true
+	}
+	if needsTimeImport(defs) {
+		seen["time"] = true
+	}
+	for _, def := range defs {
+		for _, field := range def.Fields {
+			if field.TypeImport != "" {
+				seen[field.TypeImport] = true
+			}
+		}
+	}
+	return sortImports(seen)
+}
+
+// sortImports returns a set of import paths, standard library first, each group sorted
+func sortImports(seen map[string]bool) []string {
+	imports := make([]string, 0, len(seen))
+	for imp := range seen {
+		imports = append(imports, imp)
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		if isStdImport(imports[i]) != isStdImport(imports[j]) {
+			return isStdImport(imports[i])
+		}
+		return imports[i] < imports[j]
+	})
+	return imports
+}
+
+// isStdImport reports whether an import path belongs to the standard library
+func isStdImport(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+// generateFileHeader generates the comments that precede the package clause
+func generateFileHeader(defs []StructDef, config Config) string {
+	var output strings.Builder
+
+	if config.AddHeader {
+		output.WriteString(generatedHeader + "\n")
+	}
+	if config.AddSourceHash {
+		output.WriteString(sourceHashPrefix + SourceHash(defs) + "\n")
+	}
+	if output.Len() > 0 {
+		output.WriteString("\n")
+	}
+
+	// A build constraint must be followed by a blank line to not be
+	// mistaken for the package doc comment
+	if tags := strings.TrimSpace(config.BuildTags); tags != "" {
+		output.WriteString("//go:build " + tags + "\n\n")
+	}
+
+	return output.String()
+}
+
+// packageName returns the configured package name, defaulting to main
+func packageName(config Config) string {
+	if name := strings.TrimSpace(config.PackageName); name != "" {
+		return name
+	}
+	return "main"
+}
+
+// SourceHash returns a stable hash of the parsed schema.
+// Files generated with AddSourceHash embed it so they can be checked for staleness.
+func SourceHash(defs []StructDef) string {
+	data, _ := json.Marshal(defs)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// IsStale reports whether generated code was produced from a schema other than defs.
+// Code without a source hash comment is always considered stale.
+func IsStale(code string, defs []StructDef) bool {
+	for _, line := range strings.Split(code, "\n") {
+		if strings.HasPrefix(line, "package ") {
+			break
+		}
+		if hash, ok := strings.CutPrefix(line, sourceHashPrefix); ok {
+			return strings.TrimSpace(hash) != SourceHash(defs)
+		}
+	}
+	return true
+}
+
 // generateStruct generates a single struct with proper field alignment
 func generateStruct(def StructDef, config Config) string {
 	var output strings.Builder
@@ -453,11 +1010,34 @@ func generateStruct(def StructDef, config Config) string {
 		return output.String()
 	}
 
-	// Calculate alignment widths
-	maxNameLen, maxTypeLen := calculateAlignment(def.Fields)
+	// The base struct comes first, instead of its fields
+	var fields []FieldDef
+	for _, field := range def.Fields {
+		if field.Embedded == "" {
+			fields = append(fields, field)
+		}
+	}
+	if base := embeddedType(def); base != "" {
+		output.WriteString("\t" + base + "\n")
+	}
 
 	// Generate fields
-	for _, field := range def.Fields {
+	var maxNameLen, maxTypeLen int
+	for i, field := range fields {
+		// Note what the database computes. Like gofmt, align each run of
+		// fields between comment lines separately.
+		comment := generatedFieldComment(field)
+		if comment != "" {
+			output.WriteString("\t" + comment + "\n")
+		}
+		if i == 0 || comment != "" {
+			end := i + 1
+			for end < len(fields) && generatedFieldComment(fields[end]) == "" {
+				end++
+			}
+			maxNameLen, maxTypeLen = calculateAlignment(fields[i:end])
+		}
+
 		// Field name (aligned)
 		output.WriteString("\t")
 		output.WriteString(field.Name)
@@ -467,7 +1047,7 @@ func generateStruct(def StructDef, config Config) string {
 		output.WriteString(field.Type)
 
 		// Generate tags if configured
-		tags := generateStructTags(field.ColumnName, config)
+		tags := generateStructTags(field, config)
 		if tags != "" {
 			output.WriteString(strings.Repeat(" ", maxTypeLen-len(field.Type)+1))
 			output.WriteString("`")
@@ -482,6 +1062,92 @@ func generateStruct(def StructDef, config Config) string {
 	return output.String()
 }
 
+// applyEnumTypes returns a copy of defs where ENUM fields use their named enum type
+func applyEnumTypes(defs []StructDef) []StructDef {
+	result := make([]StructDef, len(defs))
+	for i, def := range defs {
+		fields := make([]FieldDef, len(def.Fields))
+		for j, field := range def.Fields {
+			if len(field.EnumValues) > 0 {
+				typeName := enumTypeName(def, field)
+				if strings.HasPrefix(field.Type, "*") {
+					field.Type = "*" + typeName
+				} else {
+					field.Type = typeName
+				}
+			}
+			fields[j] = field
+		}
+		def.Fields = fields
+		result[i] = def
+	}
+	return result
+}
+
+// generateEnumTypes generates a named string type and a constant block for each ENUM column
+func generateEnumTypes(defs []StructDef) string {
+	var output strings.Builder
+
+	for _, def := range defs {
+		for _, field := range def.Fields {
+			if len(field.EnumValues) == 0 {
+				continue
+			}
+			if output.Len() > 0 {
+				output.WriteString("\n")
+			}
+
+			typeName := enumTypeName(def, field)
+			output.WriteString(fmt.Sprintf("// %s is the set of values allowed in %s.%s\n", typeName, def.TableName, field.ColumnName))
+			output.WriteString(fmt.Sprintf("type %s string\n\n", typeName))
+
+			// Align constant names like gofmt does
+			names := make([]string, len(field.EnumValues))
+			maxNameLen := 0
+			for i, value := range field.EnumValues {
+				names[i] = typeName + enumConstSuffix(value)
+				if len(names[i]) > maxNameLen {
+					maxNameLen = len(names[i])
+				}
+			}
+
+			output.WriteString("const (\n")
+			for i, value := range field.EnumValues {
+				output.WriteString("\t")
+				output.WriteString(names[i])
+				output.WriteString(strings.Repeat(" ", maxNameLen-len(names[i])+1))
+				output.WriteString(fmt.Sprintf("%s = %q\n", typeName, value))
+			}
+			output.WriteString(")\n")
+		}
+	}
+
+	return output.String()
+}
+
+// enumTypeName returns the Go type name for an ENUM column (struct name + field name)
+func enumTypeName(def StructDef, field FieldDef) string {
+	return def.Name + field.Name
+}
+
+// enumConstSuffix converts an ENUM value to a PascalCase identifier suffix
+func enumConstSuffix(value string) string {
+	var cleaned strings.Builder
+	for _, r := range value {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			cleaned.WriteRune(r)
+		} else {
+			cleaned.WriteRune('_')
+		}
+	}
+
+	suffix := toPascalCase(cleaned.String())
+	if suffix == "" {
+		return "Empty"
+	}
+	return suffix
+}
+
 // calculateAlignment calculates the maximum field name and type lengths for alignment
 func calculateAlignment(fields []FieldDef) (maxNameLen, maxTypeLen int) {
 	for _, field := range fields {
@@ -496,22 +1162,33 @@ func calculateAlignment(fields []FieldDef) (maxNameLen, maxTypeLen int) {
 }
 
 // generateStructTags generates struct tags based on config
-func generateStructTags(columnName string, config Config) string {
+func generateStructTags(field FieldDef, config Config) string {
+	if field.Association != "" {
+		return generateAssociationTags(field, config)
+	}
+
 	var tags []string
 
 	// Normalize column name to lowercase snake_case for tags (industry standard)
-	normalizedName := toSnakeCase(columnName)
+	normalizedName := toSnakeCase(field.ColumnName)
 
 	if config.AddJSONTag {
-		tags = append(tags, fmt.Sprintf(`json:"%s"`, normalizedName))
+		// JavaScript clients read 64-bit integers exactly only from strings
+		if config.Int64AsString && isInt64Field(field) {
+			tags = append(tags, fmt.Sprintf(`json:"%s,string"`, normalizedName))
+		} else {
+			tags = append(tags, fmt.Sprintf(`json:"%s"`, normalizedName))
+		}
 	}
 
-	if config.AddDBTag {
+	// sqlx maps columns to fields by their db tags
+	if config.AddDBTag || config.AddSQLX {
 		tags = append(tags, fmt.Sprintf(`db:"%s"`, normalizedName))
 	}
 
 	if config.AddGormTag {
-		tags = append(tags, fmt.Sprintf(`gorm:"column:%s"`, normalizedName))
+		settings := append([]string{"column:" + normalizedName}, gormWriteSettings(field)...)
+		tags = append(tags, fmt.Sprintf(`gorm:"%s"`, strings.Join(settings, ";")))
 	}
 
 	if config.AddXMLTag {
@@ -521,6 +1198,40 @@ func generateStructTags(columnName string, config Config) string {
 	return strings.Join(tags, " ")
 }
 
+// jsonFieldName returns the name a field has in the JSON encoding of its struct
+// with json tags enabled
+func jsonFieldName(field FieldDef) string {
+	if field.Association != "" {
+		return toSnakeCase(field.Name)
+	}
+	return toSnakeCase(field.ColumnName)
+}
+
+// generateAssociationTags generates the struct tags of a relation field.
+// Relations are omitted from empty JSON/XML output and are not database columns.
+func generateAssociationTags(field FieldDef, config Config) string {
+	var tags []string
+	name := toSnakeCase(field.Name)
+
+	if config.AddJSONTag {
+		tags = append(tags, fmt.Sprintf(`json:"%s,omitempty"`, name))
+	}
+
+	if config.AddDBTag || config.AddSQLX {
+		tags = append(tags, `db:"-"`)
+	}
+
+	if config.AddGormTag {
+		tags = append(tags, fmt.Sprintf(`gorm:"%s"`, field.Association))
+	}
+
+	if config.AddXMLTag {
+		tags = append(tags, fmt.Sprintf(`xml:"%s,omitempty"`, name))
+	}
+
+	return strings.Join(tags, " ")
+}
+
 // toSnakeCase converts a string to lowercase snake_case
 // Handles: PascalCase, camelCase, SCREAMING_CASE, or already snake_case
 func toSnakeCase(s string) string {
@@ -925,3 +925,277 @@ func TestGenerateGoCode_EmptyInput(t *testing.T) {
 		t.Errorf("Expected empty string for empty input, got: %s", code)
 	}
 }
+
+// TestGenerateGoCode_PackageName tests the configurable package clause
+func TestGenerateGoCode_PackageName(t *testing.T) {
+	structs, err := ParseSQL(`CREATE TABLE users (id INT NOT NULL)`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	code := GenerateGoCode(structs, Config{PackageName: "models"})
+	if !strings.HasPrefix(code, "package models\n") {
+		t.Errorf("Expected package models, got:\n%s", code)
+	}
+
+	code = GenerateGoCode(structs, Config{})
+	if !strings.HasPrefix(code, "package main\n") {
+		t.Errorf("Expected package main by default, got:\n%s", code)
+	}
+}
+
+// TestGenerateGoCode_HeaderAndBuildTags tests the generated-file header and build constraint
+func TestGenerateGoCode_HeaderAndBuildTags(t *testing.T) {
+	structs, err := ParseSQL(`CREATE TABLE users (id INT NOT NULL)`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	config := Config{
+		PackageName: "models",
+		AddHeader:   true,
+		BuildTags:   "integration",
+	}
+	code := GenerateGoCode(structs, config)
+
+	expected := "// Code generated by sql-to-go; DO NOT EDIT.\n\n//go:build integration\n\npackage models\n"
+	if !strings.HasPrefix(code, expected) {
+		t.Errorf("Expected header:\n%s\ngot:\n%s", expected, code)
+	}
+}
+
+// TestGenerateGoCode_SourceHash tests that the embedded hash detects schema changes
+func TestGenerateGoCode_SourceHash(t *testing.T) {
+	oldStructs, err := ParseSQL(`CREATE TABLE users (id INT NOT NULL)`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	newStructs, err := ParseSQL(`CREATE TABLE users (id INT NOT NULL, email VARCHAR(255))`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	code := GenerateGoCode(oldStructs, Config{AddHeader: true, AddSourceHash: true})
+	if !strings.Contains(code, "// sql-to-go source hash: "+SourceHash(oldStructs)) {
+		t.Errorf("Expected source hash comment, got:\n%s", code)
+	}
+
+	if IsStale(code, oldStructs) {
+		t.Error("Code generated from the same schema should not be stale")
+	}
+	if !IsStale(code, newStructs) {
+		t.Error("Code generated from a different schema should be stale")
+	}
+	if !IsStale(GenerateGoCode(oldStructs, Config{}), oldStructs) {
+		t.Error("Code without a source hash should be stale")
+	}
+}
+
+// TestParseSQL_MultipleTables tests scripts with several CREATE TABLE statements
+func TestParseSQL_MultipleTables(t *testing.T) {
+	sql := `-- users table; holds accounts
+	CREATE TABLE users (
+		id INT NOT NULL,
+		bio TEXT COMMENT 'semicolons; inside strings'
+	);
+
+	/* orders; one per checkout */
+	CREATE TABLE order_items (
+		id INT NOT NULL,
+		user_id INT NOT NULL
+	);
+
+	INSERT INTO users (id) VALUES (1);`
+
+	structs, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(structs) != 2 {
+		t.Fatalf("Expected 2 structs, got %d", len(structs))
+	}
+
+	if structs[0].Name != "Users" || structs[0].TableName != "users" {
+		t.Errorf("Expected Users/users, got %s/%s", structs[0].Name, structs[0].TableName)
+	}
+	if structs[1].Name != "OrderItems" || structs[1].TableName != "order_items" {
+		t.Errorf("Expected OrderItems/order_items, got %s/%s", structs[1].Name, structs[1].TableName)
+	}
+	if len(structs[0].Fields) != 2 {
+		t.Errorf("Expected 2 fields in users, got %d", len(structs[0].Fields))
+	}
+}
+
+// TestGenerateGoCode_EnumTypes tests named types generated for ENUM columns
+func TestGenerateGoCode_EnumTypes(t *testing.T) {
+	sql := `CREATE TABLE orders (
+		id INT NOT NULL,
+		status ENUM('pending', 'in-progress', 'it''s done') NOT NULL,
+		priority ENUM('low', 'high')
+	)`
+
+	structs, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expectedValues := []string{"pending", "in-progress", "it's done"}
+	values := structs[0].Fields[1].EnumValues
+	if strings.Join(values, "|") != strings.Join(expectedValues, "|") {
+		t.Errorf("Expected enum values %v, got %v", expectedValues, values)
+	}
+
+	code := GenerateGoCode(structs, Config{AddEnumTypes: true})
+
+	expected := []string{
+		"Status   OrdersStatus",
+		"Priority *OrdersPriority",
+		"type OrdersStatus string",
+		`OrdersStatusPending    OrdersStatus = "pending"`,
+		`OrdersStatusInProgress OrdersStatus = "in-progress"`,
+		`OrdersStatusItSDone    OrdersStatus = "it's done"`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(code, e) {
+			t.Errorf("Expected %q in generated code:\n%s", e, code)
+		}
+	}
+
+	// Without the option ENUM stays a plain string
+	if code := GenerateGoCode(structs, Config{}); strings.Contains(code, "OrdersStatus") {
+		t.Errorf("Enum types should not be generated by default:\n%s", code)
+	}
+}
+
+// TestParseSQL_PostgreSQLTypes tests PostgreSQL-specific types
+func TestParseSQL_PostgreSQLTypes(t *testing.T) {
+	sql := `CREATE TABLE accounts (
+		id BIGSERIAL PRIMARY KEY,
+		external_id UUID NOT NULL,
+		name CHARACTER VARYING(100) NOT NULL,
+		score DOUBLE PRECISION,
+		settings JSONB,
+		avatar BYTEA,
+		count INTEGER NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL
+	)`
+
+	structs, warnings, err := ParseSQLWithOptions(sql, ParseOptions{Dialect: DialectPostgres})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got: %v", warnings)
+	}
+
+	expectedTypes := map[string]string{
+		"Id":         "int64",
+		"ExternalId": "string",
+		"Name":       "string",
+		"Score":      "*float64",
+		"Settings":   "*string",
+		"Avatar":     "[]byte",
+		"Count":      "int",
+		"CreatedAt":  "time.Time",
+	}
+
+	for _, field := range structs[0].Fields {
+		if expected := expectedTypes[field.Name]; field.Type != expected {
+			t.Errorf("Field %s: expected type '%s', got '%s'", field.Name, expected, field.Type)
+		}
+	}
+}
+
+// TestParseSQL_SQLiteDialect tests that SQLite INTEGER maps to int64
+func TestParseSQL_SQLiteDialect(t *testing.T) {
+	sql := `CREATE TABLE notes (id INTEGER NOT NULL, body TEXT)`
+
+	structs, _, err := ParseSQLWithOptions(sql, ParseOptions{Dialect: DialectSQLite})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if structs[0].Fields[0].Type != "int64" {
+		t.Errorf("Expected int64 for SQLite INTEGER, got: %s", structs[0].Fields[0].Type)
+	}
+}
+
+// TestParseSQLWithOptions_Warnings tests that skipped lines are reported as warnings
+func TestParseSQLWithOptions_Warnings(t *testing.T) {
+	sql := `CREATE TABLE users (id INT NOT NULL, not_a_column)`
+
+	structs, warnings, err := ParseSQLWithOptions(sql, ParseOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(structs[0].Fields) != 1 {
+		t.Errorf("Expected 1 field, got %d", len(structs[0].Fields))
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "not_a_column") {
+		t.Errorf("Expected one warning about not_a_column, got: %v", warnings)
+	}
+}
+
+// TestParseSQL_AlterTable tests that ALTER TABLE statements after CREATE TABLE are applied
+func TestParseSQL_AlterTable(t *testing.T) {
+	sql := `CREATE TABLE users (
+		id INT NOT NULL,
+		name VARCHAR(255)
+	);
+	ALTER TABLE users ADD COLUMN email VARCHAR(255) NOT NULL;
+	ALTER TABLE users DROP COLUMN name;`
+
+	structs, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	code := GenerateGoCode(structs, Config{})
+	if !strings.Contains(code, "Email string") || strings.Contains(code, "Name") {
+		t.Errorf("Expected the altered struct, got:\n%s", code)
+	}
+}
+
+// TestParseSQL_PrimaryKeys tests inline, table-level and auto-increment key detection
+func TestParseSQL_PrimaryKeys(t *testing.T) {
+	structs, err := ParseSQL(`
+		CREATE TABLE users (id INT NOT NULL AUTO_INCREMENT COMMENT 'PRIMARY KEY', name VARCHAR(50), PRIMARY KEY (id));
+		CREATE TABLE tags (id SERIAL PRIMARY KEY, label TEXT);
+		CREATE TABLE post_tags (post_id INT NOT NULL, tag_id INT NOT NULL, CONSTRAINT pk PRIMARY KEY (post_id, tag_id));
+		CREATE TABLE events (id BIGINT GENERATED ALWAYS AS IDENTITY, note TEXT);
+		ALTER TABLE events ADD PRIMARY KEY (id);
+		CREATE TABLE notes (code VARCHAR(10) NOT NULL, kind VARCHAR(20) DEFAULT 'auto_increment primary key', PRIMARY KEY (code));
+	`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		table         int
+		primaryKey    string
+		autoIncrement string
+	}{
+		{0, "id", "id"},
+		{1, "id", "id"},
+		{2, "post_id,tag_id", ""},
+		{3, "id", "id"},
+		{4, "code", ""},
+	}
+	for _, tt := range tests {
+		var keys, autos []string
+		for _, field := range structs[tt.table].Fields {
+			if field.PrimaryKey {
+				keys = append(keys, field.ColumnName)
+			}
+			if field.AutoIncrement {
+				autos = append(autos, field.ColumnName)
+			}
+		}
+		if strings.Join(keys, ",") != tt.primaryKey || strings.Join(autos, ",") != tt.autoIncrement {
+			t.Errorf("Table %s: expected key %q and auto-increment %q, got %v and %v",
+				structs[tt.table].TableName, tt.primaryKey, tt.autoIncrement, keys, autos)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// simpleIdentRegex matches identifiers that never need quoting
+var simpleIdentRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// reservedWords are common keywords that must be quoted when used as identifiers
+var reservedWords = map[string]bool{
+	"all": true, "and": true, "as": true, "by": true, "check": true, "column": true,
+	"constraint": true, "create": true, "default": true, "delete": true, "desc": true,
+	"distinct": true, "drop": true, "from": true, "group": true, "having": true,
+	"in": true, "index": true, "insert": true, "into": true, "is": true, "join": true,
+	"key": true, "like": true, "limit": true, "not": true, "null": true, "on": true,
+	"or": true, "order": true, "primary": true, "references": true, "select": true,
+	"set": true, "table": true, "to": true, "union": true, "unique": true,
+	"update": true, "user": true, "using": true, "values": true, "where": true,
+}
+
+// GenerateDDL generates CREATE TABLE and CREATE INDEX statements for defs in the given dialect.
+// Column types are translated between dialects (DATETIME becomes TIMESTAMPTZ on
+// PostgreSQL, UUID becomes CHAR(36) on MySQL, ...), so definitions parsed from one
+// dialect can be written for another.
+func GenerateDDL(defs []StructDef, dialect Dialect) string {
+	var output strings.Builder
+
+	for i, def := range defs {
+		if i > 0 {
+			output.WriteString("\n")
+		}
+		output.WriteString(createTableDDL(def, dialect))
+		for _, index := range def.Indexes {
+			output.WriteString(createIndexDDL(def.TableName, index, dialect))
+		}
+	}
+
+	return output.String()
+}
+
+// createTableDDL generates the CREATE TABLE statement of a single table
+func createTableDDL(def StructDef, dialect Dialect) string {
+	var primaryKey []string
+	for _, field := range def.Fields {
+		if field.PrimaryKey && field.ColumnName != "" {
+			primaryKey = append(primaryKey, quoteIdent(field.ColumnName, dialect))
+		}
+	}
+
+	// SQLite only auto-increments an INTEGER PRIMARY KEY declared on the column
+	inlinePrimaryKey := dialect == DialectSQLite && len(primaryKey) == 1
+
+	var lines []string
+	for _, field := range def.Fields {
+		if field.ColumnName == "" {
+			// Association fields are not columns
+			continue
+		}
+		lines = append(lines, columnDDL(field, inlinePrimaryKey && field.PrimaryKey, dialect))
+	}
+
+	if len(primaryKey) > 0 && !inlinePrimaryKey {
+		lines = append(lines, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(primaryKey, ", ")))
+	}
+
+	for _, fk := range def.ForeignKeys {
+		lines = append(lines, foreignKeyDDL(fk, dialect))
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", quoteIdent(def.TableName, dialect)))
+	for i, line := range lines {
+		output.WriteString("    ")
+		output.WriteString(line)
+		if i < len(lines)-1 {
+			output.WriteString(",")
+		}
+		output.WriteString("\n")
+	}
+	output.WriteString(");\n")
+
+	return output.String()
+}
+
+// columnDDL generates a column definition
+func columnDDL(field FieldDef, inlinePrimaryKey bool, dialect Dialect) string {
+	var parts []string
+	parts = append(parts, quoteIdent(field.ColumnName, dialect), columnTypeDDL(field, dialect))
+	if field.Generated != "" {
+		parts = append(parts, generatedColumnDDL(field, dialect))
+	}
+
+	if !field.Nullable || field.PrimaryKey {
+		parts = append(parts, "NOT NULL")
+	}
+	if field.Default != "" {
+		parts = append(parts, "DEFAULT "+field.Default)
+	}
+	// Only MySQL updates columns with ON UPDATE
+	if field.OnUpdate != "" && dialect == DialectMySQL {
+		parts = append(parts, "ON UPDATE "+field.OnUpdate)
+	}
+
+	switch {
+	case inlinePrimaryKey && field.AutoIncrement:
+		parts = append(parts, "PRIMARY KEY AUTOINCREMENT")
+	case inlinePrimaryKey:
+		parts = append(parts, "PRIMARY KEY")
+	case field.AutoIncrement && dialect == DialectMySQL:
+		parts = append(parts, "AUTO_INCREMENT")
+	}
+
+	// PostgreSQL and SQLite have no ENUM type; keep the allowed values as a CHECK constraint
+	if len(field.EnumValues) > 0 && dialect != DialectMySQL {
+		parts = append(parts, fmt.Sprintf("CHECK (%s IN (%s))", quoteIdent(field.ColumnName, dialect), quoteValues(field.EnumValues)))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// foreignKeyDDL generates a FOREIGN KEY constraint
+func foreignKeyDDL(fk ForeignKey, dialect Dialect) string {
+	var output strings.Builder
+	if fk.Name != "" {
+		output.WriteString(fmt.Sprintf("CONSTRAINT %s ", quoteIdent(fk.Name, dialect)))
+	}
+	output.WriteString(fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s", quoteIdents(fk.Columns, dialect), quoteIdent(fk.RefTable, dialect)))
+	if len(fk.RefColumns) > 0 {
+		output.WriteString(fmt.Sprintf(" (%s)", quoteIdents(fk.RefColumns, dialect)))
+	}
+	return output.String()
+}
+
+// columnTypeDDL returns the column type of field written for dialect
+func columnTypeDDL(field FieldDef, dialect Dialect) string {
+	sqlType := strings.ToUpper(field.SQLType)
+	size := field.Size
+	if sqlType == "" {
+		sqlType = "TEXT"
+	}
+	integer := isIntegerSQLType(sqlType)
+
+	switch dialect {
+	case DialectPostgres:
+		if sqlType == "TINYINT" && size == "1" {
+			return "BOOLEAN"
+		}
+		if integer {
+			return postgresIntegerType(sqlType, field.Unsigned, field.AutoIncrement)
+		}
+		switch sqlType {
+		case "DATETIME":
+			return "TIMESTAMPTZ"
+		case "DOUBLE":
+			return "DOUBLE PRECISION"
+		case "FLOAT":
+			return "REAL"
+		case "TINYTEXT", "MEDIUMTEXT", "LONGTEXT", "ENUM", "SET":
+			return "TEXT"
+		case "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB":
+			return "BYTEA"
+		}
+
+	case DialectSQLite:
+		if integer && field.AutoIncrement {
+			return "INTEGER"
+		}
+		switch sqlType {
+		case "SERIAL", "BIGSERIAL", "SMALLSERIAL":
+			return "INTEGER"
+		case "ENUM", "SET", "CITEXT":
+			return "TEXT"
+		case "BYTEA":
+			return "BLOB"
+		case "TIMESTAMPTZ":
+			return "DATETIME"
+		}
+
+	default:
+		switch sqlType {
+		case "SERIAL", "INT4":
+			sqlType = "INT"
+		case "BIGSERIAL", "INT8":
+			sqlType = "BIGINT"
+		case "SMALLSERIAL", "INT2":
+			sqlType = "SMALLINT"
+		case "FLOAT4":
+			sqlType = "FLOAT"
+		case "FLOAT8":
+			sqlType = "DOUBLE"
+		case "TIMESTAMPTZ":
+			sqlType = "TIMESTAMP"
+		case "TIMETZ":
+			sqlType = "TIME"
+		case "CHARACTER":
+			sqlType = "CHAR"
+		case "BYTEA":
+			return "BLOB"
+		case "JSONB":
+			return "JSON"
+		case "CITEXT":
+			return "TEXT"
+		case "UUID":
+			return "CHAR(36)"
+		case "ENUM":
+			if len(field.EnumValues) > 0 {
+				return fmt.Sprintf("ENUM(%s)", quoteValues(field.EnumValues))
+			}
+			return "VARCHAR(255)"
+		case "SET":
+			return "VARCHAR(255)"
+		case "VARCHAR":
+			if size == "" {
+				size = "255"
+			}
+		}
+		unsigned := field.Unsigned && (integer || sqlType == "DECIMAL" || sqlType == "NUMERIC")
+		if size != "" {
+			sqlType += "(" + size + ")"
+		}
+		if unsigned {
+			sqlType += " UNSIGNED"
+		}
+		return sqlType
+	}
+
+	if size != "" {
+		sqlType += "(" + size + ")"
+	}
+	return sqlType
+}
+
+// postgresIntegerType returns the PostgreSQL integer type for an integer column.
+// Unsigned columns are widened to hold their full range, and auto-increment
+// columns use the matching SERIAL type.
+func postgresIntegerType(sqlType string, unsigned, autoIncrement bool) string {
+	bits := 32
+	switch sqlType {
+	case "TINYINT", "SMALLINT", "INT2", "SMALLSERIAL":
+		bits = 16
+	case "BIGINT", "INT8", "BIGSERIAL":
+		bits = 64
+	}
+	if unsigned && sqlType != "TINYINT" {
+		bits *= 2
+	}
+
+	switch {
+	case bits > 64 && autoIncrement:
+		// An unsigned BIGINT key fits BIGSERIAL's range in practice
+		return "BIGSERIAL"
+	case bits > 64:
+		return "NUMERIC(20)"
+	case bits == 64 && autoIncrement:
+		return "BIGSERIAL"
+	case bits == 64:
+		return "BIGINT"
+	case bits == 32 && autoIncrement:
+		return "SERIAL"
+	case bits == 32:
+		return "INTEGER"
+	case autoIncrement:
+		return "SMALLSERIAL"
+	default:
+		return "SMALLINT"
+	}
+}
+
+// isIntegerSQLType reports whether an upper-case SQL type is an integer type
+func isIntegerSQLType(sqlType string) bool {
+	switch sqlType {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT",
+		"INT2", "INT4", "INT8", "SMALLSERIAL", "SERIAL", "BIGSERIAL":
+		return true
+	}
+	return false
+}
+
+// quoteIdent quotes an identifier for dialect when it is not a plain lower-case name
+func quoteIdent(name string, dialect Dialect) string {
+	if simpleIdentRegex.MatchString(name) && !reservedWords[name] {
+		return name
+	}
+	if dialect == DialectMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteIdents quotes and joins a list of identifiers
+func quoteIdents(names []string, dialect Dialect) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteIdent(name, dialect)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// quoteValues quotes and joins a list of string literals
+func quoteValues(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGenerateDDL_Dialects tests column types, keys and quoting for each dialect
+func TestGenerateDDL_Dialects(t *testing.T) {
+	structs, err := ParseSQL(`
+		CREATE TABLE users (
+			id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+			email VARCHAR(100) NOT NULL,
+			active TINYINT(1) NOT NULL,
+			status ENUM('active', 'it''s') NOT NULL,
+			avatar BLOB,
+			created_at DATETIME NOT NULL,
+			PRIMARY KEY (id)
+		);
+		CREATE TABLE orders (
+			id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			user_id BIGINT UNSIGNED NOT NULL,
+			` + "`order`" + ` INT,
+			CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id)
+		);`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{DialectMySQL, `CREATE TABLE users (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    email VARCHAR(100) NOT NULL,
+    active TINYINT(1) NOT NULL,
+    status ENUM('active', 'it''s') NOT NULL,
+    avatar BLOB,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE orders (
+    id INT NOT NULL AUTO_INCREMENT,
+    user_id BIGINT UNSIGNED NOT NULL,
+    ` + "`order`" + ` INT,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+`},
+		{DialectPostgres, `CREATE TABLE users (
+    id BIGSERIAL NOT NULL,
+    email VARCHAR(100) NOT NULL,
+    active BOOLEAN NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('active', 'it''s')),
+    avatar BYTEA,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE orders (
+    id SERIAL NOT NULL,
+    user_id NUMERIC(20) NOT NULL,
+    "order" INTEGER,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+`},
+		{DialectSQLite, `CREATE TABLE users (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    email VARCHAR(100) NOT NULL,
+    active TINYINT(1) NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('active', 'it''s')),
+    avatar BLOB,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE orders (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    user_id BIGINT NOT NULL,
+    "order" INT,
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dialect), func(t *testing.T) {
+			if ddl := GenerateDDL(structs, tt.dialect); ddl != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, ddl)
+			}
+		})
+	}
+}
+
+// TestGenerateDDL_RoundTrip tests that DDL generated from Go structs parses back to the same structs
+func TestGenerateDDL_RoundTrip(t *testing.T) {
+	src := `package models
+
+type Users struct {
+	Id        int64   ` + "`json:\"id\"`" + `
+	Username  string  ` + "`json:\"username\"`" + `
+	Email     *string ` + "`json:\"email\"`" + `
+	Score     float64 ` + "`json:\"score\"`" + `
+	CreatedAt time.Time ` + "`json:\"created_at\"`" + `
+}
+`
+	structs, _, err := ParseGoStructs(src)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	for _, dialect := range []Dialect{DialectMySQL, DialectPostgres} {
+		ddl := GenerateDDL(structs, dialect)
+		parsed, warnings, err := ParseSQLWithOptions(ddl, ParseOptions{Dialect: dialect})
+		if err != nil || len(warnings) != 0 {
+			t.Fatalf("%s: expected the DDL to parse, got %v %v:\n%s", dialect, err, warnings, ddl)
+		}
+
+		code := GenerateGoCode(parsed, Config{AddJSONTag: true})
+		for _, line := range []string{"Id        int64", "Username  string", "Email     *string", "Score     float64", "CreatedAt time.Time"} {
+			if !strings.Contains(code, line) {
+				t.Errorf("%s: expected %q in round-tripped code, got:\n%s", dialect, line, code)
+			}
+		}
+	}
+}
+
+// TestGenerateDDL_PostgresUnsignedKeys tests that unsigned Go keys become BIGSERIAL in PostgreSQL
+func TestGenerateDDL_PostgresUnsignedKeys(t *testing.T) {
+	src := `package models
+
+type User struct {
+	gorm.Model
+	Name string
+}
+
+type Tag struct {
+	ID   uint
+	Name string
+}
+`
+	structs, _, err := ParseGoStructs(src)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	ddl := GenerateDDL(structs, DialectPostgres)
+	if strings.Count(ddl, "id BIGSERIAL NOT NULL") != 2 || strings.Contains(ddl, "NUMERIC") {
+		t.Errorf("Expected BIGSERIAL keys, got:\n%s", ddl)
+	}
+}
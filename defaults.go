@@ -0,0 +1,170 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Pre-compiled regex patterns for DEFAULT values
+var (
+	// Defaults that evaluate to the current time
+	currentTimeDefaultRegex = regexp.MustCompile(`(?i)^\(?\s*(?:CURRENT_TIMESTAMP|LOCALTIMESTAMP|NOW\s*\(\s*\)|DATETIME\s*\(\s*'now'\s*\))(?:\s*\(\s*\d*\s*\))?\s*\)?$`)
+	// Decimal number literal, valid in both SQL and Go
+	numberLiteralRegex = regexp.MustCompile(`^[+-]?(?:\d+\.?\d*|\.\d+)(?:[eE][+-]?\d+)?$`)
+)
+
+// parseDefault returns the expression of the DEFAULT clause of a column definition
+// as written, such as 'pending', 0 or CURRENT_TIMESTAMP, without PostgreSQL casts
+// like ::text. It returns "" without a DEFAULT clause and for DEFAULT NULL.
+func parseDefault(definition string) string {
+	upper := strings.ToUpper(definition)
+	for i := 0; i < len(definition); i++ {
+		switch definition[i] {
+		case '\'', '"', '`':
+			i = closingQuote(definition, i)
+			continue
+		}
+		if !strings.HasPrefix(upper[i:], "DEFAULT") || !isWordBoundary(definition, i, i+len("DEFAULT")) {
+			continue
+		}
+		// GENERATED BY DEFAULT AS IDENTITY is not a default value
+		if fields := strings.Fields(upper[:i]); len(fields) > 0 && fields[len(fields)-1] == "BY" {
+			continue
+		}
+
+		value := readDefaultValue(definition[i+len("DEFAULT"):])
+		if strings.EqualFold(value, "NULL") {
+			return ""
+		}
+		return value
+	}
+	return ""
+}
+
+// readDefaultValue reads the expression at the start of s: a string literal,
+// a parenthesized expression, or a word such as a number, keyword or function call.
+// Trailing type casts are dropped.
+func readDefaultValue(s string) string {
+	s = strings.TrimLeft(s, " \t\r\n")
+	if s == "" {
+		return ""
+	}
+
+	end := 0
+	switch s[0] {
+	case '\'', '"':
+		end = closingQuote(s, 0) + 1
+	case '(':
+		end = closingParen(s, 0) + 1
+	default:
+		for end < len(s) && !strings.ContainsRune(" \t\r\n,()'", rune(s[end])) && !strings.HasPrefix(s[end:], "::") {
+			end++
+		}
+		// Function call such as now() or nextval('seq')
+		if end < len(s) && s[end] == '(' {
+			end = closingParen(s, end) + 1
+		}
+		// Prefixed literals such as b'1' or E'text'
+		if end < len(s) && s[end] == '\'' {
+			end = closingQuote(s, end) + 1
+		}
+	}
+	if end > len(s) {
+		end = len(s)
+	}
+	return strings.TrimSpace(s[:end])
+}
+
+// closingQuote returns the position of the quote closing the one at start,
+// skipping doubled quotes, or the last position if it is not closed
+func closingQuote(s string, start int) int {
+	quote := s[start]
+	for i := start + 1; i < len(s); i++ {
+		if s[i] != quote {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == quote {
+			i++
+			continue
+		}
+		return i
+	}
+	return len(s) - 1
+}
+
+// closingParen returns the position of the parenthesis closing the one at start,
+// skipping quoted strings, or the last position if it is not closed
+func closingParen(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\'', '"', '`':
+			i = closingQuote(s, i)
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s) - 1
+}
+
+// isWordBoundary reports whether s[start:end] is a whole word
+func isWordBoundary(s string, start, end int) bool {
+	isWord := func(c byte) bool {
+		return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+	}
+	return (start == 0 || !isWord(s[start-1])) && (end >= len(s) || !isWord(s[end]))
+}
+
+// goDefaultLiteral converts the literal DEFAULT of a column to a Go literal of the
+// field's type, such as "pending" (with quotes), 0 or true. It reports false for
+// function and expression defaults such as NOW() and for types without literals.
+func goDefaultLiteral(field FieldDef) (string, bool) {
+	value := field.Default
+	quoted := strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") && len(value) >= 2
+	if quoted {
+		value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+
+	switch baseType := strings.TrimPrefix(field.Type, "*"); baseType {
+	case "string":
+		if quoted {
+			return strconv.Quote(value), true
+		}
+		// Numbers are accepted as string defaults
+		if numberLiteralRegex.MatchString(value) {
+			return strconv.Quote(value), true
+		}
+	case "bool":
+		switch strings.ToLower(value) {
+		case "true", "1", "t", "yes", "on":
+			return "true", true
+		case "false", "0", "f", "no", "off":
+			return "false", true
+		}
+	case "int", "int8", "int16", "int32", "int64":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return strconv.FormatInt(n, 10), true
+		}
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		if n, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return strconv.FormatUint(n, 10), true
+		}
+	case "float32", "float64":
+		if numberLiteralRegex.MatchString(value) {
+			return strings.TrimPrefix(value, "+"), true
+		}
+	}
+	return "", false
+}
+
+// isCurrentTimeDefault reports whether a DEFAULT expression evaluates to the current
+// time, such as CURRENT_TIMESTAMP or NOW()
+func isCurrentTimeDefault(value string) bool {
+	return currentTimeDefaultRegex.MatchString(value)
+}
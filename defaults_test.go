@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseDefault tests extracting DEFAULT expressions from column definitions
+func TestParseDefault(t *testing.T) {
+	tests := []struct {
+		definition string
+		expected   string
+	}{
+		{"VARCHAR(20) NOT NULL DEFAULT 'pending'", "'pending'"},
+		{"VARCHAR(20) DEFAULT 'it''s' COMMENT 'the default'", "'it''s'"},
+		{"INT NOT NULL DEFAULT 0", "0"},
+		{"INT DEFAULT -1 CHECK (x > -2)", "-1"},
+		{"BOOLEAN DEFAULT TRUE NOT NULL", "TRUE"},
+		{"TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP"},
+		{"TIMESTAMPTZ DEFAULT now()", "now()"},
+		{"TEXT DEFAULT 'x'::text", "'x'"},
+		{"TEXT DEFAULT (datetime('now'))", "(datetime('now'))"},
+		{"VARCHAR(20) DEFAULT NULL", ""},
+		{"INT GENERATED BY DEFAULT AS IDENTITY", ""},
+		{"VARCHAR(20) COMMENT 'no default here'", ""},
+	}
+
+	for _, tt := range tests {
+		if got := parseDefault(tt.definition); got != tt.expected {
+			t.Errorf("parseDefault(%q): expected %q, got %q", tt.definition, tt.expected, got)
+		}
+	}
+}
+
+// TestGoDefaultLiteral tests converting literal defaults to Go literals of the field type
+func TestGoDefaultLiteral(t *testing.T) {
+	tests := []struct {
+		goType   string
+		value    string
+		expected string
+		ok       bool
+	}{
+		{"string", "'pending'", `"pending"`, true},
+		{"*string", "'say \"hi\"'", `"say \"hi\""`, true},
+		{"string", "42", `"42"`, true},
+		{"int", "0", "0", true},
+		{"int64", "'7'", "7", true},
+		{"uint8", "-1", "", false},
+		{"float64", "'0.00'", "0.00", true},
+		{"float64", "'NaN'", "", false},
+		{"bool", "TRUE", "true", true},
+		{"bool", "0", "false", true},
+		{"time.Time", "CURRENT_TIMESTAMP", "", false},
+		{"string", "uuid()", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := goDefaultLiteral(FieldDef{Type: tt.goType, Default: tt.value})
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("goDefaultLiteral(%s %s): expected %q, %v, got %q, %v", tt.goType, tt.value, tt.expected, tt.ok, got, ok)
+		}
+	}
+}
+
+// TestDefaults_SchemaAndDDL tests that defaults follow ALTER statements and are written back as DDL
+func TestDefaults_SchemaAndDDL(t *testing.T) {
+	structs, err := ParseSQL(`
+		CREATE TABLE orders (
+			status VARCHAR(20) NOT NULL DEFAULT 'new',
+			total DECIMAL(10,2) NOT NULL DEFAULT 0,
+			note TEXT DEFAULT 'none'
+		);
+		ALTER TABLE orders ALTER COLUMN status SET DEFAULT 'pending';
+		ALTER TABLE orders ALTER COLUMN note DROP DEFAULT;`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	fields := structs[0].Fields
+	if fields[0].Default != "'pending'" || fields[1].Default != "0" || fields[2].Default != "" {
+		t.Errorf("Expected the defaults 'pending', 0 and none, got %q, %q and %q", fields[0].Default, fields[1].Default, fields[2].Default)
+	}
+
+	ddl := GenerateDDL(structs, DialectPostgres)
+	if !strings.Contains(ddl, "status VARCHAR(20) NOT NULL DEFAULT 'pending'") {
+		t.Errorf("Expected the default in the DDL, got:\n%s", ddl)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect identifies the SQL dialect of a schema
+type Dialect string
+
+// Supported SQL dialects
+const (
+	DialectMySQL    Dialect = "mysql"
+	DialectPostgres Dialect = "postgres"
+	DialectSQLite   Dialect = "sqlite"
+)
+
+// ParseDialect converts a dialect name (case-insensitive, with common aliases) to a Dialect.
+// An empty name selects MySQL.
+func ParseDialect(name string) (Dialect, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "mysql", "mariadb":
+		return DialectMySQL, nil
+	case "postgres", "postgresql", "pg":
+		return DialectPostgres, nil
+	case "sqlite", "sqlite3":
+		return DialectSQLite, nil
+	default:
+		return "", fmt.Errorf("unknown dialect %q (expected mysql, postgres or sqlite)", name)
+	}
+}
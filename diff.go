@@ -0,0 +1,512 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SchemaDiff is the structural difference between two versions of a schema
+type SchemaDiff struct {
+	AddedTables   []StructDef
+	DroppedTables []StructDef
+	ChangedTables []TableDiff
+}
+
+// TableDiff is the difference between two versions of a table
+type TableDiff struct {
+	Old StructDef
+	New StructDef
+
+	AddedColumns   []FieldDef
+	DroppedColumns []FieldDef
+	ChangedColumns []ColumnChange
+
+	AddedIndexes   []Index
+	DroppedIndexes []Index
+
+	AddedForeignKeys   []ForeignKey
+	DroppedForeignKeys []ForeignKey
+
+	PrimaryKeyChanged bool
+}
+
+// ColumnChange is a column whose type, nullability or default changed
+type ColumnChange struct {
+	Old                FieldDef
+	New                FieldDef
+	TypeChanged        bool
+	NullabilityChanged bool
+	DefaultChanged     bool
+}
+
+// DiffSchemas compares two versions of a schema.
+// Tables and columns are matched by name (case-insensitive), so a renamed column
+// shows up as dropped and added. Indexes and foreign keys are matched by their
+// columns, ignoring their names. Association fields are not columns and are ignored.
+func DiffSchemas(oldDefs, newDefs []StructDef) SchemaDiff {
+	var diff SchemaDiff
+
+	for _, newDef := range newDefs {
+		oldDef := findTable(oldDefs, newDef.TableName)
+		if oldDef == nil {
+			diff.AddedTables = append(diff.AddedTables, newDef)
+			continue
+		}
+		if table := diffTable(*oldDef, newDef); !table.IsEmpty() {
+			diff.ChangedTables = append(diff.ChangedTables, table)
+		}
+	}
+
+	for _, oldDef := range oldDefs {
+		if findTable(newDefs, oldDef.TableName) == nil {
+			diff.DroppedTables = append(diff.DroppedTables, oldDef)
+		}
+	}
+
+	return diff
+}
+
+// diffTable compares two versions of a table
+func diffTable(oldDef, newDef StructDef) TableDiff {
+	table := TableDiff{Old: oldDef, New: newDef}
+
+	for _, field := range newDef.Fields {
+		if field.ColumnName == "" {
+			continue
+		}
+		i := fieldIndex(oldDef.Fields, field.ColumnName)
+		if i == -1 {
+			table.AddedColumns = append(table.AddedColumns, field)
+			continue
+		}
+
+		change := ColumnChange{
+			Old:                oldDef.Fields[i],
+			New:                field,
+			TypeChanged:        !strings.EqualFold(describeColumnType(oldDef.Fields[i]), describeColumnType(field)),
+			NullabilityChanged: isNullableColumn(oldDef.Fields[i]) != isNullableColumn(field),
+			DefaultChanged:     strings.TrimSpace(oldDef.Fields[i].Default) != strings.TrimSpace(field.Default),
+		}
+		if change.TypeChanged || change.NullabilityChanged || change.DefaultChanged {
+			table.ChangedColumns = append(table.ChangedColumns, change)
+		}
+	}
+	for _, field := range oldDef.Fields {
+		if field.ColumnName != "" && fieldIndex(newDef.Fields, field.ColumnName) == -1 {
+			table.DroppedColumns = append(table.DroppedColumns, field)
+		}
+	}
+
+	for _, index := range newDef.Indexes {
+		if !containsIndex(oldDef.Indexes, index) {
+			table.AddedIndexes = append(table.AddedIndexes, index)
+		}
+	}
+	for _, index := range oldDef.Indexes {
+		if !containsIndex(newDef.Indexes, index) {
+			table.DroppedIndexes = append(table.DroppedIndexes, index)
+		}
+	}
+
+	for _, fk := range newDef.ForeignKeys {
+		if !containsForeignKey(oldDef.ForeignKeys, fk) {
+			table.AddedForeignKeys = append(table.AddedForeignKeys, fk)
+		}
+	}
+	for _, fk := range oldDef.ForeignKeys {
+		if !containsForeignKey(newDef.ForeignKeys, fk) {
+			table.DroppedForeignKeys = append(table.DroppedForeignKeys, fk)
+		}
+	}
+
+	table.PrimaryKeyChanged = !equalFold(primaryKeyColumns(oldDef), primaryKeyColumns(newDef))
+
+	return table
+}
+
+// IsEmpty reports whether the schemas are structurally identical
+func (d SchemaDiff) IsEmpty() bool {
+	return len(d.AddedTables) == 0 && len(d.DroppedTables) == 0 && len(d.ChangedTables) == 0
+}
+
+// IsEmpty reports whether the table is unchanged
+func (t TableDiff) IsEmpty() bool {
+	return len(t.AddedColumns) == 0 && len(t.DroppedColumns) == 0 && len(t.ChangedColumns) == 0 &&
+		len(t.AddedIndexes) == 0 && len(t.DroppedIndexes) == 0 &&
+		len(t.AddedForeignKeys) == 0 && len(t.DroppedForeignKeys) == 0 && !t.PrimaryKeyChanged
+}
+
+// Report returns a human-readable summary of the diff, one line per change:
+// "+" for added, "-" for dropped and "~" for changed tables, columns and keys.
+func (d SchemaDiff) Report() string {
+	if d.IsEmpty() {
+		return "No schema changes\n"
+	}
+
+	var output strings.Builder
+	for _, def := range d.AddedTables {
+		output.WriteString(fmt.Sprintf("+ table %s\n", def.TableName))
+	}
+	for _, def := range d.DroppedTables {
+		output.WriteString(fmt.Sprintf("- table %s\n", def.TableName))
+	}
+
+	for _, table := range d.ChangedTables {
+		output.WriteString(fmt.Sprintf("~ table %s\n", table.New.TableName))
+		for _, field := range table.AddedColumns {
+			output.WriteString(fmt.Sprintf("    + column %s %s\n", field.ColumnName, describeColumn(field)))
+		}
+		for _, field := range table.DroppedColumns {
+			output.WriteString(fmt.Sprintf("    - column %s %s\n", field.ColumnName, describeColumn(field)))
+		}
+		for _, change := range table.ChangedColumns {
+			output.WriteString(fmt.Sprintf("    ~ column %s %s -> %s\n", change.New.ColumnName, describeColumn(change.Old), describeColumn(change.New)))
+		}
+		if table.PrimaryKeyChanged {
+			output.WriteString(fmt.Sprintf("    ~ primary key (%s) -> (%s)\n",
+				strings.Join(primaryKeyColumns(table.Old), ", "), strings.Join(primaryKeyColumns(table.New), ", ")))
+		}
+		for _, index := range table.AddedIndexes {
+			output.WriteString("    + " + describeIndex(index) + "\n")
+		}
+		for _, index := range table.DroppedIndexes {
+			output.WriteString("    - " + describeIndex(index) + "\n")
+		}
+		for _, fk := range table.AddedForeignKeys {
+			output.WriteString("    + " + describeForeignKey(fk) + "\n")
+		}
+		for _, fk := range table.DroppedForeignKeys {
+			output.WriteString("    - " + describeForeignKey(fk) + "\n")
+		}
+	}
+
+	return output.String()
+}
+
+// GenerateMigration compares two versions of a schema and returns the statements
+// migrating from oldDefs to newDefs (up) and back (down) in the given dialect.
+// Foreign keys are dropped first and added last, so tables can be created and
+// dropped in any order. SQLite cannot alter column types or constraints, so its
+// tables with such changes are rebuilt: the new table is created, the common
+// columns are copied, and it replaces the old table.
+func GenerateMigration(oldDefs, newDefs []StructDef, dialect Dialect) (up, down string) {
+	return migrationSQL(DiffSchemas(oldDefs, newDefs), dialect), migrationSQL(DiffSchemas(newDefs, oldDefs), dialect)
+}
+
+// migrationSQL generates the statements applying diff
+func migrationSQL(diff SchemaDiff, dialect Dialect) string {
+	var output strings.Builder
+
+	rebuild := make(map[string]bool)
+	if dialect == DialectSQLite {
+		for _, table := range diff.ChangedTables {
+			rebuild[table.New.TableName] = needsRebuild(table)
+		}
+	}
+
+	// SQLite has no ALTER TABLE for constraints; its foreign keys stay in CREATE TABLE
+	if dialect != DialectSQLite {
+		for _, table := range diff.ChangedTables {
+			for _, fk := range table.DroppedForeignKeys {
+				output.WriteString(dropForeignKeyDDL(table.Old.TableName, fk, dialect))
+			}
+		}
+		for _, def := range diff.DroppedTables {
+			for _, fk := range def.ForeignKeys {
+				output.WriteString(dropForeignKeyDDL(def.TableName, fk, dialect))
+			}
+		}
+	}
+
+	for _, table := range diff.ChangedTables {
+		if rebuild[table.New.TableName] {
+			continue
+		}
+		for _, index := range table.DroppedIndexes {
+			output.WriteString(dropIndexDDL(table.Old.TableName, index, dialect))
+		}
+	}
+
+	for _, def := range diff.AddedTables {
+		if dialect != DialectSQLite {
+			def.ForeignKeys = nil
+		}
+		output.WriteString(createTableDDL(def, dialect))
+		for _, index := range def.Indexes {
+			output.WriteString(createIndexDDL(def.TableName, index, dialect))
+		}
+	}
+
+	for _, table := range diff.ChangedTables {
+		if rebuild[table.New.TableName] {
+			output.WriteString(rebuildTableSQL(table, dialect))
+			continue
+		}
+		output.WriteString(alterTableSQL(table, dialect))
+		for _, index := range table.AddedIndexes {
+			output.WriteString(createIndexDDL(table.New.TableName, index, dialect))
+		}
+	}
+
+	if dialect != DialectSQLite {
+		for _, def := range diff.AddedTables {
+			for _, fk := range def.ForeignKeys {
+				output.WriteString(addForeignKeyDDL(def.TableName, fk, dialect))
+			}
+		}
+		for _, table := range diff.ChangedTables {
+			for _, fk := range table.AddedForeignKeys {
+				output.WriteString(addForeignKeyDDL(table.New.TableName, fk, dialect))
+			}
+		}
+	}
+
+	for _, def := range diff.DroppedTables {
+		output.WriteString(fmt.Sprintf("DROP TABLE %s;\n", quoteIdent(def.TableName, dialect)))
+	}
+
+	return output.String()
+}
+
+// alterTableSQL generates the ALTER TABLE statements for the column and primary key changes of a table
+func alterTableSQL(table TableDiff, dialect Dialect) string {
+	var output strings.Builder
+	name := quoteIdent(table.New.TableName, dialect)
+
+	if oldKey := primaryKeyColumns(table.Old); table.PrimaryKeyChanged && len(oldKey) > 0 {
+		if dialect == DialectMySQL {
+			output.WriteString(fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY;\n", name))
+		} else {
+			// PostgreSQL's default name for the primary key constraint
+			output.WriteString(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;\n", name, quoteIdent(table.Old.TableName+"_pkey", dialect)))
+		}
+	}
+
+	for _, field := range table.AddedColumns {
+		output.WriteString(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;\n", name, columnDDL(field, false, dialect)))
+	}
+	for _, change := range table.ChangedColumns {
+		output.WriteString(alterColumnSQL(name, change, dialect))
+	}
+	for _, field := range table.DroppedColumns {
+		output.WriteString(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;\n", name, quoteIdent(field.ColumnName, dialect)))
+	}
+
+	if newKey := primaryKeyColumns(table.New); table.PrimaryKeyChanged && len(newKey) > 0 {
+		output.WriteString(fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s);\n", name, quoteIdents(newKey, dialect)))
+	}
+
+	return output.String()
+}
+
+// alterColumnSQL generates the statements changing the type, nullability or default of a column
+func alterColumnSQL(table string, change ColumnChange, dialect Dialect) string {
+	if dialect == DialectMySQL {
+		return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;\n", table, columnDDL(change.New, false, dialect))
+	}
+
+	var output strings.Builder
+	column := quoteIdent(change.New.ColumnName, dialect)
+	if change.TypeChanged {
+		// SERIAL is not a type; the column keeps its sequence
+		field := change.New
+		field.AutoIncrement = false
+		output.WriteString(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s;\n", table, column, columnTypeDDL(field, dialect)))
+	}
+	if change.NullabilityChanged {
+		action := "SET NOT NULL"
+		if isNullableColumn(change.New) {
+			action = "DROP NOT NULL"
+		}
+		output.WriteString(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s;\n", table, column, action))
+	}
+	if change.DefaultChanged {
+		action := "DROP DEFAULT"
+		if change.New.Default != "" {
+			action = "SET DEFAULT " + change.New.Default
+		}
+		output.WriteString(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s;\n", table, column, action))
+	}
+	return output.String()
+}
+
+// rebuildTableSQL recreates a SQLite table with its new definition, keeping the data of common columns
+func rebuildTableSQL(table TableDiff, dialect Dialect) string {
+	var output strings.Builder
+
+	temp := table.New
+	temp.TableName = "new_" + table.New.TableName
+	output.WriteString(createTableDDL(temp, dialect))
+
+	var columns []string
+	for _, field := range table.New.Fields {
+		if field.ColumnName != "" && fieldIndex(table.Old.Fields, field.ColumnName) != -1 {
+			columns = append(columns, field.ColumnName)
+		}
+	}
+	if len(columns) > 0 {
+		output.WriteString(fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s;\n", quoteIdent(temp.TableName, dialect),
+			quoteIdents(columns, dialect), quoteIdents(columns, dialect), quoteIdent(table.Old.TableName, dialect)))
+	}
+	output.WriteString(fmt.Sprintf("DROP TABLE %s;\n", quoteIdent(table.Old.TableName, dialect)))
+	output.WriteString(fmt.Sprintf("ALTER TABLE %s RENAME TO %s;\n", quoteIdent(temp.TableName, dialect), quoteIdent(table.New.TableName, dialect)))
+
+	// Dropping the old table dropped its indexes
+	for _, index := range table.New.Indexes {
+		output.WriteString(createIndexDDL(table.New.TableName, index, dialect))
+	}
+
+	return output.String()
+}
+
+// needsRebuild reports whether a SQLite table change goes beyond adding and dropping
+// columns and named indexes
+func needsRebuild(table TableDiff) bool {
+	if len(table.ChangedColumns) > 0 || table.PrimaryKeyChanged ||
+		len(table.AddedForeignKeys) > 0 || len(table.DroppedForeignKeys) > 0 {
+		return true
+	}
+	// Unnamed UNIQUE constraints are automatic indexes that cannot be dropped
+	for _, index := range table.DroppedIndexes {
+		if index.Name == "" {
+			return true
+		}
+	}
+	// Columns with constraints cannot be added by ALTER TABLE
+	for _, field := range table.AddedColumns {
+		if field.PrimaryKey {
+			return true
+		}
+	}
+	return false
+}
+
+// foreignKeyName returns the name of a foreign key, deriving PostgreSQL's
+// default name (orders_user_id_fkey) for unnamed ones
+func foreignKeyName(table string, fk ForeignKey) string {
+	if fk.Name != "" {
+		return fk.Name
+	}
+	return table + "_" + strings.Join(fk.Columns, "_") + "_fkey"
+}
+
+// addForeignKeyDDL generates the statement adding a foreign key to a table
+func addForeignKeyDDL(table string, fk ForeignKey, dialect Dialect) string {
+	fk.Name = foreignKeyName(table, fk)
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;\n", quoteIdent(table, dialect), foreignKeyDDL(fk, dialect))
+}
+
+// dropForeignKeyDDL generates the statement dropping a foreign key from a table
+func dropForeignKeyDDL(table string, fk ForeignKey, dialect Dialect) string {
+	action := "DROP CONSTRAINT"
+	if dialect == DialectMySQL {
+		action = "DROP FOREIGN KEY"
+	}
+	return fmt.Sprintf("ALTER TABLE %s %s %s;\n", quoteIdent(table, dialect), action, quoteIdent(foreignKeyName(table, fk), dialect))
+}
+
+// describeColumn describes the type, nullability and default of a column, as in
+// "VARCHAR(10) NOT NULL DEFAULT 'new'"
+func describeColumn(field FieldDef) string {
+	description := describeColumnType(field) + " NOT NULL"
+	if isNullableColumn(field) {
+		description = describeColumnType(field) + " NULL"
+	}
+	if field.Default != "" {
+		description += " DEFAULT " + field.Default
+	}
+	return description
+}
+
+// describeColumnType describes the type of a column as declared, as in "INT UNSIGNED"
+func describeColumnType(field FieldDef) string {
+	description := strings.ToUpper(field.SQLType)
+	switch {
+	case len(field.EnumValues) > 0:
+		description += "(" + quoteValues(field.EnumValues) + ")"
+	case field.Size != "":
+		description += "(" + field.Size + ")"
+	}
+	if field.Unsigned {
+		description += " UNSIGNED"
+	}
+	return description
+}
+
+// describeIndex describes an index, as in "unique index idx_email (email)"
+func describeIndex(index Index) string {
+	description := "index"
+	if index.Unique {
+		description = "unique index"
+	}
+	if index.Name != "" {
+		description += " " + index.Name
+	}
+	columns := make([]string, len(index.Columns))
+	for i, column := range index.Columns {
+		columns[i] = column
+		if length := indexLength(index, i); length > 0 {
+			columns[i] += fmt.Sprintf("(%d)", length)
+		}
+	}
+	return description + " (" + strings.Join(columns, ", ") + ")"
+}
+
+// describeForeignKey describes a foreign key, as in "foreign key (user_id) references users (id)"
+func describeForeignKey(fk ForeignKey) string {
+	description := fmt.Sprintf("foreign key (%s) references %s", strings.Join(fk.Columns, ", "), fk.RefTable)
+	if len(fk.RefColumns) > 0 {
+		description += " (" + strings.Join(fk.RefColumns, ", ") + ")"
+	}
+	return description
+}
+
+// isNullableColumn reports whether a column accepts NULL; primary key columns never do
+func isNullableColumn(field FieldDef) bool {
+	return field.Nullable && !field.PrimaryKey
+}
+
+// containsIndex reports whether indexes has an index on the same columns, with the same
+// prefix lengths and uniqueness
+func containsIndex(indexes []Index, index Index) bool {
+	for _, other := range indexes {
+		if other.Unique == index.Unique && equalFold(other.Columns, index.Columns) && equalLengths(other, index) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsForeignKey reports whether fks has a foreign key with the same columns and references
+func containsForeignKey(fks []ForeignKey, fk ForeignKey) bool {
+	for _, other := range fks {
+		if equalFold(other.Columns, fk.Columns) && strings.EqualFold(other.RefTable, fk.RefTable) &&
+			equalFold(other.RefColumns, fk.RefColumns) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalLengths reports whether two indexes on the same columns have the same prefix lengths
+func equalLengths(a, b Index) bool {
+	for i := range a.Columns {
+		if indexLength(a, i) != indexLength(b, i) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalFold reports whether two identifier lists are equal, ignoring case
+func equalFold(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
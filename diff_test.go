@@ -0,0 +1,306 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const diffOldSQL = `
+CREATE TABLE users (
+	id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	email VARCHAR(100) NOT NULL,
+	nickname VARCHAR(50),
+	KEY idx_nickname (nickname)
+);
+CREATE TABLE sessions (
+	id INT NOT NULL PRIMARY KEY,
+	user_id INT NOT NULL,
+	FOREIGN KEY (user_id) REFERENCES users (id)
+);`
+
+const diffNewSQL = `
+CREATE TABLE users (
+	id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	email VARCHAR(255) NULL UNIQUE,
+	age INT
+);
+CREATE TABLE orders (
+	id INT NOT NULL PRIMARY KEY,
+	user_id INT NOT NULL,
+	CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id)
+);`
+
+// parseDiffSchemas parses the old and new schemas of the diff tests
+func parseDiffSchemas(t *testing.T) ([]StructDef, []StructDef) {
+	t.Helper()
+	oldDefs, err := ParseSQL(diffOldSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	newDefs, err := ParseSQL(diffNewSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	return oldDefs, newDefs
+}
+
+// TestDiffSchemas tests the structural diff and its report
+func TestDiffSchemas(t *testing.T) {
+	oldDefs, newDefs := parseDiffSchemas(t)
+
+	diff := DiffSchemas(oldDefs, newDefs)
+	if len(diff.AddedTables) != 1 || diff.AddedTables[0].TableName != "orders" {
+		t.Errorf("Expected orders to be added, got %+v", diff.AddedTables)
+	}
+	if len(diff.DroppedTables) != 1 || diff.DroppedTables[0].TableName != "sessions" {
+		t.Errorf("Expected sessions to be dropped, got %+v", diff.DroppedTables)
+	}
+	if len(diff.ChangedTables) != 1 {
+		t.Fatalf("Expected 1 changed table, got %d", len(diff.ChangedTables))
+	}
+
+	users := diff.ChangedTables[0]
+	if len(users.ChangedColumns) != 1 || !users.ChangedColumns[0].TypeChanged || !users.ChangedColumns[0].NullabilityChanged {
+		t.Errorf("Expected a type and nullability change of email, got %+v", users.ChangedColumns)
+	}
+	if users.PrimaryKeyChanged {
+		t.Error("Expected the primary key to be unchanged")
+	}
+
+	expected := `+ table orders
+- table sessions
+~ table users
+    + column age INT NULL
+    - column nickname VARCHAR(50) NULL
+    ~ column email VARCHAR(100) NOT NULL -> VARCHAR(255) NULL
+    + unique index (email)
+    - index idx_nickname (nickname)
+`
+	if report := diff.Report(); report != expected {
+		t.Errorf("Expected report:\n%s\ngot:\n%s", expected, report)
+	}
+
+	if diff := DiffSchemas(newDefs, newDefs); !diff.IsEmpty() || diff.Report() != "No schema changes\n" {
+		t.Errorf("Expected no changes between identical schemas, got:\n%s", diff.Report())
+	}
+}
+
+// TestDiffSchemas_PrimaryKeyAndForeignKeys tests primary key and foreign key changes
+func TestDiffSchemas_PrimaryKeyAndForeignKeys(t *testing.T) {
+	oldDefs, err := ParseSQL(`CREATE TABLE items (id INT PRIMARY KEY, tenant_id INT NOT NULL, owner_id INT REFERENCES users (id))`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	newDefs, err := ParseSQL(`CREATE TABLE items (id INT NOT NULL, tenant_id INT NOT NULL, owner_id INT,
+		PRIMARY KEY (id, tenant_id), FOREIGN KEY (tenant_id) REFERENCES tenants (id))`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	diff := DiffSchemas(oldDefs, newDefs)
+	if len(diff.ChangedTables) != 1 {
+		t.Fatalf("Expected 1 changed table, got %d", len(diff.ChangedTables))
+	}
+	items := diff.ChangedTables[0]
+	if !items.PrimaryKeyChanged {
+		t.Error("Expected the primary key to change")
+	}
+	if len(items.ChangedColumns) != 0 {
+		t.Errorf("Expected a primary key column to stay NOT NULL, got %+v", items.ChangedColumns)
+	}
+	if len(items.AddedForeignKeys) != 1 || len(items.DroppedForeignKeys) != 1 {
+		t.Errorf("Expected 1 added and 1 dropped foreign key, got %+v", items)
+	}
+
+	up, _ := GenerateMigration(oldDefs, newDefs, DialectPostgres)
+	expected := `ALTER TABLE items DROP CONSTRAINT items_owner_id_fkey;
+ALTER TABLE items DROP CONSTRAINT items_pkey;
+ALTER TABLE items ADD PRIMARY KEY (id, tenant_id);
+ALTER TABLE items ADD CONSTRAINT items_tenant_id_fkey FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+`
+	if up != expected {
+		t.Errorf("Expected up migration:\n%s\ngot:\n%s", expected, up)
+	}
+}
+
+// TestGenerateMigration tests up and down migrations in each dialect
+func TestGenerateMigration(t *testing.T) {
+	oldDefs, newDefs := parseDiffSchemas(t)
+
+	tests := []struct {
+		dialect Dialect
+		up      string
+		down    string
+	}{
+		{
+			dialect: DialectMySQL,
+			up: `ALTER TABLE sessions DROP FOREIGN KEY sessions_user_id_fkey;
+DROP INDEX idx_nickname ON users;
+CREATE TABLE orders (
+    id INT NOT NULL,
+    user_id INT NOT NULL,
+    PRIMARY KEY (id)
+);
+ALTER TABLE users ADD COLUMN age INT;
+ALTER TABLE users MODIFY COLUMN email VARCHAR(255);
+ALTER TABLE users DROP COLUMN nickname;
+CREATE UNIQUE INDEX email ON users (email);
+ALTER TABLE orders ADD CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id);
+DROP TABLE sessions;
+`,
+			down: `ALTER TABLE orders DROP FOREIGN KEY fk_orders_user;
+DROP INDEX email ON users;
+CREATE TABLE sessions (
+    id INT NOT NULL,
+    user_id INT NOT NULL,
+    PRIMARY KEY (id)
+);
+ALTER TABLE users ADD COLUMN nickname VARCHAR(50);
+ALTER TABLE users MODIFY COLUMN email VARCHAR(100) NOT NULL;
+ALTER TABLE users DROP COLUMN age;
+CREATE INDEX idx_nickname ON users (nickname);
+ALTER TABLE sessions ADD CONSTRAINT sessions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id);
+DROP TABLE orders;
+`,
+		},
+		{
+			dialect: DialectPostgres,
+			up: `ALTER TABLE sessions DROP CONSTRAINT sessions_user_id_fkey;
+DROP INDEX idx_nickname;
+CREATE TABLE orders (
+    id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    PRIMARY KEY (id)
+);
+ALTER TABLE users ADD COLUMN age INTEGER;
+ALTER TABLE users ALTER COLUMN email TYPE VARCHAR(255);
+ALTER TABLE users ALTER COLUMN email DROP NOT NULL;
+ALTER TABLE users DROP COLUMN nickname;
+CREATE UNIQUE INDEX users_email_key ON users (email);
+ALTER TABLE orders ADD CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id);
+DROP TABLE sessions;
+`,
+		},
+		{
+			dialect: DialectSQLite,
+			up: `CREATE TABLE orders (
+    id INT NOT NULL PRIMARY KEY,
+    user_id INT NOT NULL,
+    CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE TABLE new_users (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    email VARCHAR(255),
+    age INT
+);
+INSERT INTO new_users (id, email) SELECT id, email FROM users;
+DROP TABLE users;
+ALTER TABLE new_users RENAME TO users;
+CREATE UNIQUE INDEX users_email_key ON users (email);
+DROP TABLE sessions;
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		up, down := GenerateMigration(oldDefs, newDefs, tt.dialect)
+		if up != tt.up {
+			t.Errorf("Expected %s up migration:\n%s\ngot:\n%s", tt.dialect, tt.up, up)
+		}
+		if tt.down != "" && down != tt.down {
+			t.Errorf("Expected %s down migration:\n%s\ngot:\n%s", tt.dialect, tt.down, down)
+		}
+	}
+}
+
+// TestGenerateMigration_SQLiteAddColumn tests that SQLite only rebuilds tables when needed
+func TestGenerateMigration_SQLiteAddColumn(t *testing.T) {
+	oldDefs, _, err := ParseSQLWithOptions(`CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT)`, ParseOptions{Dialect: DialectSQLite})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	newDefs, _, err := ParseSQLWithOptions(`CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT, title TEXT);
+		CREATE INDEX idx_title ON notes (title);`, ParseOptions{Dialect: DialectSQLite})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	up, down := GenerateMigration(oldDefs, newDefs, DialectSQLite)
+	if expected := "ALTER TABLE notes ADD COLUMN title TEXT;\nCREATE INDEX idx_title ON notes (title);\n"; up != expected {
+		t.Errorf("Expected up migration:\n%s\ngot:\n%s", expected, up)
+	}
+	if expected := "DROP INDEX idx_title;\nALTER TABLE notes DROP COLUMN title;\n"; down != expected {
+		t.Errorf("Expected down migration:\n%s\ngot:\n%s", expected, down)
+	}
+}
+
+// TestGenerateMigration_PostgresUnsignedKey tests that a MySQL unsigned auto-increment key becomes BIGSERIAL
+func TestGenerateMigration_PostgresUnsignedKey(t *testing.T) {
+	newDefs, err := ParseSQL(`CREATE TABLE users (id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY, name VARCHAR(50))`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	up, _ := GenerateMigration(nil, newDefs, DialectPostgres)
+	if !strings.Contains(up, "id BIGSERIAL NOT NULL,") {
+		t.Errorf("Expected a BIGSERIAL key, got:\n%s", up)
+	}
+}
+
+// TestGenerateMigration_Default tests that changed and dropped column defaults are migrated
+func TestGenerateMigration_Default(t *testing.T) {
+	oldDefs, err := ParseSQL(`CREATE TABLE tasks (id INT NOT NULL PRIMARY KEY, status VARCHAR(10) NOT NULL DEFAULT 'a', note TEXT DEFAULT 'none')`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	newDefs, err := ParseSQL(`CREATE TABLE tasks (id INT NOT NULL PRIMARY KEY, status VARCHAR(10) NOT NULL DEFAULT 'b', note TEXT)`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	diff := DiffSchemas(oldDefs, newDefs)
+	expected := `~ table tasks
+    ~ column status VARCHAR(10) NOT NULL DEFAULT 'a' -> VARCHAR(10) NOT NULL DEFAULT 'b'
+    ~ column note TEXT NULL DEFAULT 'none' -> TEXT NULL
+`
+	if report := diff.Report(); report != expected {
+		t.Errorf("Expected report:\n%s\ngot:\n%s", expected, report)
+	}
+	for _, change := range diff.ChangedTables[0].ChangedColumns {
+		if !change.DefaultChanged || change.TypeChanged || change.NullabilityChanged {
+			t.Errorf("Expected only the default of %s to change, got %+v", change.New.ColumnName, change)
+		}
+	}
+
+	tests := []struct {
+		dialect Dialect
+		up      string
+		down    string
+	}{
+		{
+			dialect: DialectMySQL,
+			up:      "ALTER TABLE tasks MODIFY COLUMN status VARCHAR(10) NOT NULL DEFAULT 'b';\nALTER TABLE tasks MODIFY COLUMN note TEXT;\n",
+			down:    "ALTER TABLE tasks MODIFY COLUMN status VARCHAR(10) NOT NULL DEFAULT 'a';\nALTER TABLE tasks MODIFY COLUMN note TEXT DEFAULT 'none';\n",
+		},
+		{
+			dialect: DialectPostgres,
+			up:      "ALTER TABLE tasks ALTER COLUMN status SET DEFAULT 'b';\nALTER TABLE tasks ALTER COLUMN note DROP DEFAULT;\n",
+			down:    "ALTER TABLE tasks ALTER COLUMN status SET DEFAULT 'a';\nALTER TABLE tasks ALTER COLUMN note SET DEFAULT 'none';\n",
+		},
+	}
+
+	for _, tt := range tests {
+		up, down := GenerateMigration(oldDefs, newDefs, tt.dialect)
+		if up != tt.up {
+			t.Errorf("Expected %s up migration:\n%s\ngot:\n%s", tt.dialect, tt.up, up)
+		}
+		if down != tt.down {
+			t.Errorf("Expected %s down migration:\n%s\ngot:\n%s", tt.dialect, tt.down, down)
+		}
+	}
+
+	up, _ := GenerateMigration(oldDefs, newDefs, DialectSQLite)
+	if !strings.Contains(up, "CREATE TABLE new_tasks") || !strings.Contains(up, "DEFAULT 'b'") {
+		t.Errorf("Expected SQLite to rebuild tasks with the new default, got:\n%s", up)
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// generateDTOs generates the companion structs of a table for API handlers, with their
+// conversion methods:
+//   - XCreate holds the columns of a new row, without those the database sets
+//   - XUpdate holds a partial update: every column is an optional pointer
+//   - XResponse holds the columns returned to clients, without config.ResponseExclude
+//
+// DTOs only carry json and xml tags, as they are not mapped to the database.
+func generateDTOs(def StructDef, config Config) string {
+	var created, updated, response []FieldDef
+	for _, field := range def.Fields {
+		if field.Association != "" {
+			continue
+		}
+		if isInsertedColumn(field) {
+			created = append(created, field)
+		}
+		if isUpdatedColumn(field) {
+			optional := field
+			if !strings.HasPrefix(optional.Type, "*") {
+				optional.Type = "*" + optional.Type
+			}
+			updated = append(updated, optional)
+		}
+		if !isResponseExcluded(def, field, config) {
+			// Response structs are flat
+			flat := field
+			flat.Embedded = ""
+			response = append(response, flat)
+		}
+	}
+
+	var output strings.Builder
+	createName, updateName, responseName := def.Name+"Create", def.Name+"Update", def.Name+"Response"
+
+	// Create: a new row from the client's values
+	output.WriteString(fmt.Sprintf("// %s holds the values of a new %s row, without the columns the database sets\n", createName, def.TableName))
+	output.WriteString(generateDTOStruct(createName, created, config, false))
+	output.WriteString(fmt.Sprintf("\n// To%s returns the %s row holding the values of c\n", def.Name, def.TableName))
+	output.WriteString(fmt.Sprintf("func (c %s) To%s() %s {\n", createName, def.Name, def.Name))
+	output.WriteString(generateDTOLiteral(def.Name, "c", created))
+	output.WriteString("}\n")
+
+	// Update: only the fields set by the client are written to the row
+	if len(updated) > 0 {
+		output.WriteString(fmt.Sprintf("\n// %s holds a partial update of a %s row. Nil fields are left unchanged.\n", updateName, def.TableName))
+		output.WriteString(generateDTOStruct(updateName, updated, config, true))
+		output.WriteString("\n// ApplyTo sets the fields of row that are set in u\n")
+		output.WriteString(fmt.Sprintf("func (u %s) ApplyTo(row *%s) {\n", updateName, def.Name))
+		for _, field := range updated {
+			output.WriteString(fmt.Sprintf("\tif u.%s != nil {\n", field.Name))
+			if field.Nullable {
+				output.WriteString(fmt.Sprintf("\t\trow.%s = u.%s\n", field.Name, field.Name))
+			} else {
+				output.WriteString(fmt.Sprintf("\t\trow.%s = *u.%s\n", field.Name, field.Name))
+			}
+			output.WriteString("\t}\n")
+		}
+		output.WriteString("}\n")
+	}
+
+	// Response: the row as returned to clients
+	output.WriteString(fmt.Sprintf("\n// %s holds the %s columns returned to clients\n", responseName, def.TableName))
+	output.WriteString(generateDTOStruct(responseName, response, config, false))
+	output.WriteString(fmt.Sprintf("\n// ToResponse returns the %s of row\n", responseName))
+	output.WriteString(fmt.Sprintf("func (row %s) ToResponse() %s {\n", def.Name, responseName))
+	output.WriteString(generateDTOLiteral(responseName, "row", response))
+	output.WriteString("}\n")
+
+	return output.String()
+}
+
+// generateDTOStruct generates a DTO struct with aligned json and xml tags. The tags of
+// optional fields have omitempty.
+func generateDTOStruct(name string, fields []FieldDef, config Config, optional bool) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("type %s struct {\n", name))
+
+	maxNameLen, maxTypeLen := calculateAlignment(fields)
+	for _, field := range fields {
+		output.WriteString("\t" + field.Name + strings.Repeat(" ", maxNameLen-len(field.Name)+1) + field.Type)
+		if tags := dtoStructTags(field, config, optional); tags != "" {
+			output.WriteString(strings.Repeat(" ", maxTypeLen-len(field.Type)+1) + "`" + tags + "`")
+		}
+		output.WriteString("\n")
+	}
+
+	output.WriteString("}\n")
+	return output.String()
+}
+
+// dtoStructTags generates the json and xml tags of a DTO field
+func dtoStructTags(field FieldDef, config Config, optional bool) string {
+	name := toSnakeCase(field.ColumnName)
+	omitEmpty := ""
+	if optional {
+		omitEmpty = ",omitempty"
+	}
+
+	var tags []string
+	if config.AddJSONTag {
+		// JavaScript clients read 64-bit integers exactly only from strings
+		if config.Int64AsString && isInt64Field(field) {
+			tags = append(tags, fmt.Sprintf(`json:"%s,string%s"`, name, omitEmpty))
+		} else {
+			tags = append(tags, fmt.Sprintf(`json:"%s%s"`, name, omitEmpty))
+		}
+	}
+	if config.AddXMLTag {
+		tags = append(tags, fmt.Sprintf(`xml:"%s%s"`, name, omitEmpty))
+	}
+	return strings.Join(tags, " ")
+}
+
+// generateDTOLiteral generates the return statement of a conversion method, copying
+// the fields from the receiver named recv into a typeName composite literal
+func generateDTOLiteral(typeName, recv string, fields []FieldDef) string {
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		values[i] = recv + "." + field.Name
+	}
+	return generateReturnLiteral(typeName, fields, values)
+}
+
+// isResponseExcluded reports whether config.ResponseExclude leaves a column out of
+// the Response struct, by column name or table.column
+func isResponseExcluded(def StructDef, field FieldDef, config Config) bool {
+	for _, key := range config.ResponseExclude {
+		table, column, qualified := strings.Cut(key, ".")
+		if !qualified {
+			column = table
+		} else if !strings.EqualFold(table, def.TableName) {
+			continue
+		}
+		if strings.EqualFold(column, field.ColumnName) {
+			return true
+		}
+	}
+	return false
+}
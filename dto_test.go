@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// dtoSQL has columns set by the database, a nullable column and a secret
+const dtoSQL = `
+CREATE TABLE users (
+	id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	email VARCHAR(255) NOT NULL,
+	nickname VARCHAR(64),
+	password_hash CHAR(60) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);`
+
+// TestGenerateDTOs tests the Create, Update and Response structs and their conversion methods
+func TestGenerateDTOs(t *testing.T) {
+	structs, err := ParseSQL(dtoSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := `// UsersCreate holds the values of a new users row, without the columns the database sets
+type UsersCreate struct {
+	Email        string  ` + "`json:\"email\"`" + `
+	Nickname     *string ` + "`json:\"nickname\"`" + `
+	PasswordHash string  ` + "`json:\"password_hash\"`" + `
+}
+
+// ToUsers returns the users row holding the values of c
+func (c UsersCreate) ToUsers() Users {
+	return Users{
+		Email:        c.Email,
+		Nickname:     c.Nickname,
+		PasswordHash: c.PasswordHash,
+	}
+}
+
+// UsersUpdate holds a partial update of a users row. Nil fields are left unchanged.
+type UsersUpdate struct {
+	Email        *string ` + "`json:\"email,omitempty\"`" + `
+	Nickname     *string ` + "`json:\"nickname,omitempty\"`" + `
+	PasswordHash *string ` + "`json:\"password_hash,omitempty\"`" + `
+}
+
+// ApplyTo sets the fields of row that are set in u
+func (u UsersUpdate) ApplyTo(row *Users) {
+	if u.Email != nil {
+		row.Email = *u.Email
+	}
+	if u.Nickname != nil {
+		row.Nickname = u.Nickname
+	}
+	if u.PasswordHash != nil {
+		row.PasswordHash = *u.PasswordHash
+	}
+}
+
+// UsersResponse holds the users columns returned to clients
+type UsersResponse struct {
+	Id        int64     ` + "`json:\"id,string\"`" + `
+	Email     string    ` + "`json:\"email\"`" + `
+	Nickname  *string   ` + "`json:\"nickname\"`" + `
+	CreatedAt time.Time ` + "`json:\"created_at\"`" + `
+}
+
+// ToResponse returns the UsersResponse of row
+func (row Users) ToResponse() UsersResponse {
+	return UsersResponse{
+		Id:        row.Id,
+		Email:     row.Email,
+		Nickname:  row.Nickname,
+		CreatedAt: row.CreatedAt,
+	}
+}
+`
+	config := Config{AddJSONTag: true, AddGormTag: true, Int64AsString: true, AddDTOs: true, ResponseExclude: []string{"users.password_hash", "orders.email"}}
+	if code := generateDTOs(structs[0], config); code != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, code)
+	}
+
+	code := GenerateGoCode(structs, config)
+	typeCheck(t, code, nil)
+}
+
+// TestGenerateDTOs_Files tests DTOs in multi-file output, with unqualified exclusions
+func TestGenerateDTOs_Files(t *testing.T) {
+	structs, err := ParseSQL(dtoSQL + "CREATE TABLE tokens (user_id BIGINT NOT NULL PRIMARY KEY, password_hash CHAR(60));")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	files := GenerateGoFiles(structs, Config{AddDTOs: true, ResponseExclude: []string{"PASSWORD_HASH"}})
+
+	if !strings.Contains(files["users.go"], "type UsersResponse struct {") || strings.Contains(files["users.go"], "type Tokens") {
+		t.Errorf("Expected the users DTOs in users.go, got:\n%s", files["users.go"])
+	}
+	tokens := files["tokens.go"]
+	if !strings.Contains(tokens, "type TokensResponse struct {\n\tUserId int64\n}") {
+		t.Errorf("Expected password_hash left out of TokensResponse, got:\n%s", tokens)
+	}
+	if !strings.Contains(tokens, "type TokensUpdate struct {\n\tPasswordHash *string\n}") {
+		t.Errorf("Expected TokensUpdate without the primary key, got:\n%s", tokens)
+	}
+}
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// entPackageName is the package of generated ent schemas unless configured otherwise
+const entPackageName = "schema"
+
+// entFieldBuilders maps Go types to the ent field builder functions
+var entFieldBuilders = map[string]string{
+	"bool":      "Bool",
+	"int":       "Int",
+	"int8":      "Int8",
+	"int16":     "Int16",
+	"int32":     "Int32",
+	"int64":     "Int64",
+	"uint":      "Uint",
+	"uint8":     "Uint8",
+	"uint16":    "Uint16",
+	"uint32":    "Uint32",
+	"uint64":    "Uint64",
+	"float32":   "Float32",
+	"float64":   "Float",
+	"time.Time": "Time",
+	"[]byte":    "Bytes",
+}
+
+// entSchema is the ent schema of a table: field, edge and index builder expressions
+type entSchema struct {
+	def     StructDef
+	fields  []string
+	edges   []string
+	indexes []string
+	notes   []string // Doc comment lines on what ent cannot represent
+	imports map[string]bool
+}
+
+// GenerateEntSchema generates ent (entgo.io) schema types for the struct definitions
+// in a single file. Columns become fields with their modifiers (MaxLen, Optional,
+// Nillable, Unique, Default, Comment), foreign keys become edges and detected join
+// tables become many-to-many edges instead of schemas. The package defaults to "schema".
+func GenerateEntSchema(defs []StructDef, config Config) string {
+	if len(defs) == 0 {
+		return ""
+	}
+
+	var body strings.Builder
+	imports := make(map[string]bool)
+	for i, schema := range buildEntSchemas(defs) {
+		if i > 0 {
+			body.WriteString("\n")
+		}
+		body.WriteString(generateEntSchemaType(schema))
+		for imp := range schema.imports {
+			imports[imp] = true
+		}
+	}
+
+	return generateGoFile(defs, sortImports(imports), body.String(), entConfig(config))
+}
+
+// GenerateEntFiles generates one ent schema file per table, keyed by file name (e.g. "users.go")
+func GenerateEntFiles(defs []StructDef, config Config) map[string]string {
+	files := make(map[string]string)
+	config = entConfig(config)
+	for _, schema := range buildEntSchemas(defs) {
+		name := strings.ToLower(schema.def.Name) + ".go"
+		files[name] = generateGoFile([]StructDef{schema.def}, sortImports(schema.imports), generateEntSchemaType(schema), config)
+	}
+	return files
+}
+
+// entConfig returns config with the ent schema package as default package name
+func entConfig(config Config) Config {
+	if strings.TrimSpace(config.PackageName) == "" {
+		config.PackageName = entPackageName
+	}
+	return config
+}
+
+// buildEntSchemas builds the ent schemas of defs and the edges between them
+func buildEntSchemas(defs []StructDef) []*entSchema {
+	var schemas []*entSchema
+	byTable := make(map[string]*entSchema)
+	for _, def := range defs {
+		// The table name is kept with an annotation, as ent would derive it from the type name
+		schema := &entSchema{def: def, imports: map[string]bool{
+			"entgo.io/ent": true, "entgo.io/ent/dialect/entsql": true, "entgo.io/ent/schema": true,
+		}}
+		schema.fields, schema.indexes, schema.notes = entFields(def, schema.imports)
+		schemas = append(schemas, schema)
+		byTable[strings.ToLower(def.TableName)] = schema
+	}
+
+	graph := BuildRelationshipGraph(defs)
+
+	// Join tables become the storage of many-to-many edges, when ent can express them
+	joinTables := make(map[string]bool)
+	for _, m2m := range graph.ManyToMany {
+		left, right := byTable[strings.ToLower(m2m.Left.RefTable)], byTable[strings.ToLower(m2m.Right.RefTable)]
+		if !isEntID(left.def, m2m.Left.RefColumn) || !isEntID(right.def, m2m.Right.RefColumn) {
+			continue
+		}
+		leftEdge, rightEdge := toSnakeCase(pluralize(right.def.Name)), toSnakeCase(pluralize(left.def.Name))
+		if left.hasName(leftEdge) || right.hasName(rightEdge) {
+			continue
+		}
+		left.addEdge(fmt.Sprintf("edge.To(%q, %s.Type).StorageKey(edge.Table(%q), edge.Columns(%q, %q))",
+			leftEdge, right.def.Name, m2m.JoinTable, m2m.Left.Column, m2m.Right.Column))
+		right.addEdge(fmt.Sprintf("edge.From(%q, %s.Type).Ref(%q)", rightEdge, left.def.Name, leftEdge))
+		joinTables[strings.ToLower(m2m.JoinTable)] = true
+	}
+
+	// Foreign keys from one table to the same parent need distinct edge names
+	parentCount := make(map[string]int)
+	for _, rel := range graph.Relationships {
+		parentCount[strings.ToLower(rel.Table+"\x00"+rel.RefTable)]++
+	}
+
+	for _, rel := range graph.Relationships {
+		if joinTables[strings.ToLower(rel.Table)] {
+			continue
+		}
+		child, parent := byTable[strings.ToLower(rel.Table)], byTable[strings.ToLower(rel.RefTable)]
+		column := child.def.Fields[fieldIndex(child.def.Fields, rel.Column)]
+		if !isEntID(parent.def, rel.RefColumn) || isEntID(child.def, rel.Column) {
+			continue
+		}
+
+		from := toSnakeCase(belongsToName(column.Name, parent.def.Name))
+		to := toSnakeCase(pluralize(child.def.Name))
+		if parentCount[strings.ToLower(rel.Table+"\x00"+rel.RefTable)] > 1 {
+			to = from + "_" + to
+		}
+		if child.hasName(from) || parent.hasName(to) || (child == parent && from == to) {
+			continue
+		}
+
+		required := ""
+		if !column.Nullable {
+			required = ".Required()"
+		}
+		parent.addEdge(fmt.Sprintf("edge.To(%q, %s.Type)", to, child.def.Name))
+		child.addEdge(fmt.Sprintf("edge.From(%q, %s.Type).Ref(%q).Field(%q).Unique()%s",
+			from, parent.def.Name, to, entFieldName(column.ColumnName), required))
+	}
+
+	var result []*entSchema
+	for _, schema := range schemas {
+		if !joinTables[strings.ToLower(schema.def.TableName)] {
+			result = append(result, schema)
+		}
+	}
+	return result
+}
+
+// entFields returns the field and index builders of a table, and notes on what
+// ent cannot represent. A single-column primary key becomes the id field, which is
+// left out when it is ent's default auto-increment int id.
+func entFields(def StructDef, imports map[string]bool) (fields, indexes, notes []string) {
+	var keys []FieldDef
+	for _, field := range repositoryColumns(def) {
+		if field.PrimaryKey {
+			keys = append(keys, field)
+		}
+	}
+	switch len(keys) {
+	case 0:
+		notes = append(notes, fmt.Sprintf("%s has no primary key; ent adds an id column.", def.TableName))
+	case 1:
+	default:
+		// ent needs a single id column; keep the key as a unique index
+		notes = append(notes, fmt.Sprintf("%s has a composite primary key; ent adds an id column.", def.TableName))
+		indexes = append(indexes, fmt.Sprintf("index.Fields(%s).Unique()", entFieldNames(columnNames(keys))))
+	}
+
+	uniqueColumns := make(map[string]bool)
+	for _, index := range def.Indexes {
+		if index.Unique && len(index.Columns) == 1 {
+			uniqueColumns[strings.ToLower(index.Columns[0])] = true
+			continue
+		}
+		builder := fmt.Sprintf("index.Fields(%s)", entFieldNames(index.Columns))
+		if index.Unique {
+			builder += ".Unique()"
+		}
+		if index.Name != "" {
+			builder += fmt.Sprintf(".StorageKey(%q)", index.Name)
+		}
+		indexes = append(indexes, builder)
+	}
+	if len(indexes) > 0 {
+		imports["entgo.io/ent/schema/index"] = true
+	}
+
+	for _, field := range repositoryColumns(def) {
+		id := len(keys) == 1 && field.PrimaryKey
+		if id && strings.EqualFold(field.ColumnName, "id") && field.Type == "int" && field.AutoIncrement {
+			continue
+		}
+
+		builder, ok := entFieldBuilder(field, id, uniqueColumns[strings.ToLower(field.ColumnName)], imports)
+		if !ok {
+			fields = append(fields, fmt.Sprintf("// %s: no ent field type for %s", field.ColumnName, field.Type))
+			continue
+		}
+		fields = append(fields, builder)
+		imports["entgo.io/ent/schema/field"] = true
+	}
+
+	return fields, indexes, notes
+}
+
+// entFieldBuilder returns the ent field builder of a column, such as
+// field.String("email").MaxLen(255).Unique(). It reports false for Go types
+// ent has no field type for.
+func entFieldBuilder(field FieldDef, id, unique bool, imports map[string]bool) (string, bool) {
+	name := entFieldName(field.ColumnName)
+	if id {
+		name = "id"
+	}
+	baseType := strings.TrimPrefix(field.Type, "*")
+
+	var builder strings.Builder
+	switch {
+	case len(field.EnumValues) > 0:
+		builder.WriteString(fmt.Sprintf("field.Enum(%q).Values(%s)", name, goQuoteList(field.EnumValues)))
+	case baseType == "string":
+		switch field.SQLType {
+		case "TEXT", "TINYTEXT", "MEDIUMTEXT", "LONGTEXT", "CITEXT":
+			builder.WriteString(fmt.Sprintf("field.Text(%q)", name))
+		default:
+			builder.WriteString(fmt.Sprintf("field.String(%q)", name))
+		}
+		if numberLiteralRegex.MatchString(field.Size) && !strings.ContainsAny(field.Size, ".eE") {
+			builder.WriteString(fmt.Sprintf(".MaxLen(%s)", field.Size))
+		}
+	case entFieldBuilders[baseType] != "":
+		builder.WriteString(fmt.Sprintf("field.%s(%q)", entFieldBuilders[baseType], name))
+	case baseType == "uuid.UUID" && field.TypeImport == "github.com/google/uuid":
+		builder.WriteString(fmt.Sprintf("field.UUID(%q, uuid.UUID{})", name))
+		imports[field.TypeImport] = true
+	case field.SQLType == "JSON" || field.SQLType == "JSONB":
+		builder.WriteString(fmt.Sprintf("field.JSON(%q, %s{})", name, baseType))
+		if field.TypeImport != "" {
+			imports[field.TypeImport] = true
+		}
+	default:
+		return "", false
+	}
+
+	if unique && !id {
+		builder.WriteString(".Unique()")
+	}
+	if field.Nullable && !field.PrimaryKey {
+		builder.WriteString(".Optional().Nillable()")
+	}
+	switch {
+	case field.Default == "":
+	case baseType == "time.Time" && isCurrentTimeDefault(field.Default):
+		builder.WriteString(".Default(time.Now)")
+		imports["time"] = true
+	default:
+		if literal, ok := goDefaultLiteral(field); ok {
+			builder.WriteString(fmt.Sprintf(".Default(%s)", literal))
+		}
+	}
+	if (field.SQLType == "DECIMAL" || field.SQLType == "NUMERIC") && field.Size != "" {
+		builder.WriteString(fmt.Sprintf(".SchemaType(map[string]string{dialect.MySQL: %q, dialect.Postgres: %q})",
+			"decimal("+field.Size+")", "numeric("+field.Size+")"))
+		imports["entgo.io/ent/dialect"] = true
+	}
+	if field.Comment != "" {
+		builder.WriteString(fmt.Sprintf(".Comment(%q)", field.Comment))
+	}
+	if field.ColumnName != name {
+		builder.WriteString(fmt.Sprintf(".StorageKey(%q)", field.ColumnName))
+	}
+
+	return builder.String(), true
+}
+
+// generateEntSchemaType generates the schema type of a table with its Annotations,
+// Fields, Edges and Indexes methods
+func generateEntSchemaType(schema *entSchema) string {
+	// Annotations, Fields, Edges and Indexes follow the order of the ent documentation
+	def := schema.def
+	var output strings.Builder
+
+	output.WriteString(fmt.Sprintf("// %s holds the schema definition of the %s table.\n", def.Name, def.TableName))
+	for _, note := range schema.notes {
+		output.WriteString("// " + note + "\n")
+	}
+	output.WriteString(fmt.Sprintf("type %s struct {\n\tent.Schema\n}\n", def.Name))
+
+	writeEntMethod(&output, def.Name, "Annotations", "schema.Annotation", []string{fmt.Sprintf("entsql.Annotation{Table: %q}", def.TableName)})
+
+	writeEntMethod(&output, def.Name, "Fields", "ent.Field", schema.fields)
+	writeEntMethod(&output, def.Name, "Edges", "ent.Edge", schema.edges)
+	writeEntMethod(&output, def.Name, "Indexes", "ent.Index", schema.indexes)
+
+	return output.String()
+}
+
+// writeEntMethod writes a schema method returning a list of builders, unless the list is empty
+func writeEntMethod(output *strings.Builder, typeName, method, elemType string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	output.WriteString(fmt.Sprintf("\n// %s of the %s.\n", method, typeName))
+	output.WriteString(fmt.Sprintf("func (%s) %s() []%s {\n", typeName, method, elemType))
+	output.WriteString(fmt.Sprintf("\treturn []%s{\n", elemType))
+	for _, item := range items {
+		if strings.HasPrefix(item, "//") {
+			output.WriteString("\t\t" + item + "\n")
+		} else {
+			output.WriteString("\t\t" + item + ",\n")
+		}
+	}
+	output.WriteString("\t}\n}\n")
+}
+
+// addEdge adds an edge builder and its import
+func (s *entSchema) addEdge(edge string) {
+	s.edges = append(s.edges, edge)
+	s.imports["entgo.io/ent/schema/edge"] = true
+}
+
+// hasName reports whether a field or edge of the schema is named name
+func (s *entSchema) hasName(name string) bool {
+	if name == "id" {
+		return true
+	}
+	for _, field := range s.def.Fields {
+		if field.Association == "" && entFieldName(field.ColumnName) == name {
+			return true
+		}
+	}
+	for _, edge := range s.edges {
+		if strings.Contains(edge, fmt.Sprintf("(%q,", name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isEntID reports whether column is the single-column primary key of def,
+// which ent represents as the id field
+func isEntID(def StructDef, column string) bool {
+	keys := primaryKeyColumns(def)
+	return len(keys) == 1 && strings.EqualFold(keys[0], column)
+}
+
+// entFieldName returns the ent field name of a column (snake_case)
+func entFieldName(column string) string {
+	return toSnakeCase(column)
+}
+
+// entFieldNames returns the quoted ent field names of columns, joined by commas
+func entFieldNames(columns []string) string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = entFieldName(column)
+	}
+	return goQuoteList(names)
+}
+
+// goQuoteList returns values as comma-separated Go string literals
+func goQuoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return strings.Join(quoted, ", ")
+}
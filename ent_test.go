@@ -0,0 +1,159 @@
+package main
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+)
+
+// entSQL has a foreign key, a many-to-many join table, a composite key and a table without a key
+const entSQL = `
+CREATE TABLE users (
+	id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	email VARCHAR(255) NOT NULL UNIQUE,
+	status ENUM('active','banned') NOT NULL DEFAULT 'active',
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE posts (
+	id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	user_id INT NOT NULL,
+	title VARCHAR(200),
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE TABLE tags (id INT NOT NULL AUTO_INCREMENT PRIMARY KEY, name VARCHAR(50) NOT NULL);
+CREATE TABLE post_tags (
+	post_id INT NOT NULL,
+	tag_id INT NOT NULL,
+	PRIMARY KEY (post_id, tag_id),
+	FOREIGN KEY (post_id) REFERENCES posts(id),
+	FOREIGN KEY (tag_id) REFERENCES tags(id)
+);
+CREATE TABLE versions (doc_id INT NOT NULL, version INT NOT NULL, PRIMARY KEY (doc_id, version));
+CREATE TABLE audit (a INT, b INT);`
+
+// TestGenerateEntSchema tests the ent schema of a table with a foreign key
+func TestGenerateEntSchema(t *testing.T) {
+	structs, err := ParseSQL(entSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := `package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// Users holds the schema definition of the users table.
+type Users struct {
+	ent.Schema
+}
+
+// Annotations of the Users.
+func (Users) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "users"},
+	}
+}
+
+// Fields of the Users.
+func (Users) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("email").MaxLen(255).Unique(),
+		field.Enum("status").Values("active", "banned").Default("active"),
+		field.Time("created_at").Default(time.Now),
+	}
+}
+
+// Edges of the Users.
+func (Users) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("posts", Posts.Type),
+	}
+}
+
+// Posts holds the schema definition of the posts table.
+type Posts struct {
+	ent.Schema
+}
+
+// Annotations of the Posts.
+func (Posts) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "posts"},
+	}
+}
+
+// Fields of the Posts.
+func (Posts) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("user_id"),
+		field.String("title").MaxLen(200).Optional().Nillable(),
+	}
+}
+
+// Edges of the Posts.
+func (Posts) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", Users.Type).Ref("posts").Field("user_id").Unique().Required(),
+	}
+}
+`
+	if code := GenerateEntSchema(structs[:2], Config{}); code != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, code)
+	}
+}
+
+// TestGenerateEntSchema_Relations tests join tables, composite keys and tables without a key
+func TestGenerateEntSchema_Relations(t *testing.T) {
+	structs, err := ParseSQL(entSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	code := GenerateEntSchema(structs, Config{})
+	if _, err := format.Source([]byte(code)); err != nil {
+		t.Fatalf("Expected valid Go, got: %v\n%s", err, code)
+	}
+	expected := []string{
+		`edge.To("tags", Tags.Type).StorageKey(edge.Table("post_tags"), edge.Columns("post_id", "tag_id")),`,
+		`edge.From("posts", Posts.Type).Ref("tags"),`,
+		"// versions has a composite primary key",
+		`index.Fields("doc_id", "version").Unique(),`,
+		"// audit has no primary key; ent adds an id column.",
+	}
+	for _, want := range expected {
+		if !strings.Contains(code, want) {
+			t.Errorf("Expected %q in:\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "type PostTags struct") {
+		t.Errorf("Expected the join table to become an edge, got:\n%s", code)
+	}
+}
+
+// TestGenerateEntFiles tests that each schema gets its own file with its own imports
+func TestGenerateEntFiles(t *testing.T) {
+	structs, err := ParseSQL(entSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	files := GenerateEntFiles(structs, Config{})
+	if len(files) != 5 {
+		t.Fatalf("Expected 5 files, got %v", sortedFileNames(files))
+	}
+	tags := files["tags.go"]
+	if strings.Contains(tags, `"time"`) || !strings.Contains(tags, `"entgo.io/ent/schema/edge"`) {
+		t.Errorf("Unexpected tags.go:\n%s", tags)
+	}
+	if audit := files["audit.go"]; strings.Contains(audit, "edge") {
+		t.Errorf("Expected no edges in audit.go, got:\n%s", audit)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"archive/zip"
+	"io"
+	"sort"
+	"strings"
+)
+
+// enumsFileName is the shared file holding the ENUM type declarations in multi-file output
+const enumsFileName = "enums.go"
+
+// GenerateGoFiles generates one Go file per table, keyed by file name (e.g. "order_items.go").
+// Declarations shared between tables, such as ENUM types, the JSON wrapper type, the
+// base struct and the repository DBTX interface, go to separate enums.go, json.go,
+// base.go and db.go files.
+// Each file carries its own header, package clause and minimal imports.
+func GenerateGoFiles(defs []StructDef, config Config) map[string]string {
+	files := make(map[string]string)
+	if len(defs) == 0 {
+		return files
+	}
+
+	typed, _ := applyBaseStruct(generatedDefs(defs, config), config)
+
+	for i, def := range typed {
+		name := goFileName(def)
+		body := generateStruct(def, config)
+		if config.AddConstructors {
+			if constructor, _ := generateConstructor(def); constructor != "" {
+				body += "\n" + constructor
+			}
+		}
+		if config.AddDTOs {
+			body += "\n" + generateDTOs(def, config)
+		}
+		imports := jsonWrapperImports(typed[i:i+1], config)
+		switch {
+		case config.AddSQLX:
+			body += "\n" + generateSQLX(def, config)
+			imports = append(imports, sqlxImports...)
+		case config.AddRepository:
+			body += "\n" + generateRepository(def, config)
+			imports = append(imports, repositoryImports...)
+		}
+		imports = append(imports, baseImports(typed[i:i+1])...)
+		files[name] = generateGoFile(defs[i:i+1], goImports(declaredDefs(typed[i:i+1], config), imports...), body, config)
+	}
+
+	if config.AddEnumTypes {
+		if enums := generateEnumTypes(typed); enums != "" {
+			files[enumsFileName] = generateGoFile(defs, nil, enums, config)
+		}
+	}
+
+	if usesJSONType(typed, config) && config.JSONWrapper == "" {
+		files[jsonTypeFileName] = generateGoFile(defs, jsonTypeImports, generateJSONType(), config)
+	}
+
+	if base, ok := baseStructDef(typed, config); ok {
+		files[baseFileName] = generateGoFile(defs, goImports([]StructDef{base}), generateBaseStruct(typed, config), config)
+	}
+
+	if config.AddRepository && !config.AddSQLX {
+		files[repositoryFileName] = generateGoFile(defs, dbtxImports, generateDBTX(), config)
+	}
+
+	return files
+}
+
+// goFileName returns the snake_case file name for a table's struct
+func goFileName(def StructDef) string {
+	name := def.TableName
+	if name == "" {
+		name = def.Name
+	}
+	name = toSnakeCase(name)
+
+	// The go tool ignores files starting with _ or ., as for _prisma_migrations
+	if trimmed := strings.TrimLeft(name, "_."); trimmed != name {
+		name = strings.TrimPrefix(trimmed+"_table", "_")
+	}
+	// Don't let a table named "enums", "json", "base" or "db" overwrite a shared file
+	switch name + ".go" {
+	case enumsFileName, jsonTypeFileName, baseFileName, repositoryFileName:
+		name += "_table"
+	}
+	// Nor let the go tool read user_test or events_linux as a test or build-constrained file
+	if i := strings.LastIndex(name, "_"); i > 0 {
+		if suffix := name[i+1:]; suffix == "test" || knownGOOS[suffix] || knownGOARCH[suffix] {
+			name += "_table"
+		}
+	}
+
+	return name + ".go"
+}
+
+// knownGOOS and knownGOARCH are the file name suffixes the go tool reads as build
+// constraints, as listed in go/build
+var (
+	knownGOOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true, "hurd": true,
+		"illumos": true, "ios": true, "js": true, "linux": true, "nacl": true, "netbsd": true,
+		"openbsd": true, "plan9": true, "solaris": true, "wasip1": true, "windows": true, "zos": true,
+	}
+	knownGOARCH = map[string]bool{
+		"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true, "arm64": true,
+		"arm64be": true, "loong64": true, "mips": true, "mipsle": true, "mips64": true,
+		"mips64le": true, "mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true,
+		"ppc64le": true, "riscv": true, "riscv64": true, "s390": true, "s390x": true,
+		"sparc": true, "sparc64": true, "wasm": true,
+	}
+)
+
+// sortedFileNames returns the file names of a multi-file output in a stable order
+func sortedFileNames(files map[string]string) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeZip writes the files as a zip archive
+func writeZip(w io.Writer, files map[string]string) error {
+	archive := zip.NewWriter(w)
+
+	for _, name := range sortedFileNames(files) {
+		f, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, files[name]); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestGenerateGoFiles tests one file per table with per-file imports
+func TestGenerateGoFiles(t *testing.T) {
+	sql := `CREATE TABLE users (
+		id INT NOT NULL,
+		created_at DATETIME NOT NULL
+	);
+	CREATE TABLE order_items (
+		id INT NOT NULL,
+		status ENUM('new', 'shipped') NOT NULL
+	);`
+
+	structs, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	files := GenerateGoFiles(structs, Config{PackageName: "models", AddHeader: true, AddEnumTypes: true})
+
+	if len(files) != 3 {
+		t.Fatalf("Expected 3 files, got %d: %v", len(files), sortedFileNames(files))
+	}
+
+	users, ok := files["users.go"]
+	if !ok {
+		t.Fatal("Expected users.go")
+	}
+	if !strings.Contains(users, `import "time"`) || !strings.Contains(users, "type Users struct") {
+		t.Errorf("Unexpected users.go:\n%s", users)
+	}
+
+	items, ok := files["order_items.go"]
+	if !ok {
+		t.Fatal("Expected order_items.go")
+	}
+	if strings.Contains(items, "import") {
+		t.Errorf("order_items.go should not import anything:\n%s", items)
+	}
+	if !strings.Contains(items, "Status OrderItemsStatus") {
+		t.Errorf("order_items.go should use the enum type:\n%s", items)
+	}
+
+	enums, ok := files["enums.go"]
+	if !ok {
+		t.Fatal("Expected enums.go")
+	}
+	if !strings.HasPrefix(enums, generatedHeader) || !strings.Contains(enums, "package models") {
+		t.Errorf("enums.go should have header and package:\n%s", enums)
+	}
+	if !strings.Contains(enums, "type OrderItemsStatus string") {
+		t.Errorf("enums.go should declare the enum type:\n%s", enums)
+	}
+}
+
+// TestGenerateGoFiles_NoEnums tests that no shared file is produced when nothing is shared
+func TestGenerateGoFiles_NoEnums(t *testing.T) {
+	structs, err := ParseSQL(`CREATE TABLE enums (id INT NOT NULL)`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	files := GenerateGoFiles(structs, Config{AddEnumTypes: true})
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 file, got %v", sortedFileNames(files))
+	}
+	if _, ok := files["enums_table.go"]; !ok {
+		t.Errorf("Table named enums should not take the shared file name, got %v", sortedFileNames(files))
+	}
+}
+
+// TestGoFileName_BuildSuffixes tests that table names don't produce test, build-constrained or ignored files
+func TestGoFileName_BuildSuffixes(t *testing.T) {
+	tests := map[string]string{
+		"user_test":          "user_test_table.go",
+		"events_linux":       "events_linux_table.go",
+		"jobs_amd64":         "jobs_amd64_table.go",
+		"builds_js_wasm":     "builds_js_wasm_table.go",
+		"linux":              "linux.go",
+		"test_results":       "test_results.go",
+		"latest_contests":    "latest_contests.go",
+		"_migrations":        "migrations_table.go",
+		"_prisma_migrations": "prisma_migrations_table.go",
+		"__test":             "test_table.go",
+		"_":                  "table.go",
+	}
+	for table, expected := range tests {
+		if name := goFileName(StructDef{TableName: table}); name != expected {
+			t.Errorf("Table %s: expected %s, got %s", table, expected, name)
+		}
+	}
+}
+
+// TestWriteZip tests the zip archive round trip
+func TestWriteZip(t *testing.T) {
+	files := map[string]string{
+		"users.go":  "package main\n",
+		"orders.go": "package main\n",
+	}
+
+	var buf bytes.Buffer
+	if err := writeZip(&buf, files); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Expected valid zip, got: %v", err)
+	}
+
+	if len(archive.File) != 2 || archive.File[0].Name != "orders.go" {
+		t.Fatalf("Expected sorted entries, got %d entries", len(archive.File))
+	}
+
+	f, _ := archive.File[1].Open()
+	content, _ := io.ReadAll(f)
+	f.Close()
+	if string(content) != files["users.go"] {
+		t.Errorf("Unexpected content for users.go: %q", content)
+	}
+}
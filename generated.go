@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// onUpdateRegex matches the ON UPDATE clause of a MySQL column, and of REFERENCES actions
+var onUpdateRegex = regexp.MustCompile(`(?i)\bON\s+UPDATE\s+`)
+
+// parseGenerated returns the expression of a generated column definition, written
+// GENERATED ALWAYS AS (expr) or AS (expr), and whether it is STORED (or PERSISTENT,
+// in MariaDB) rather than VIRTUAL. It returns "" for other columns.
+func parseGenerated(definition string) (expr string, stored bool) {
+	upper := strings.ToUpper(definition)
+	for i := 0; i < len(definition); i++ {
+		switch definition[i] {
+		case '\'', '"', '`':
+			i = closingQuote(definition, i)
+			continue
+		case '(':
+			// Type arguments, DEFAULT and CHECK expressions
+			i = closingParen(definition, i)
+			continue
+		}
+		if !strings.HasPrefix(upper[i:], "AS") || !isWordBoundary(definition, i, i+len("AS")) {
+			continue
+		}
+
+		// AS IDENTITY is an auto-increment column
+		rest := strings.TrimLeft(definition[i+len("AS"):], " \t\r\n")
+		if !strings.HasPrefix(rest, "(") {
+			continue
+		}
+		end := closingParen(rest, 0)
+		kind := strings.Fields(strings.ToUpper(rest[end+1:]))
+		stored = len(kind) > 0 && (kind[0] == "STORED" || kind[0] == "PERSISTENT")
+		return strings.TrimSpace(rest[1:end]), stored
+	}
+	return "", false
+}
+
+// parseOnUpdate returns the expression of the ON UPDATE clause of a MySQL column, such as
+// CURRENT_TIMESTAMP, or "". ON UPDATE actions of foreign keys are not values and are skipped.
+func parseOnUpdate(definition string) string {
+	for _, loc := range onUpdateRegex.FindAllStringIndex(definition, -1) {
+		if value := readDefaultValue(definition[loc[1]:]); isCurrentTimeDefault(value) {
+			return value
+		}
+	}
+	return ""
+}
+
+// isInsertedColumn reports whether the application writes a column on INSERT. The database
+// sets auto-increment and generated columns, and those defaulting or updated to the current time.
+func isInsertedColumn(field FieldDef) bool {
+	return !field.AutoIncrement && field.Generated == "" && field.OnUpdate == "" && !isCurrentTimeDefault(field.Default)
+}
+
+// isUpdatedColumn reports whether the application writes a column on UPDATE: a column
+// it inserts that is not part of the primary key
+func isUpdatedColumn(field FieldDef) bool {
+	return !field.PrimaryKey && isInsertedColumn(field)
+}
+
+// gormWriteSettings returns the gorm settings of a column the database writes: read-only
+// (->) for generated columns, and autoCreateTime or autoUpdateTime for current-time columns
+func gormWriteSettings(field FieldDef) []string {
+	switch {
+	case field.Generated != "":
+		return []string{"->"}
+	case field.OnUpdate != "":
+		return []string{"autoUpdateTime"}
+	case isCurrentTimeDefault(field.Default):
+		return []string{"autoCreateTime"}
+	}
+	return nil
+}
+
+// generatedFieldComment returns the doc comment of a field the database computes,
+// noting its expression, or ""
+func generatedFieldComment(field FieldDef) string {
+	switch {
+	case field.Generated != "":
+		kind := "virtual"
+		if field.GeneratedStored {
+			kind = "stored"
+		}
+		return fmt.Sprintf("// %s is generated by the database as %s (%s)", field.Name, strings.Join(strings.Fields(field.Generated), " "), kind)
+	case field.OnUpdate != "":
+		return fmt.Sprintf("// %s is set to %s by the database on update", field.Name, field.OnUpdate)
+	}
+	return ""
+}
+
+// generatedColumnDDL returns the GENERATED ALWAYS AS clause of a generated column for
+// dialect. PostgreSQL only supports stored generated columns.
+func generatedColumnDDL(field FieldDef, dialect Dialect) string {
+	kind := "VIRTUAL"
+	if field.GeneratedStored || dialect == DialectPostgres {
+		kind = "STORED"
+	}
+	return fmt.Sprintf("GENERATED ALWAYS AS (%s) %s", field.Generated, kind)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// generatedSQL has a stored and a virtual generated column and columns the database
+// sets to the current time
+const generatedSQL = `
+CREATE TABLE items (
+	id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	price DECIMAL(10,2) NOT NULL,
+	qty INT NOT NULL,
+	total DECIMAL(12,2) GENERATED ALWAYS AS (price * qty) STORED,
+	label VARCHAR(64) AS (CONCAT('item ', id)) VIRTUAL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);`
+
+// TestParseGenerated tests generated column expressions and their kind
+func TestParseGenerated(t *testing.T) {
+	tests := []struct {
+		definition string
+		expr       string
+		stored     bool
+	}{
+		{"total DECIMAL(12,2) GENERATED ALWAYS AS (price * qty) STORED", "price * qty", true},
+		{"label VARCHAR(64) AS (CONCAT('a (', id)) VIRTUAL", "CONCAT('a (', id)", false},
+		{"label TEXT AS (upper(name))", "upper(name)", false},
+		{"total INT AS ((a + b)) PERSISTENT", "(a + b)", true},
+		{"id INT GENERATED ALWAYS AS IDENTITY", "", false},
+		{"note TEXT DEFAULT 'AS (x)'", "", false},
+		{"alias TEXT COMMENT 'known as (nick)'", "", false},
+	}
+
+	for _, tt := range tests {
+		expr, stored := parseGenerated(tt.definition)
+		if expr != tt.expr || stored != tt.stored {
+			t.Errorf("parseGenerated(%q): expected %q, %v, got %q, %v", tt.definition, tt.expr, tt.stored, expr, stored)
+		}
+	}
+}
+
+// TestParseOnUpdate tests ON UPDATE values and foreign key actions
+func TestParseOnUpdate(t *testing.T) {
+	tests := map[string]string{
+		"updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP":       "CURRENT_TIMESTAMP",
+		"updated_at DATETIME(3) ON UPDATE CURRENT_TIMESTAMP(3)":                            "CURRENT_TIMESTAMP(3)",
+		"user_id INT REFERENCES users (id) ON UPDATE CASCADE":                              "",
+		"user_id INT REFERENCES users (id) ON DELETE SET NULL ON UPDATE NO ACTION":         "",
+		"updated_at TIMESTAMP NOT NULL ON UPDATE now() REFERENCES t (x) ON UPDATE CASCADE": "now()",
+	}
+
+	for definition, expected := range tests {
+		if got := parseOnUpdate(definition); got != expected {
+			t.Errorf("parseOnUpdate(%q): expected %q, got %q", definition, expected, got)
+		}
+	}
+}
+
+// TestParseSQL_Generated tests the parsed properties of generated and current-time columns
+func TestParseSQL_Generated(t *testing.T) {
+	structs, err := ParseSQL(generatedSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	fields := structs[0].Fields
+	if total := fields[3]; total.Generated != "price * qty" || !total.GeneratedStored || !total.Nullable || total.SQLType != "DECIMAL" {
+		t.Errorf("Expected a stored generated DECIMAL column, got %+v", total)
+	}
+	if label := fields[4]; label.Generated != "CONCAT('item ', id)" || label.GeneratedStored || label.Size != "64" {
+		t.Errorf("Expected a virtual generated VARCHAR column, got %+v", label)
+	}
+	if updated := fields[6]; updated.OnUpdate != "CURRENT_TIMESTAMP" || updated.Default != "CURRENT_TIMESTAMP" || updated.Nullable {
+		t.Errorf("Expected an ON UPDATE column, got %+v", updated)
+	}
+
+	var inserted []string
+	for _, field := range fields {
+		if isInsertedColumn(field) {
+			inserted = append(inserted, field.ColumnName)
+		}
+	}
+	if strings.Join(inserted, ",") != "price,qty" {
+		t.Errorf("Expected only price and qty to be inserted, got %v", inserted)
+	}
+}
+
+// TestGenerateGoCode_Generated tests the gorm write settings and doc comments of generated columns
+func TestGenerateGoCode_Generated(t *testing.T) {
+	structs, err := ParseSQL(generatedSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	code := GenerateGoCode(structs, Config{AddGormTag: true})
+
+	for _, expected := range []string{
+		"\t// Total is generated by the database as price * qty (stored)\n\tTotal *float64 `gorm:\"column:total;->\"`",
+		"\t// Label is generated by the database as CONCAT('item ', id) (virtual)\n\tLabel     *string   `gorm:\"column:label;->\"`",
+		"\tCreatedAt time.Time `gorm:\"column:created_at;autoCreateTime\"`",
+		"\t// UpdatedAt is set to CURRENT_TIMESTAMP by the database on update\n\tUpdatedAt time.Time `gorm:\"column:updated_at;autoUpdateTime\"`",
+		"\tQty   int     `gorm:\"column:qty\"`",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, code)
+		}
+	}
+	typeCheck(t, code, nil)
+}
+
+// TestGenerateRepository_Generated tests that generated code doesn't write columns the database sets
+func TestGenerateRepository_Generated(t *testing.T) {
+	structs, err := ParseSQL(generatedSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	code := generateRepository(structs[0], Config{})
+	for _, expected := range []string{
+		`"INSERT INTO items (price, qty) VALUES (?, ?)", row.Price, row.Qty)`,
+		`"UPDATE items SET price = ?, qty = ? WHERE id = ?", row.Price, row.Qty, row.Id)`,
+		`"SELECT id, price, qty, total, label, created_at, updated_at FROM items WHERE id = ?"`,
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected %q in repository, got:\n%s", expected, code)
+		}
+	}
+
+	code = generateSQLX(structs[0], Config{})
+	for _, expected := range []string{
+		`"INSERT INTO items (price, qty) VALUES (:price, :qty)"`,
+		`"UPDATE items SET price = :price, qty = :qty WHERE id = :id"`,
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected %q in sqlx code, got:\n%s", expected, code)
+		}
+	}
+}
+
+// TestGenerateDDL_Generated tests generated columns and ON UPDATE in each dialect
+func TestGenerateDDL_Generated(t *testing.T) {
+	structs, err := ParseSQL(generatedSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		dialect  Dialect
+		expected []string
+	}{
+		{DialectMySQL, []string{
+			"total DECIMAL(12,2) GENERATED ALWAYS AS (price * qty) STORED,",
+			"label VARCHAR(64) GENERATED ALWAYS AS (CONCAT('item ', id)) VIRTUAL,",
+			"updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP",
+		}},
+		{DialectPostgres, []string{
+			`label VARCHAR(64) GENERATED ALWAYS AS (CONCAT('item ', id)) STORED,`,
+			"updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,\n",
+		}},
+	}
+
+	for _, tt := range tests {
+		ddl := GenerateDDL(structs, tt.dialect)
+		for _, expected := range tt.expected {
+			if !strings.Contains(ddl, expected) {
+				t.Errorf("%s: expected %q in DDL, got:\n%s", tt.dialect, expected, ddl)
+			}
+		}
+	}
+
+	// The MySQL DDL parses back to the same columns
+	parsed, err := ParseSQL(GenerateDDL(structs, DialectMySQL))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for i, field := range structs[0].Fields {
+		got := parsed[0].Fields[i]
+		if got.Generated != field.Generated || got.GeneratedStored != field.GeneratedStored || got.OnUpdate != field.OnUpdate {
+			t.Errorf("Expected %+v after the round trip, got %+v", field, got)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// File is a generated file
+type File struct {
+	Name    string // File name relative to the output directory (e.g. "users.go")
+	Content string
+}
+
+// Generator produces files from parsed table definitions. Generators are selected
+// by name with --target; see RegisterGenerator.
+type Generator interface {
+	// Name is the name of the generator, such as "go"
+	Name() string
+	// Extension is the file extension of single-file output, such as ".go"
+	Extension() string
+	// Generate generates one file per table, or a single file with config.SingleFile
+	Generate(defs []StructDef, config Config) ([]File, error)
+}
+
+// WarningGenerator is a Generator reporting what it leaves out of the generated code
+type WarningGenerator interface {
+	Generator
+	// Warnings returns the warnings of generating defs with config
+	Warnings(defs []StructDef, config Config) []string
+}
+
+// Registered generators by name, and their names in registration order
+var (
+	generators     = make(map[string]Generator)
+	generatorNames []string
+)
+
+func init() {
+	// The Go structs are the default target
+	RegisterGenerator(funcGenerator{"go", "models.go", GenerateGoCode, GenerateGoFiles, GoCodeWarnings})
+	RegisterGenerator(funcGenerator{"ent", "schema.go", GenerateEntSchema, GenerateEntFiles, nil})
+	RegisterGenerator(funcGenerator{"proto", "models.proto", GenerateProto, GenerateProtoFiles, nil})
+	RegisterGenerator(funcGenerator{"typescript", "models.ts", GenerateTypeScript, GenerateTypeScriptFiles, nil})
+	RegisterGenerator(funcGenerator{"jsonschema", "schema.json", GenerateJSONSchema, GenerateJSONSchemaFiles, nil})
+	RegisterGenerator(funcGenerator{"openapi", "openapi.json", GenerateOpenAPI, GenerateOpenAPIFiles, nil})
+	RegisterGenerator(funcGenerator{"graphql", "schema.graphql", GenerateGraphQL, GenerateGraphQLFiles, nil})
+}
+
+// RegisterGenerator makes a generator available by its name.
+// It panics if a generator with the same name is already registered.
+func RegisterGenerator(gen Generator) {
+	name := strings.ToLower(gen.Name())
+	if _, ok := generators[name]; ok {
+		panic(fmt.Sprintf("generator %q registered twice", name))
+	}
+	generators[name] = gen
+	generatorNames = append(generatorNames, name)
+}
+
+// LookupGenerator returns the registered generator with the given name
+func LookupGenerator(name string) (Generator, bool) {
+	gen, ok := generators[strings.ToLower(name)]
+	return gen, ok
+}
+
+// GeneratorNames returns the names of the registered generators in registration order
+func GeneratorNames() []string {
+	return append([]string(nil), generatorNames...)
+}
+
+// funcGenerator is a generator made of a single-file and a per-table generate function
+type funcGenerator struct {
+	name          string
+	fileName      string // Name of the single file
+	generate      func([]StructDef, Config) string
+	generateFiles func([]StructDef, Config) map[string]string
+	warnings      func([]StructDef, Config) []string // Optional
+}
+
+func (g funcGenerator) Name() string {
+	return g.name
+}
+
+func (g funcGenerator) Extension() string {
+	return filepath.Ext(g.fileName)
+}
+
+func (g funcGenerator) Warnings(defs []StructDef, config Config) []string {
+	if g.warnings == nil {
+		return nil
+	}
+	return g.warnings(defs, config)
+}
+
+func (g funcGenerator) Generate(defs []StructDef, config Config) ([]File, error) {
+	if config.SingleFile {
+		return []File{{Name: g.fileName, Content: g.generate(defs, config)}}, nil
+	}
+
+	files := g.generateFiles(defs, config)
+	result := make([]File, 0, len(files))
+	for _, name := range sortedFileNames(files) {
+		result = append(result, File{Name: name, Content: files[name]})
+	}
+	return result, nil
+}
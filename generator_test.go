@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLookupGenerator tests the built-in generators of the registry
+func TestLookupGenerator(t *testing.T) {
+	names := GeneratorNames()
+	if len(names) == 0 || names[0] != "go" {
+		t.Fatalf("Expected go as the first generator, got %v", names)
+	}
+
+	tests := map[string]string{"go": ".go", "ENT": ".go", "proto": ".proto", "typescript": ".ts", "graphql": ".graphql"}
+	for name, extension := range tests {
+		gen, ok := LookupGenerator(name)
+		if !ok {
+			t.Errorf("Expected a %s generator", name)
+			continue
+		}
+		if gen.Extension() != extension {
+			t.Errorf("Expected extension %s for %s, got %s", extension, name, gen.Extension())
+		}
+	}
+
+	if _, ok := LookupGenerator("cobol"); ok {
+		t.Error("Expected no cobol generator")
+	}
+}
+
+// TestRegisterGenerator_Duplicate tests that a generator name can't be registered twice
+func TestRegisterGenerator_Duplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic")
+		}
+	}()
+	RegisterGenerator(funcGenerator{name: "Go"})
+}
+
+// TestFuncGenerator tests single-file and per-table output of a built-in generator
+func TestFuncGenerator(t *testing.T) {
+	structs, err := ParseSQL("CREATE TABLE users (id INT NOT NULL); CREATE TABLE posts (id INT NOT NULL);")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	gen, _ := LookupGenerator("go")
+
+	files, err := gen.Generate(structs, Config{PackageName: "models"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(files) != 2 || files[0].Name != "posts.go" || files[1].Name != "users.go" {
+		t.Errorf("Expected posts.go and users.go, got %v", files)
+	}
+
+	files, err = gen.Generate(structs, Config{PackageName: "models", SingleFile: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "models.go" || !strings.Contains(files[0].Content, "type Posts struct") {
+		t.Errorf("Expected a single models.go, got %v", files)
+	}
+}
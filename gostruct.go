@@ -0,0 +1,518 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// goColumnType describes the column of a Go field type
+type goColumnType struct {
+	SQLType  string
+	Size     string
+	Unsigned bool
+	Nullable bool // The Go type can hold NULL by itself (sql.NullString, []byte, ...)
+}
+
+// goColumnTypes maps Go types to column types; the inverse of mapSQLTypeToGo
+var goColumnTypes = map[string]goColumnType{
+	"bool":            {SQLType: "BOOLEAN"},
+	"int8":            {SQLType: "TINYINT"},
+	"uint8":           {SQLType: "TINYINT", Unsigned: true},
+	"byte":            {SQLType: "TINYINT", Unsigned: true},
+	"int16":           {SQLType: "SMALLINT"},
+	"uint16":          {SQLType: "SMALLINT", Unsigned: true},
+	"int":             {SQLType: "INT"},
+	"int32":           {SQLType: "INT"},
+	"rune":            {SQLType: "INT"},
+	"uint32":          {SQLType: "INT", Unsigned: true},
+	"int64":           {SQLType: "BIGINT"},
+	"uint":            {SQLType: "BIGINT", Unsigned: true},
+	"uint64":          {SQLType: "BIGINT", Unsigned: true},
+	"float32":         {SQLType: "FLOAT"},
+	"float64":         {SQLType: "DOUBLE"},
+	"string":          {SQLType: "VARCHAR", Size: "255"},
+	"time.Time":       {SQLType: "DATETIME"},
+	"[]byte":          {SQLType: "BLOB", Nullable: true},
+	"json.RawMessage": {SQLType: "JSON", Nullable: true},
+	"datatypes.JSON":  {SQLType: "JSON", Nullable: true},
+	"uuid.UUID":       {SQLType: "UUID"},
+	"sql.NullBool":    {SQLType: "BOOLEAN", Nullable: true},
+	"sql.NullByte":    {SQLType: "TINYINT", Unsigned: true, Nullable: true},
+	"sql.NullInt16":   {SQLType: "SMALLINT", Nullable: true},
+	"sql.NullInt32":   {SQLType: "INT", Nullable: true},
+	"sql.NullInt64":   {SQLType: "BIGINT", Nullable: true},
+	"sql.NullFloat64": {SQLType: "DOUBLE", Nullable: true},
+	"sql.NullString":  {SQLType: "VARCHAR", Size: "255", Nullable: true},
+	"sql.NullTime":    {SQLType: "DATETIME", Nullable: true},
+	"gorm.DeletedAt":  {SQLType: "DATETIME", Nullable: true},
+}
+
+// goGenericColumnTypes maps generic Go types, without their type arguments, to column types
+var goGenericColumnTypes = map[string]goColumnType{
+	"JSON":               {SQLType: "JSON"},
+	"datatypes.JSONType": {SQLType: "JSON"},
+}
+
+// goSource holds the declarations of the parsed Go files
+type goSource struct {
+	structs    map[string]*ast.StructType
+	order      []string            // Struct names in declaration order
+	named      map[string]ast.Expr // Other named types and their underlying type
+	enumValues map[string][]string // String constants by named type
+	tableNames map[string]string   // Results of TableName() methods by type
+	manualKeys map[string]bool     // Struct.column of fields tagged autoIncrement:false
+	warnings   []string
+}
+
+// goAssociation is a field referencing another struct, such as User *User
+type goAssociation struct {
+	Target     string // Referenced struct
+	ForeignKey string // gorm foreignKey setting (field name)
+	References string // gorm references setting (field name)
+}
+
+// ParseGoStructs parses Go source files and converts their struct types to table
+// definitions, the inverse of GenerateGoCode. Column names come from gorm column,
+// db and json tags, falling back to the snake_case field name; table names come
+// from TableName() methods, falling back to the snake_case struct name.
+// Pointer and sql.Null* fields are nullable, an ID field is the default primary key,
+// gorm index, uniqueIndex and unique settings become indexes, and belongs-to
+// fields (User *User next to UserID) become foreign keys.
+// A source without a package clause is accepted.
+// Fields with unsupported types are skipped and reported as warnings.
+func ParseGoStructs(sources ...string) ([]StructDef, []string, error) {
+	src := &goSource{
+		structs:    make(map[string]*ast.StructType),
+		named:      make(map[string]ast.Expr),
+		enumValues: make(map[string][]string),
+		tableNames: make(map[string]string),
+		manualKeys: make(map[string]bool),
+	}
+
+	fset := token.NewFileSet()
+	for _, source := range sources {
+		file, err := parser.ParseFile(fset, "", source, 0)
+		if err != nil {
+			// Accept bare declarations without a package clause
+			if f, retryErr := parser.ParseFile(fset, "", "package main\n"+source, 0); retryErr == nil {
+				file, err = f, nil
+			}
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse Go source: %w", err)
+		}
+		src.collect(file)
+	}
+
+	if len(src.order) == 0 {
+		return nil, src.warnings, fmt.Errorf("no struct types found in Go source")
+	}
+
+	var defs []StructDef
+	associations := make(map[string]map[string]goAssociation)
+	for _, name := range src.order {
+		def := StructDef{Name: name, TableName: src.tableName(name)}
+		associations[name] = make(map[string]goAssociation)
+		src.addFields(&def, src.structs[name], "", associations[name], map[string]bool{name: true})
+
+		if len(def.Fields) == 0 {
+			src.warnings = append(src.warnings, fmt.Sprintf("skipping struct %s: no columns", name))
+			continue
+		}
+		src.setDefaultPrimaryKey(&def)
+		defs = append(defs, def)
+	}
+
+	for i := range defs {
+		src.addForeignKeys(&defs[i], defs, associations[defs[i].Name])
+	}
+
+	return defs, src.warnings, nil
+}
+
+// collect records the type declarations, constants and TableName methods of a file
+func (src *goSource) collect(file *ast.File) {
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if spec.TypeParams != nil {
+						continue
+					}
+					if st, ok := spec.Type.(*ast.StructType); ok {
+						src.structs[spec.Name.Name] = st
+						src.order = append(src.order, spec.Name.Name)
+					} else {
+						src.named[spec.Name.Name] = spec.Type
+					}
+				case *ast.ValueSpec:
+					typeName, ok := spec.Type.(*ast.Ident)
+					if d.Tok != token.CONST || !ok {
+						continue
+					}
+					for _, value := range spec.Values {
+						if lit, ok := value.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+							if s, err := strconv.Unquote(lit.Value); err == nil {
+								src.enumValues[typeName.Name] = append(src.enumValues[typeName.Name], s)
+							}
+						}
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if name, table, ok := tableNameMethod(d); ok {
+				src.tableNames[name] = table
+			}
+		}
+	}
+}
+
+// tableNameMethod recognizes func (T) TableName() string { return "table" }
+func tableNameMethod(fn *ast.FuncDecl) (string, string, bool) {
+	if fn.Name.Name != "TableName" || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Body == nil || len(fn.Body.List) != 1 {
+		return "", "", false
+	}
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", "", false
+	}
+	lit, ok := ret.Results[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", "", false
+	}
+	table, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", "", false
+	}
+
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	ident, ok := recv.(*ast.Ident)
+	if !ok {
+		return "", "", false
+	}
+	return ident.Name, table, true
+}
+
+// tableName returns the table name of a struct
+func (src *goSource) tableName(structName string) string {
+	if table, ok := src.tableNames[structName]; ok {
+		return table
+	}
+	return toSnakeCase(structName)
+}
+
+// addFields adds the columns of a struct type to def. Embedded structs declared in
+// the source, gorm.Model and fields tagged gorm:"embedded" are inlined.
+func (src *goSource) addFields(def *StructDef, st *ast.StructType, prefix string, associations map[string]goAssociation, visiting map[string]bool) {
+	for _, f := range st.Fields.List {
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			if s, err := strconv.Unquote(f.Tag.Value); err == nil {
+				tag = reflect.StructTag(s)
+			}
+		}
+		gorm := parseGormTag(tag.Get("gorm"))
+		if _, skip := gorm["-"]; skip || tag.Get("db") == "-" {
+			continue
+		}
+
+		typeName := strings.TrimPrefix(types.ExprString(f.Type), "*")
+
+		// Embedded fields
+		if len(f.Names) == 0 {
+			src.addEmbedded(def, typeName, prefix+gorm["EMBEDDEDPREFIX"], associations, visiting)
+			continue
+		}
+
+		for _, name := range f.Names {
+			if !name.IsExported() {
+				continue
+			}
+			if _, embedded := gorm["EMBEDDED"]; embedded {
+				src.addEmbedded(def, typeName, prefix+gorm["EMBEDDEDPREFIX"], associations, visiting)
+				continue
+			}
+			src.addField(def, name.Name, f.Type, tag, gorm, prefix, associations)
+		}
+	}
+}
+
+// addEmbedded inlines the columns of an embedded struct
+func (src *goSource) addEmbedded(def *StructDef, typeName, prefix string, associations map[string]goAssociation, visiting map[string]bool) {
+	if typeName == "gorm.Model" {
+		def.Fields = append(def.Fields,
+			FieldDef{Name: "ID", Type: "uint", ColumnName: prefix + "id", SQLType: "BIGINT", Unsigned: true, PrimaryKey: true, AutoIncrement: true},
+			FieldDef{Name: "CreatedAt", Type: "time.Time", ColumnName: prefix + "created_at", SQLType: "DATETIME"},
+			FieldDef{Name: "UpdatedAt", Type: "time.Time", ColumnName: prefix + "updated_at", SQLType: "DATETIME"},
+			FieldDef{Name: "DeletedAt", Type: "gorm.DeletedAt", ColumnName: prefix + "deleted_at", SQLType: "DATETIME", Nullable: true},
+		)
+		return
+	}
+
+	st, ok := src.structs[typeName]
+	if !ok || visiting[typeName] {
+		src.warnings = append(src.warnings, fmt.Sprintf("%s: skipping embedded field %s (not a struct declared in the source)", def.Name, typeName))
+		return
+	}
+
+	visiting[typeName] = true
+	src.addFields(def, st, prefix, associations, visiting)
+	delete(visiting, typeName)
+}
+
+// addField adds the column of a named struct field to def
+func (src *goSource) addField(def *StructDef, name string, typeExpr ast.Expr, tag reflect.StructTag, gorm map[string]string, prefix string, associations map[string]goAssociation) {
+	goType := types.ExprString(typeExpr)
+	baseType := strings.TrimPrefix(goType, "*")
+
+	// Fields referencing other structs are associations, not columns
+	elemType := strings.TrimPrefix(strings.TrimPrefix(baseType, "[]"), "*")
+	if _, ok := src.structs[elemType]; ok {
+		if !strings.HasPrefix(baseType, "[]") {
+			associations[name] = goAssociation{Target: elemType, ForeignKey: gorm["FOREIGNKEY"], References: gorm["REFERENCES"]}
+		}
+		return
+	}
+	if _, ok := gorm["MANY2MANY"]; ok {
+		return
+	}
+
+	field := FieldDef{
+		Name:       name,
+		Type:       goType,
+		ColumnName: prefix + goColumnName(name, tag, gorm),
+		Nullable:   strings.HasPrefix(goType, "*"),
+	}
+
+	column, ok := src.columnType(baseType, &field)
+	if !ok && gorm["TYPE"] == "" {
+		src.warnings = append(src.warnings, fmt.Sprintf("%s.%s: skipping field of unsupported type %s", def.Name, name, goType))
+		return
+	}
+	field.SQLType, field.Size, field.Unsigned = column.SQLType, column.Size, column.Unsigned
+	field.Nullable = field.Nullable || column.Nullable
+
+	applyGormSettings(&field, gorm)
+	if gorm["AUTOINCREMENT"] == "false" {
+		src.manualKeys[def.Name+"."+field.ColumnName] = true
+	}
+	def.Fields = append(def.Fields, field)
+	addGormIndexes(def, field.ColumnName, gorm)
+}
+
+// addGormIndexes adds the indexes declared by the index, uniqueIndex and unique
+// settings of a gorm tag. Fields naming the same index form a composite index.
+func addGormIndexes(def *StructDef, column string, gorm map[string]string) {
+	for _, setting := range []struct {
+		key    string
+		unique bool
+	}{{"INDEX", false}, {"UNIQUEINDEX", true}, {"UNIQUE", true}} {
+		value, ok := gorm[setting.key]
+		if !ok {
+			continue
+		}
+		// Options such as index:idx_name,sort:desc follow the name
+		name, _, _ := strings.Cut(value, ",")
+		name = strings.TrimSpace(name)
+
+		if i := indexByName(def.Indexes, name); name != "" && i != -1 {
+			def.Indexes[i].Columns = append(def.Indexes[i].Columns, column)
+			continue
+		}
+		def.Indexes = append(def.Indexes, Index{Name: name, Columns: []string{column}, Unique: setting.unique})
+	}
+}
+
+// columnType looks up the column type of a Go type, resolving named types declared
+// in the source. String types with constants get them as ENUM values.
+func (src *goSource) columnType(goType string, field *FieldDef) (goColumnType, bool) {
+	for depth := 0; depth < 10; depth++ {
+		if column, ok := goColumnTypes[goType]; ok {
+			return column, true
+		}
+		if name, _, ok := strings.Cut(goType, "["); ok && name != "" {
+			column, ok := goGenericColumnTypes[name]
+			return column, ok
+		}
+
+		underlying, ok := src.named[goType]
+		if !ok {
+			return goColumnType{}, false
+		}
+		if values := src.enumValues[goType]; len(values) > 0 && field.EnumValues == nil {
+			field.EnumValues = values
+		}
+		goType = types.ExprString(underlying)
+	}
+	return goColumnType{}, false
+}
+
+// goColumnName returns the column name of a field from its gorm, db or json tag
+func goColumnName(name string, tag reflect.StructTag, gorm map[string]string) string {
+	if column := gorm["COLUMN"]; column != "" {
+		return column
+	}
+	for _, key := range []string{"db", "json"} {
+		if column, _, _ := strings.Cut(tag.Get(key), ","); column != "" && column != "-" {
+			return column
+		}
+	}
+	return toSnakeCase(name)
+}
+
+// parseGormTag splits a gorm struct tag into its settings, keyed by upper-case name
+// like gorm does (e.g. "column:id;primaryKey" -> COLUMN=id, PRIMARYKEY="")
+func parseGormTag(tag string) map[string]string {
+	settings := make(map[string]string)
+	for _, part := range strings.Split(tag, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(part, ":")
+		settings[strings.ToUpper(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return settings
+}
+
+// applyGormSettings applies the column settings of a gorm tag to field
+func applyGormSettings(field *FieldDef, gorm map[string]string) {
+	if typeDef := gorm["TYPE"]; typeDef != "" {
+		dataType, typeArgs := extractDataType(typeDef)
+		if dataType == "" {
+			dataType, typeArgs = strings.ToUpper(typeDef), ""
+		}
+		setColumnType(field, dataType, typeArgs)
+		field.Unsigned = strings.Contains(strings.ToUpper(typeDef), "UNSIGNED")
+	}
+	if size := gorm["SIZE"]; size != "" && field.SQLType == "VARCHAR" {
+		field.Size = size
+	}
+	if _, ok := gorm["NOT NULL"]; ok {
+		field.Nullable = false
+	}
+	if _, ok := gorm["PRIMARYKEY"]; ok {
+		field.PrimaryKey = true
+	}
+	if _, ok := gorm["PRIMARY_KEY"]; ok {
+		field.PrimaryKey = true
+	}
+	if value, ok := gorm["AUTOINCREMENT"]; ok {
+		field.AutoIncrement = value != "false"
+	}
+	if value := strings.TrimSpace(gorm["DEFAULT"]); value != "" {
+		// Like gorm's migrator, quote plain string values such as default:pending
+		if strings.TrimPrefix(field.Type, "*") == "string" && !strings.HasPrefix(value, "'") && !strings.Contains(value, "(") {
+			value = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+		}
+		field.Default = parseDefault("DEFAULT " + value)
+	}
+}
+
+// setDefaultPrimaryKey makes the ID field the primary key of a struct without one,
+// following the gorm convention. A single integer primary key auto-increments
+// unless disabled with autoIncrement:false.
+func (src *goSource) setDefaultPrimaryKey(def *StructDef) {
+	var keys []int
+	for i, field := range def.Fields {
+		if field.PrimaryKey {
+			keys = append(keys, i)
+		}
+	}
+	if len(keys) == 0 {
+		if i := fieldIndex(def.Fields, "id"); i != -1 {
+			def.Fields[i].PrimaryKey = true
+			keys = append(keys, i)
+		}
+	}
+
+	if len(keys) == 1 {
+		field := &def.Fields[keys[0]]
+		field.Nullable = false
+		if isIntegerSQLType(field.SQLType) && !src.manualKeys[def.Name+"."+field.ColumnName] {
+			field.AutoIncrement = true
+		}
+	}
+}
+
+// addForeignKeys turns the belongs-to associations of def into foreign keys
+func (src *goSource) addForeignKeys(def *StructDef, defs []StructDef, associations map[string]goAssociation) {
+	// Visit fields in order for a stable result
+	for _, field := range src.structs[def.Name].Fields.List {
+		for _, name := range field.Names {
+			association, ok := associations[name.Name]
+			if !ok {
+				continue
+			}
+			target := findStruct(defs, association.Target)
+			if target == nil {
+				continue
+			}
+
+			foreignKey := association.ForeignKey
+			if foreignKey == "" {
+				foreignKey = name.Name + "ID"
+			}
+			column := goFieldColumn(def, foreignKey)
+			if column == "" && association.ForeignKey == "" {
+				column = goFieldColumn(def, name.Name+"Id")
+			}
+			if column == "" {
+				// The key lives on the other struct (has-one)
+				continue
+			}
+
+			fk := ForeignKey{Columns: []string{column}, RefTable: target.TableName}
+			if association.References != "" {
+				if refColumn := goFieldColumn(target, association.References); refColumn != "" {
+					fk.RefColumns = []string{refColumn}
+				}
+			} else if pk := primaryKeyColumns(*target); len(pk) == 1 {
+				fk.RefColumns = pk
+			}
+			def.ForeignKeys = append(def.ForeignKeys, fk)
+		}
+	}
+}
+
+// findStruct returns the definition of a struct by Go name, or nil
+func findStruct(defs []StructDef, name string) *StructDef {
+	for i := range defs {
+		if defs[i].Name == name {
+			return &defs[i]
+		}
+	}
+	return nil
+}
+
+// goFieldColumn returns the column of the field with the given Go name, or ""
+func goFieldColumn(def *StructDef, name string) string {
+	for _, field := range def.Fields {
+		if field.Name == name {
+			return field.ColumnName
+		}
+	}
+	return ""
+}
+
+// primaryKeyColumns returns the primary key columns of a table
+func primaryKeyColumns(def StructDef) []string {
+	var columns []string
+	for _, field := range def.Fields {
+		if field.PrimaryKey {
+			columns = append(columns, field.ColumnName)
+		}
+	}
+	return columns
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseGoStructs tests column names, types, nullability and keys read from Go structs
+func TestParseGoStructs(t *testing.T) {
+	src := `package models
+
+import "time"
+
+type Status string
+
+const (
+	StatusActive   Status = "active"
+	StatusDisabled Status = "disabled"
+)
+
+type User struct {
+	ID        int64     ` + "`json:\"id\"`" + `
+	Email     string    ` + "`db:\"email_address\" gorm:\"size:100;not null\"`" + `
+	Nickname  *string   ` + "`json:\"nick,omitempty\"`" + `
+	Balance   float64   ` + "`gorm:\"type:decimal(10,2)\"`" + `
+	Status    Status
+	Avatar    []byte
+	CreatedAt time.Time
+	Orders    []Order
+	secret    string
+	Ignored   string ` + "`gorm:\"-\"`" + `
+}
+
+type Order struct {
+	ID     uint   ` + "`gorm:\"primaryKey\"`" + `
+	UserID int64
+	User   *User
+}
+
+func (Order) TableName() string { return "customer_orders" }
+`
+
+	structs, warnings, err := ParseGoStructs(src)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got: %v", warnings)
+	}
+	if len(structs) != 2 {
+		t.Fatalf("Expected 2 structs, got %d", len(structs))
+	}
+
+	user := structs[0]
+	if user.TableName != "user" {
+		t.Errorf("Expected table user, got %s", user.TableName)
+	}
+	if fieldNames(user) != "id,email_address,nick,balance,status,avatar,created_at" {
+		t.Errorf("Unexpected columns: %s", fieldNames(user))
+	}
+
+	expected := []struct {
+		sqlType  string
+		size     string
+		nullable bool
+	}{
+		{"BIGINT", "", false},
+		{"VARCHAR", "100", false},
+		{"VARCHAR", "255", true},
+		{"DECIMAL", "10,2", false},
+		{"VARCHAR", "255", false},
+		{"BLOB", "", true},
+		{"DATETIME", "", false},
+	}
+	for i, exp := range expected {
+		field := user.Fields[i]
+		if field.SQLType != exp.sqlType || field.Size != exp.size || field.Nullable != exp.nullable {
+			t.Errorf("Column %s: expected %s(%s) nullable=%v, got %s(%s) nullable=%v",
+				field.ColumnName, exp.sqlType, exp.size, exp.nullable, field.SQLType, field.Size, field.Nullable)
+		}
+	}
+	if !user.Fields[0].PrimaryKey || !user.Fields[0].AutoIncrement {
+		t.Errorf("Expected ID to be an auto-increment primary key, got %+v", user.Fields[0])
+	}
+	if strings.Join(user.Fields[4].EnumValues, ",") != "active,disabled" {
+		t.Errorf("Expected enum values active,disabled, got %v", user.Fields[4].EnumValues)
+	}
+
+	order := structs[1]
+	if order.TableName != "customer_orders" {
+		t.Errorf("Expected table customer_orders, got %s", order.TableName)
+	}
+	if !order.Fields[0].PrimaryKey || !order.Fields[0].Unsigned {
+		t.Errorf("Expected unsigned primary key, got %+v", order.Fields[0])
+	}
+	if len(order.ForeignKeys) != 1 {
+		t.Fatalf("Expected 1 foreign key, got %+v", order.ForeignKeys)
+	}
+	fk := order.ForeignKeys[0]
+	if fk.Columns[0] != "user_id" || fk.RefTable != "user" || fk.RefColumns[0] != "id" {
+		t.Errorf("Unexpected foreign key: %+v", fk)
+	}
+}
+
+// TestParseGoStructs_Embedded tests embedded structs, gorm.Model and bare declarations
+func TestParseGoStructs_Embedded(t *testing.T) {
+	src := `
+type Timestamps struct {
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
+
+type Address struct {
+	City string
+}
+
+type Customer struct {
+	gorm.Model
+	Name     sql.NullString
+	Billing  Address ` + "`gorm:\"embedded;embeddedPrefix:billing_\"`" + `
+	Extra    map[string]any
+}
+
+type Note struct {
+	Timestamps
+	NoteID int ` + "`gorm:\"column:note_id;primaryKey;autoIncrement:false\"`" + `
+}
+`
+
+	structs, warnings, err := ParseGoStructs(src)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Timestamps and Address are parsed as tables too
+	customer, note := findStruct(structs, "Customer"), findStruct(structs, "Note")
+	if customer == nil || note == nil {
+		t.Fatalf("Expected Customer and Note, got %d structs", len(structs))
+	}
+
+	if fieldNames(*customer) != "id,created_at,updated_at,deleted_at,name,billing_city" {
+		t.Errorf("Unexpected customer columns: %s", fieldNames(*customer))
+	}
+	if !customer.Fields[4].Nullable {
+		t.Error("Expected sql.NullString to be nullable")
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "Customer.Extra") {
+		t.Errorf("Expected a warning for the map field, got: %v", warnings)
+	}
+
+	if fieldNames(*note) != "created_at,updated_at,note_id" {
+		t.Errorf("Unexpected note columns: %s", fieldNames(*note))
+	}
+	if !note.Fields[2].PrimaryKey || note.Fields[2].AutoIncrement {
+		t.Errorf("Expected a primary key without auto-increment, got %+v", note.Fields[2])
+	}
+}
+
+// TestParseGoStructs_Errors tests invalid Go source and sources without structs
+func TestParseGoStructs_Errors(t *testing.T) {
+	if _, _, err := ParseGoStructs("type User struct {"); err == nil {
+		t.Error("Expected an error for invalid Go source")
+	}
+	if _, _, err := ParseGoStructs("package models\n\nconst X = 1\n"); err == nil {
+		t.Error("Expected an error for a source without structs")
+	}
+}
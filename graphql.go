@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// graphQLNameRegex matches valid GraphQL type and field names
+var graphQLNameRegex = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// graphQLBuiltinScalars are the scalars every GraphQL schema has, which are not declared
+var graphQLBuiltinScalars = map[string]bool{"ID": true, "Int": true, "Float": true, "String": true, "Boolean": true}
+
+// graphQLInt64Scalar is the custom scalar of integers exceeding Int, which is 32-bit
+const graphQLInt64Scalar = "Int64"
+
+// graphQLScalars maps Go types to GraphQL scalars. Types without an entry are Strings.
+var graphQLScalars = map[string]string{
+	"bool":      "Boolean",
+	"int":       "Int",
+	"int8":      "Int",
+	"int16":     "Int",
+	"int32":     "Int",
+	"int64":     graphQLInt64Scalar,
+	"uint":      graphQLInt64Scalar,
+	"uint8":     "Int",
+	"uint16":    "Int",
+	"uint32":    graphQLInt64Scalar,
+	"uint64":    graphQLInt64Scalar,
+	"float32":   "Float",
+	"float64":   "Float",
+	"string":    "String",
+	"time.Time": "Time",
+}
+
+// GenerateGraphQL generates GraphQL SDL object types for the struct definitions.
+// Fields are camelCase, NOT NULL columns are non-null, a single-column primary key is
+// an ID and ENUM columns get an enum type. Foreign keys add fields for the referenced
+// object and the list of referencing objects, like the association fields of Go structs.
+// config.GraphQLScalars maps SQL types or table.column names to custom scalars,
+// which are declared along with the default Time and Int64 scalars. With
+// config.Int64AsString, int64 and uint64 columns are Strings instead.
+func GenerateGraphQL(defs []StructDef, config Config) string {
+	if len(defs) == 0 {
+		return ""
+	}
+
+	var types, enums strings.Builder
+	scalars := make(map[string]bool)
+	for i, def := range applyAssociations(defs) {
+		if i > 0 {
+			types.WriteString("\n")
+		}
+		types.WriteString(generateGraphQLType(def, config, scalars))
+
+		for _, field := range def.Fields {
+			if len(field.EnumValues) > 0 {
+				enums.WriteString("\n")
+				enums.WriteString(generateGraphQLEnum(def, field))
+			}
+		}
+	}
+
+	// GraphQL comments start with # instead of //, and build constraints don't apply
+	var output strings.Builder
+	config.BuildTags = ""
+	output.WriteString(strings.ReplaceAll(generateFileHeader(defs, config), "// ", "# "))
+
+	names := make([]string, 0, len(scalars))
+	for name := range scalars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		output.WriteString(fmt.Sprintf("scalar %s\n", name))
+	}
+	if len(names) > 0 {
+		output.WriteString("\n")
+	}
+
+	output.WriteString(types.String())
+	output.WriteString(enums.String())
+	return output.String()
+}
+
+// GenerateGraphQLFiles generates the schema as "schema.graphql"; GraphQL servers
+// load several files, but types are not split per table to keep scalars declared once
+func GenerateGraphQLFiles(defs []StructDef, config Config) map[string]string {
+	files := make(map[string]string)
+	if len(defs) > 0 {
+		files["schema.graphql"] = GenerateGraphQL(defs, config)
+	}
+	return files
+}
+
+// generateGraphQLType generates the object type of a table, adding the custom scalars it uses to scalars
+func generateGraphQLType(def StructDef, config Config, scalars map[string]bool) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("\"\"\"\n%s is a row of the %s table.\n\"\"\"\n", def.Name, def.TableName))
+	output.WriteString(fmt.Sprintf("type %s {\n", def.Name))
+
+	keys := primaryKeyColumns(def)
+	for _, field := range def.Fields {
+		if field.Comment != "" {
+			output.WriteString(fmt.Sprintf("  %s\n", graphQLString(strings.Join(strings.Fields(field.Comment), " "))))
+		}
+
+		if field.Association != "" {
+			output.WriteString(fmt.Sprintf("  %s: %s\n", graphQLFieldName(field.Name), graphQLAssociationType(def, field)))
+			continue
+		}
+
+		var fieldType string
+		switch {
+		case len(keys) == 1 && keys[0] == field.ColumnName:
+			fieldType = "ID"
+		case len(field.EnumValues) > 0:
+			fieldType = enumTypeName(def, field)
+		default:
+			fieldType = graphQLScalar(def, field, config)
+			if !graphQLBuiltinScalars[fieldType] {
+				scalars[fieldType] = true
+			}
+		}
+		if !field.Nullable {
+			fieldType += "!"
+		}
+		output.WriteString(fmt.Sprintf("  %s: %s\n", graphQLFieldName(field.ColumnName), fieldType))
+	}
+
+	output.WriteString("}\n")
+	return output.String()
+}
+
+// graphQLScalar returns the scalar of a column: a custom scalar configured for the
+// column or its SQL type, or the scalar of its Go type
+func graphQLScalar(def StructDef, field FieldDef, config Config) string {
+	if scalar, ok := config.GraphQLScalars[def.TableName+"."+field.ColumnName]; ok {
+		return scalar
+	}
+	for key, scalar := range config.GraphQLScalars {
+		if !strings.Contains(key, ".") && strings.EqualFold(key, field.SQLType) {
+			return scalar
+		}
+	}
+	if config.Int64AsString && isInt64Field(field) {
+		return "String"
+	}
+	if scalar, ok := graphQLScalars[strings.TrimPrefix(field.Type, "*")]; ok {
+		return scalar
+	}
+	return "String"
+}
+
+// graphQLString quotes s as a GraphQL string value, escaping quotes, backslashes and
+// control characters; other characters are valid as they are
+func graphQLString(s string) string {
+	var output strings.Builder
+	output.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			output.WriteRune('\\')
+			output.WriteRune(r)
+		case r == '\n':
+			output.WriteString(`\n`)
+		case r == '\t':
+			output.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			output.WriteString(fmt.Sprintf(`\u%04X`, r))
+		default:
+			output.WriteRune(r)
+		}
+	}
+	output.WriteByte('"')
+	return output.String()
+}
+
+// graphQLAssociationType returns the type of a relation field: the referenced object,
+// non-null when the foreign key column is NOT NULL, or a non-null list of objects
+func graphQLAssociationType(def StructDef, field FieldDef) string {
+	if name, ok := strings.CutPrefix(field.Type, "[]"); ok {
+		return "[" + name + "!]!"
+	}
+
+	name := strings.TrimPrefix(field.Type, "*")
+	settings := parseGormTag(field.Association)
+	if i := fieldIndexByName(def.Fields, settings["FOREIGNKEY"]); i >= 0 && !def.Fields[i].Nullable {
+		return name + "!"
+	}
+	return name
+}
+
+// fieldIndexByName returns the index of the field with the given Go name, or -1
+func fieldIndexByName(fields []FieldDef, name string) int {
+	for i, field := range fields {
+		if field.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// generateGraphQLEnum generates the enum type of an ENUM column, with upper-case values
+func generateGraphQLEnum(def StructDef, field FieldDef) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("\"\"\"\n%s is the set of values allowed in %s.%s.\n\"\"\"\n", enumTypeName(def, field), def.TableName, field.ColumnName))
+	output.WriteString(fmt.Sprintf("enum %s {\n", enumTypeName(def, field)))
+
+	seen := make(map[string]bool)
+	for _, value := range field.EnumValues {
+		name := strings.ToUpper(toSnakeCase(enumConstSuffix(value)))
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		output.WriteString(fmt.Sprintf("  %s\n", name))
+	}
+
+	output.WriteString("}\n")
+	return output.String()
+}
+
+// graphQLFieldName converts a column or field name to a camelCase GraphQL field name
+func graphQLFieldName(name string) string {
+	return toCamelCase(name)
+}
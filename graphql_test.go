@@ -0,0 +1,150 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGenerateGraphQL tests object types, enums, scalars and relationship fields
+func TestGenerateGraphQL(t *testing.T) {
+	structs, err := ParseSQL(`
+		CREATE TABLE users (
+			id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			email VARCHAR(255) NOT NULL COMMENT 'login email',
+			status ENUM('active','in-review') NOT NULL,
+			settings JSON,
+			created_at TIMESTAMP
+		);
+		CREATE TABLE posts (
+			id INT NOT NULL PRIMARY KEY,
+			user_id INT NOT NULL,
+			score DOUBLE,
+			FOREIGN KEY (user_id) REFERENCES users(id)
+		);`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := `# Code generated by sql-to-go; DO NOT EDIT.
+
+scalar JSON
+scalar Time
+
+"""
+Users is a row of the users table.
+"""
+type Users {
+  id: ID!
+  "login email"
+  email: String!
+  status: UsersStatus!
+  settings: JSON
+  createdAt: Time
+  posts: [Posts!]!
+}
+
+"""
+Posts is a row of the posts table.
+"""
+type Posts {
+  id: ID!
+  userId: Int!
+  score: Float
+  user: Users!
+}
+
+"""
+UsersStatus is the set of values allowed in users.status.
+"""
+enum UsersStatus {
+  ACTIVE
+  IN_REVIEW
+}
+`
+	config := Config{AddHeader: true, GraphQLScalars: map[string]string{"json": "JSON"}}
+	if code := GenerateGraphQL(structs, config); code != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, code)
+	}
+}
+
+// TestGenerateGraphQL_Scalars tests custom scalars by column and composite primary keys
+func TestGenerateGraphQL_Scalars(t *testing.T) {
+	structs, err := ParseSQL(`CREATE TABLE tags (post_id BIGINT NOT NULL, name VARCHAR(50) NOT NULL, created_at DATETIME NOT NULL, PRIMARY KEY (post_id, name));`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	code := GenerateGraphQL(structs, Config{GraphQLScalars: map[string]string{"tags.post_id": "Int64", "DATETIME": "DateTime"}})
+	for _, want := range []string{"scalar DateTime\nscalar Int64\n\n", "  postId: Int64!\n  name: String!\n  createdAt: DateTime!\n"} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Expected %q in:\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "ID") || strings.Contains(code, "scalar Time") {
+		t.Errorf("Expected no ID or Time scalar, got:\n%s", code)
+	}
+}
+
+// TestGenerateGraphQL_Int64 tests that integers exceeding 32 bits are not Ints, and description escaping
+func TestGenerateGraphQL_Int64(t *testing.T) {
+	structs, err := ParseSQL(`CREATE TABLE events (
+		id BIGINT NOT NULL PRIMARY KEY,
+		account_id BIGINT NOT NULL COMMENT 'owner "account" \ café',
+		hits INT UNSIGNED,
+		day_count INT NOT NULL,
+		version BIGINT UNSIGNED NOT NULL
+	);`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		config   Config
+		expected []string
+	}{
+		{Config{}, []string{"scalar Int64\n", "  accountId: Int64!\n", "  hits: Int64\n", "  dayCount: Int!\n", "  version: Int64!\n"}},
+		{Config{Int64AsString: true}, []string{"  accountId: String!\n", "  hits: Int64\n", "  version: String!\n"}},
+		{Config{GraphQLScalars: map[string]string{"BIGINT": "BigInt"}}, []string{"scalar BigInt\nscalar Int64\n", "  accountId: BigInt!\n", "  hits: Int64\n"}},
+	}
+	for _, tt := range tests {
+		code := GenerateGraphQL(structs, tt.config)
+		for _, want := range append(tt.expected, "  \"owner \\\"account\\\" \\\\ café\"\n") {
+			if !strings.Contains(code, want) {
+				t.Errorf("Expected %q in:\n%s", want, code)
+			}
+		}
+	}
+}
+
+// TestGraphQLString tests GraphQL string escaping
+func TestGraphQLString(t *testing.T) {
+	tests := map[string]string{
+		`plain`:         `"plain"`,
+		`say "hi"`:      `"say \"hi\""`,
+		`back\slash`:    `"back\\slash"`,
+		"bell\x07":      `"bell\u0007"`,
+		"line\nbreak\t": `"line\nbreak\t"`,
+		"café":          `"café"`,
+	}
+	for s, expected := range tests {
+		if got := graphQLString(s); got != expected {
+			t.Errorf("graphQLString(%q): expected %s, got %s", s, expected, got)
+		}
+	}
+}
+
+// TestGraphQLFieldName tests camelCase field names
+func TestGraphQLFieldName(t *testing.T) {
+	tests := map[string]string{
+		"created_at": "createdAt",
+		"UserID":     "userId",
+		"URLPath":    "urlPath",
+		"Posts":      "posts",
+		"email":      "email",
+	}
+	for name, expected := range tests {
+		if got := graphQLFieldName(name); got != expected {
+			t.Errorf("graphQLFieldName(%q): expected %q, got %q", name, expected, got)
+		}
+	}
+}
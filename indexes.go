@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Pre-compiled regex patterns for indexes and UNIQUE constraints
+var (
+	// [CONSTRAINT name] UNIQUE [INDEX|KEY] [name] (cols)
+	uniqueConstraintRegex = regexp.MustCompile(`(?i)^(?:CONSTRAINT\s+` + identPattern + `\s+)?UNIQUE(?:\s+(?:INDEX|KEY))?\s*(?:` + identPattern + `\s*)?(?:USING\s+\w+\s*)?\((.+)\)`)
+	// INDEX|KEY [name] (cols)
+	plainIndexRegex = regexp.MustCompile(`(?i)^(?:INDEX|KEY)\s+(?:` + identPattern + `\s*)?(?:USING\s+\w+\s*)?\((.+)\)`)
+	// Inline column constraint: UNIQUE [KEY]
+	inlineUniqueRegex = regexp.MustCompile(`(?i)\bUNIQUE\b`)
+	// CREATE [UNIQUE] INDEX [CONCURRENTLY] [IF NOT EXISTS] name ON [ONLY] table [USING method] (cols)
+	createIndexRegex = regexp.MustCompile(`(?i)^CREATE\s+(UNIQUE\s+)?INDEX\s+(?:CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\s+)?` + identPattern + `\s+ON\s+(?:ONLY\s+)?` + identPattern + `\s*(?:USING\s+\w+\s*)?\((.+)\)`)
+	// DROP INDEX [CONCURRENTLY] [IF EXISTS] name [ON table]
+	dropIndexRegex = regexp.MustCompile(`(?i)^DROP\s+INDEX\s+(?:CONCURRENTLY\s+)?(?:IF\s+EXISTS\s+)?` + identPattern + `(?:\s+ON\s+` + identPattern + `)?(?:\s+(?:CASCADE|RESTRICT))?$`)
+
+	// ALTER TABLE actions on indexes
+	dropIndexActionRegex   = regexp.MustCompile(`(?i)^DROP\s+(?:INDEX|KEY)\s+(?:IF\s+EXISTS\s+)?` + identPattern + `$`)
+	renameIndexActionRegex = regexp.MustCompile(`(?i)^RENAME\s+(?:INDEX|KEY)\s+` + identPattern + `\s+TO\s+` + identPattern + `$`)
+
+	// Indexed column: name [(prefix length)] [ASC|DESC]
+	indexColumnRegex = regexp.MustCompile(`(?i)^` + identPattern + `\s*(?:\(\s*(\d+)\s*\))?(?:\s+(?:ASC|DESC))?$`)
+)
+
+// Index is an index or UNIQUE constraint of a table
+type Index struct {
+	Name    string   // Index or constraint name, if given
+	Columns []string // Indexed columns
+	Unique  bool     // UNIQUE index or constraint
+	Lengths []int    // Prefix length of each column, 0 for the whole column (MySQL); nil without prefixes
+}
+
+// parseIndex parses a table-level index or UNIQUE constraint.
+// Indexes on expressions are not recorded.
+func parseIndex(line string) (Index, bool) {
+	if matches := uniqueConstraintRegex.FindStringSubmatch(line); matches != nil {
+		name := matches[1]
+		if name == "" {
+			name = matches[2]
+		}
+		columns, lengths := parseIndexColumns(matches[3])
+		return Index{Name: name, Columns: columns, Unique: true, Lengths: lengths}, columns != nil
+	}
+	if matches := plainIndexRegex.FindStringSubmatch(line); matches != nil {
+		columns, lengths := parseIndexColumns(matches[2])
+		return Index{Name: matches[1], Columns: columns, Lengths: lengths}, columns != nil
+	}
+	return Index{}, false
+}
+
+// parseIndexColumns parses an index column list such as name(10) DESC, dropping sort
+// orders. It returns the prefix length of each column, or nil when none has one, and
+// nil columns for indexes on expressions.
+func parseIndexColumns(list string) ([]string, []int) {
+	var columns []string
+	var lengths []int
+	prefixed := false
+	for _, item := range splitColumns(list) {
+		matches := indexColumnRegex.FindStringSubmatch(strings.TrimSpace(item))
+		if matches == nil {
+			return nil, nil
+		}
+		length, _ := strconv.Atoi(matches[2])
+		prefixed = prefixed || length > 0
+		columns = append(columns, matches[1])
+		lengths = append(lengths, length)
+	}
+	if !prefixed {
+		lengths = nil
+	}
+	return columns, lengths
+}
+
+// indexColumnsDDL generates the column list of an index. Prefix lengths are MySQL's.
+func indexColumnsDDL(index Index, dialect Dialect) string {
+	if dialect != DialectMySQL || index.Lengths == nil {
+		return quoteIdents(index.Columns, dialect)
+	}
+	columns := make([]string, len(index.Columns))
+	for i, column := range index.Columns {
+		columns[i] = quoteIdent(column, dialect)
+		if length := indexLength(index, i); length > 0 {
+			columns[i] += fmt.Sprintf("(%d)", length)
+		}
+	}
+	return strings.Join(columns, ", ")
+}
+
+// indexLength returns the prefix length of the i-th column of an index, or 0
+func indexLength(index Index, i int) int {
+	if i < len(index.Lengths) {
+		return index.Lengths[i]
+	}
+	return 0
+}
+
+// indexName returns the name of an index, deriving the name the database gives
+// an unnamed index: the first column on MySQL, users_email_key or users_email_idx
+// like PostgreSQL elsewhere
+func indexName(table string, index Index, dialect Dialect) string {
+	if index.Name != "" {
+		return index.Name
+	}
+	if dialect == DialectMySQL && len(index.Columns) > 0 {
+		return index.Columns[0]
+	}
+	suffix := "idx"
+	if index.Unique {
+		suffix = "key"
+	}
+	return table + "_" + strings.Join(index.Columns, "_") + "_" + suffix
+}
+
+// createIndexDDL generates the CREATE INDEX statement of an index
+func createIndexDDL(table string, index Index, dialect Dialect) string {
+	unique := ""
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s);\n", unique, quoteIdent(indexName(table, index, dialect), dialect),
+		quoteIdent(table, dialect), indexColumnsDDL(index, dialect))
+}
+
+// dropIndexDDL generates the DROP INDEX statement of an index
+func dropIndexDDL(table string, index Index, dialect Dialect) string {
+	name := quoteIdent(indexName(table, index, dialect), dialect)
+	if dialect == DialectMySQL {
+		return fmt.Sprintf("DROP INDEX %s ON %s;\n", name, quoteIdent(table, dialect))
+	}
+	return fmt.Sprintf("DROP INDEX %s;\n", name)
+}
+
+// applyCreateIndex adds the index of a CREATE INDEX statement to its table
+func (s *Schema) applyCreateIndex(stmt string) {
+	matches := createIndexRegex.FindStringSubmatch(stmt)
+	i := s.tableIndex(matches[3])
+	if i == -1 {
+		s.warn("CREATE INDEX on unknown table %s", matches[3])
+		return
+	}
+	columns, lengths := parseIndexColumns(matches[4])
+	if columns == nil {
+		// Indexes on expressions are not part of the model
+		return
+	}
+	s.tables[i].Indexes = append(s.tables[i].Indexes, Index{
+		Name:    matches[2],
+		Columns: columns,
+		Unique:  matches[1] != "",
+		Lengths: lengths,
+	})
+}
+
+// applyDropIndex removes an index by name, from the given table or any table
+func (s *Schema) applyDropIndex(name, table string) {
+	for i := range s.tables {
+		if table != "" && !strings.EqualFold(s.tables[i].TableName, table) {
+			continue
+		}
+		s.tables[i].Indexes = removeIndexes(s.tables[i].Indexes, func(index Index) bool {
+			return strings.EqualFold(index.Name, name)
+		})
+	}
+}
+
+// indexByName returns the position of the index named name (case-insensitive), or -1
+func indexByName(indexes []Index, name string) int {
+	for i, index := range indexes {
+		if strings.EqualFold(index.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// removeIndexes returns the indexes for which remove reports false
+func removeIndexes(indexes []Index, remove func(Index) bool) []Index {
+	var kept []Index
+	for _, index := range indexes {
+		if !remove(index) {
+			kept = append(kept, index)
+		}
+	}
+	return kept
+}
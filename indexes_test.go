@@ -0,0 +1,173 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseSQL_Indexes tests table-level indexes, UNIQUE constraints and inline UNIQUE columns
+func TestParseSQL_Indexes(t *testing.T) {
+	sql := `CREATE TABLE users (
+		id INT NOT NULL PRIMARY KEY,
+		email VARCHAR(255) NOT NULL UNIQUE,
+		name VARCHAR(100) NOT NULL COMMENT 'not unique',
+		tenant_id INT NOT NULL,
+		UNIQUE KEY uq_tenant_name (tenant_id, name(20)),
+		CONSTRAINT uq_name UNIQUE (name),
+		KEY idx_name (name DESC),
+		FULLTEXT KEY ft_name (name)
+	);
+	CREATE INDEX idx_tenant ON users (tenant_id);
+	CREATE INDEX idx_lower_name ON users (lower(name));`
+
+	structs, warnings, err := ParseSQLWithOptions(sql, ParseOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got: %v", warnings)
+	}
+
+	expected := []Index{
+		{Columns: []string{"email"}, Unique: true},
+		{Name: "uq_tenant_name", Columns: []string{"tenant_id", "name"}, Unique: true},
+		{Name: "uq_name", Columns: []string{"name"}, Unique: true},
+		{Name: "idx_name", Columns: []string{"name"}},
+		{Name: "idx_tenant", Columns: []string{"tenant_id"}},
+	}
+	indexes := structs[0].Indexes
+	if len(indexes) != len(expected) {
+		t.Fatalf("Expected %d indexes, got %d: %+v", len(expected), len(indexes), indexes)
+	}
+	for i, index := range indexes {
+		if index.Name != expected[i].Name || index.Unique != expected[i].Unique ||
+			strings.Join(index.Columns, ",") != strings.Join(expected[i].Columns, ",") {
+			t.Errorf("Expected index %+v, got %+v", expected[i], index)
+		}
+	}
+}
+
+// TestSchema_IndexChanges tests index statements and column changes affecting indexes
+func TestSchema_IndexChanges(t *testing.T) {
+	sql := `CREATE TABLE users (id INT NOT NULL, email VARCHAR(255), name VARCHAR(100), nickname VARCHAR(50));
+	CREATE UNIQUE INDEX IF NOT EXISTS uq_email ON users (email);
+	CREATE INDEX idx_name ON users USING btree (name);
+	CREATE INDEX idx_nickname ON users (nickname);
+	ALTER TABLE users ADD INDEX idx_both (name, nickname), RENAME INDEX idx_name TO idx_users_name;
+	ALTER TABLE users RENAME COLUMN email TO email_address;
+	ALTER TABLE users DROP COLUMN nickname;
+	DROP INDEX idx_missing;
+	ALTER TABLE users ADD CONSTRAINT uq_name UNIQUE (name);
+	ALTER TABLE users DROP CONSTRAINT uq_name;`
+
+	structs, _, err := ParseSQLWithOptions(sql, ParseOptions{Dialect: DialectPostgres})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var got []string
+	for _, index := range structs[0].Indexes {
+		got = append(got, index.Name+"("+strings.Join(index.Columns, ",")+")")
+	}
+	expected := "uq_email(email_address) idx_users_name(name)"
+	if strings.Join(got, " ") != expected {
+		t.Errorf("Expected indexes %s, got %s", expected, strings.Join(got, " "))
+	}
+
+	structs, _, err = ParseSQLWithOptions(sql+"; DROP INDEX uq_email ON users; ALTER TABLE users DROP KEY idx_users_name;", ParseOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(structs[0].Indexes) != 0 {
+		t.Errorf("Expected all indexes dropped, got %+v", structs[0].Indexes)
+	}
+}
+
+// TestGenerateDDL_Indexes tests CREATE INDEX output, including unnamed index names per dialect
+func TestGenerateDDL_Indexes(t *testing.T) {
+	defs := []StructDef{{
+		Name:      "User",
+		TableName: "users",
+		Fields: []FieldDef{
+			{Name: "ID", ColumnName: "id", SQLType: "INT", PrimaryKey: true},
+			{Name: "Email", ColumnName: "email", SQLType: "VARCHAR", Size: "255"},
+		},
+		Indexes: []Index{
+			{Columns: []string{"email"}, Unique: true},
+			{Name: "idx_email_id", Columns: []string{"email", "id"}},
+		},
+	}}
+
+	tests := map[Dialect][]string{
+		DialectMySQL:    {"CREATE UNIQUE INDEX email ON users (email);", "CREATE INDEX idx_email_id ON users (email, id);"},
+		DialectPostgres: {"CREATE UNIQUE INDEX users_email_key ON users (email);", "CREATE INDEX idx_email_id ON users (email, id);"},
+	}
+	for dialect, expected := range tests {
+		ddl := GenerateDDL(defs, dialect)
+		for _, statement := range expected {
+			if !strings.Contains(ddl, statement) {
+				t.Errorf("Expected %q in %s DDL, got:\n%s", statement, dialect, ddl)
+			}
+		}
+	}
+}
+
+// TestIndexPrefixLengths tests that MySQL prefix lengths round-trip through DDL and migrations
+func TestIndexPrefixLengths(t *testing.T) {
+	oldDefs, err := ParseSQL(`CREATE TABLE users (
+		id INT NOT NULL PRIMARY KEY,
+		name VARCHAR(100) NOT NULL,
+		bio TEXT,
+		KEY idx_bio (bio(10)),
+		UNIQUE KEY uq_name (name(20), id)
+	);`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if lengths := oldDefs[0].Indexes[0].Lengths; len(lengths) != 1 || lengths[0] != 10 {
+		t.Errorf("Expected prefix length 10, got %v", lengths)
+	}
+
+	ddl := GenerateDDL(oldDefs, DialectMySQL)
+	for _, statement := range []string{"CREATE INDEX idx_bio ON users (bio(10));", "CREATE UNIQUE INDEX uq_name ON users (name(20), id);"} {
+		if !strings.Contains(ddl, statement) {
+			t.Errorf("Expected %q in MySQL DDL, got:\n%s", statement, ddl)
+		}
+	}
+	if ddl := GenerateDDL(oldDefs, DialectPostgres); !strings.Contains(ddl, "CREATE INDEX idx_bio ON users (bio);") {
+		t.Errorf("Expected no prefix length in PostgreSQL DDL, got:\n%s", ddl)
+	}
+
+	newDefs, err := ParseSQL(strings.Replace(ddl, "bio(10)", "bio(32)", 1))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	up, _ := GenerateMigration(oldDefs, newDefs, DialectMySQL)
+	if expected := "DROP INDEX idx_bio ON users;\nCREATE INDEX idx_bio ON users (bio(32));\n"; up != expected {
+		t.Errorf("Expected up migration:\n%s\ngot:\n%s", expected, up)
+	}
+}
+
+// TestParseGoStructs_Indexes tests gorm index, uniqueIndex and unique settings
+func TestParseGoStructs_Indexes(t *testing.T) {
+	source := "type User struct {\n" +
+		"\tID       int\n" +
+		"\tEmail    string `gorm:\"uniqueIndex\"`\n" +
+		"\tTenantID int    `gorm:\"index:idx_tenant_name,priority:1\"`\n" +
+		"\tName     string `gorm:\"index:idx_tenant_name;unique\"`\n" +
+		"}\n"
+
+	structs, _, err := ParseGoStructs(source)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var got []string
+	for _, index := range structs[0].Indexes {
+		got = append(got, index.Name+"("+strings.Join(index.Columns, ",")+")"+map[bool]string{true: "!"}[index.Unique])
+	}
+	expected := "(email)! idx_tenant_name(tenant_id,name) (name)!"
+	if strings.Join(got, " ") != expected {
+		t.Errorf("Expected indexes %s, got %s", expected, strings.Join(got, " "))
+	}
+}
@@ -0,0 +1,429 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql" // MySQL driver, registered as "mysql"
+	_ "github.com/lib/pq"              // PostgreSQL driver, registered as "postgres"
+)
+
+// catalogQueries are the catalog queries of a dialect. Each query returns one row
+// per column in a shape shared by the dialects, ordered by table.
+type catalogQueries struct {
+	columns     string // table, column, type, is_nullable, auto-increment, comment, default (NULL for none), generated and ON UPDATE clauses
+	constraints string // table, constraint, constraint type, column, referenced table, referenced column
+	indexes     string // table, index, unique, column (NULL for expressions)
+	enums       string // enum type, value (PostgreSQL only)
+}
+
+// mysqlCatalog reads the current database of a MySQL connection.
+// Literal defaults are quoted, and generated columns and ON UPDATE are written, like in DDL.
+var mysqlCatalog = catalogQueries{
+	columns: `SELECT c.table_name, c.column_name, c.column_type, c.is_nullable,
+		c.extra LIKE '%auto_increment%', c.column_comment,
+		CASE
+			WHEN c.column_default IS NULL THEN NULL
+			WHEN c.extra LIKE '%DEFAULT_GENERATED%' OR c.column_default LIKE 'CURRENT\_TIMESTAMP%'
+				OR c.data_type IN ('tinyint', 'smallint', 'mediumint', 'int', 'bigint', 'decimal', 'float', 'double', 'bit')
+				THEN c.column_default
+			ELSE CONCAT('''', REPLACE(c.column_default, '''', ''''''), '''')
+		END,
+		CONCAT(
+			CASE WHEN c.generation_expression <> ''
+				THEN CONCAT(' AS (', c.generation_expression, ') ', IF(c.extra LIKE '%STORED%', 'STORED', 'VIRTUAL'))
+				ELSE '' END,
+			CASE WHEN c.extra LIKE '%on update %'
+				THEN CONCAT(' ON UPDATE ', SUBSTRING(c.extra, LOCATE('on update ', c.extra) + 10))
+				ELSE '' END)
+	FROM information_schema.columns c
+	JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+	WHERE c.table_schema = DATABASE() AND t.table_type = 'BASE TABLE'
+	ORDER BY c.table_name, c.ordinal_position`,
+
+	// UNIQUE constraints are indexes in MySQL and are read with them
+	constraints: `SELECT tc.table_name, tc.constraint_name, tc.constraint_type, k.column_name,
+		COALESCE(k.referenced_table_name, ''), COALESCE(k.referenced_column_name, '')
+	FROM information_schema.table_constraints tc
+	JOIN information_schema.key_column_usage k ON k.constraint_schema = tc.constraint_schema
+		AND k.constraint_name = tc.constraint_name AND k.table_name = tc.table_name
+	WHERE tc.table_schema = DATABASE() AND tc.constraint_type IN ('PRIMARY KEY', 'FOREIGN KEY')
+	ORDER BY tc.table_name, tc.constraint_name, k.ordinal_position`,
+
+	indexes: `SELECT table_name, index_name, non_unique = 0, column_name
+	FROM information_schema.statistics
+	WHERE table_schema = DATABASE() AND index_name <> 'PRIMARY'
+	ORDER BY table_name, index_name, seq_in_index`,
+}
+
+// postgresCatalog reads the current schema of a PostgreSQL connection.
+// Columns defaulting to a sequence are reported as SERIAL types, and generated
+// columns with their expression, like in DDL.
+var postgresCatalog = catalogQueries{
+	columns: `SELECT c.table_name, c.column_name,
+		CASE
+			WHEN c.column_default LIKE 'nextval(%' AND c.udt_name = 'int2' THEN 'smallserial'
+			WHEN c.column_default LIKE 'nextval(%' AND c.udt_name = 'int4' THEN 'serial'
+			WHEN c.column_default LIKE 'nextval(%' AND c.udt_name = 'int8' THEN 'bigserial'
+			WHEN c.character_maximum_length IS NOT NULL THEN c.udt_name || '(' || c.character_maximum_length || ')'
+			WHEN c.udt_name = 'numeric' AND c.numeric_precision IS NOT NULL THEN 'numeric(' || c.numeric_precision || ',' || c.numeric_scale || ')'
+			ELSE c.udt_name
+		END,
+		c.is_nullable,
+		c.is_identity = 'YES',
+		COALESCE(col_description(format('%I.%I', c.table_schema, c.table_name)::regclass, c.ordinal_position), ''),
+		CASE WHEN c.column_default LIKE 'nextval(%' THEN NULL ELSE c.column_default END,
+		CASE WHEN c.is_generated = 'ALWAYS' THEN ' GENERATED ALWAYS AS (' || c.generation_expression || ') STORED' ELSE '' END
+	FROM information_schema.columns c
+	JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+	WHERE c.table_schema = current_schema() AND t.table_type = 'BASE TABLE'
+	ORDER BY c.table_name, c.ordinal_position`,
+
+	constraints: `SELECT tc.table_name, tc.constraint_name, tc.constraint_type, k.column_name,
+		COALESCE(r.table_name, ''), COALESCE(r.column_name, '')
+	FROM information_schema.table_constraints tc
+	JOIN information_schema.key_column_usage k ON k.constraint_schema = tc.constraint_schema
+		AND k.constraint_name = tc.constraint_name AND k.table_name = tc.table_name
+	LEFT JOIN information_schema.referential_constraints rc ON rc.constraint_schema = tc.constraint_schema
+		AND rc.constraint_name = tc.constraint_name
+	LEFT JOIN information_schema.key_column_usage r ON r.constraint_schema = rc.unique_constraint_schema
+		AND r.constraint_name = rc.unique_constraint_name AND r.ordinal_position = k.position_in_unique_constraint
+	WHERE tc.table_schema = current_schema() AND tc.constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+	ORDER BY tc.table_name, tc.constraint_name, k.ordinal_position`,
+
+	// Indexes backing constraints are read with the constraints
+	indexes: `SELECT t.relname, i.relname, ix.indisunique, a.attname
+	FROM pg_catalog.pg_index ix
+	JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid
+	JOIN pg_catalog.pg_class t ON t.oid = ix.indrelid
+	JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+	CROSS JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord)
+	LEFT JOIN pg_catalog.pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+	WHERE n.nspname = current_schema()
+		AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_constraint c WHERE c.conindid = ix.indexrelid)
+	ORDER BY t.relname, i.relname, k.ord`,
+
+	enums: `SELECT t.typname, e.enumlabel
+	FROM pg_catalog.pg_type t
+	JOIN pg_catalog.pg_enum e ON e.enumtypid = t.oid
+	JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace
+	WHERE n.nspname = current_schema()
+	ORDER BY t.typname, e.enumsortorder`,
+}
+
+// postgresTypeNames maps PostgreSQL's internal type names to their SQL names
+var postgresTypeNames = map[string]string{
+	"int2":        "SMALLINT",
+	"int4":        "INTEGER",
+	"int8":        "BIGINT",
+	"float4":      "REAL",
+	"float8":      "DOUBLE PRECISION",
+	"bool":        "BOOLEAN",
+	"bpchar":      "CHAR",
+	"varchar":     "VARCHAR",
+	"timestamptz": "TIMESTAMPTZ",
+	"timetz":      "TIMETZ",
+}
+
+// dsnVariableRegex matches the ${VAR} environment variables expanded in a DSN
+var dsnVariableRegex = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// expandDSN expands the ${VAR} environment variables of a DSN. Other uses of $ are
+// kept, as in a password such as p$ss.
+func expandDSN(dsn string) string {
+	return dsnVariableRegex.ReplaceAllStringFunc(dsn, func(variable string) string {
+		return os.Getenv(dsnVariableRegex.FindStringSubmatch(variable)[1])
+	})
+}
+
+// Introspect reads the schema of a live database: a SQLite database file, or a
+// MySQL or PostgreSQL database given by a driver DSN. Environment variables
+// written as ${DB_PASSWORD} in the DSN are expanded.
+func Introspect(database string, dialect Dialect, opts IntrospectOptions) ([]StructDef, []string, error) {
+	if dialect == DialectSQLite {
+		return IntrospectSQLiteFile(database, opts)
+	}
+
+	driverName := "mysql"
+	if dialect == DialectPostgres {
+		driverName = "postgres"
+	}
+	db, err := sql.Open(driverName, expandDSN(database))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return IntrospectInformationSchema(db, dialect, opts)
+}
+
+// IntrospectInformationSchema reads the tables of the current MySQL database or
+// PostgreSQL schema from information_schema (columns, table_constraints and
+// key_column_usage) and the index and enum catalogs, and builds the same struct
+// definitions ParseSQL produces for their DDL. PostgreSQL enum types become ENUM
+// columns, and column comments are kept.
+// Columns of unsupported types are skipped and reported as warnings.
+func IntrospectInformationSchema(db *sql.DB, dialect Dialect, opts IntrospectOptions) ([]StructDef, []string, error) {
+	queries := mysqlCatalog
+	switch dialect {
+	case DialectSQLite:
+		return IntrospectSQLite(db, opts)
+	case DialectPostgres:
+		queries = postgresCatalog
+	}
+
+	enums, err := readEnums(db, queries.enums)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read enum types: %w", err)
+	}
+
+	defs, warnings, err := readCatalogColumns(db, queries.columns, dialect, enums, opts)
+	if err != nil {
+		return nil, warnings, fmt.Errorf("failed to read columns: %w", err)
+	}
+	if err := readCatalogConstraints(db, queries.constraints, defs); err != nil {
+		return nil, warnings, fmt.Errorf("failed to read constraints: %w", err)
+	}
+	if err := readCatalogIndexes(db, queries.indexes, defs); err != nil {
+		return nil, warnings, fmt.Errorf("failed to read indexes: %w", err)
+	}
+
+	var result []StructDef
+	for _, def := range defs {
+		if len(def.Fields) == 0 {
+			warnings = append(warnings, fmt.Sprintf("skipping table %s: no valid columns found", def.TableName))
+			continue
+		}
+		result = append(result, *def)
+	}
+	return result, warnings, nil
+}
+
+// readEnums reads the values of enum types by type name
+func readEnums(db *sql.DB, query string) (map[string][]string, error) {
+	enums := make(map[string][]string)
+	if query == "" {
+		return enums, nil
+	}
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var typeName, value string
+		if err := rows.Scan(&typeName, &value); err != nil {
+			return nil, err
+		}
+		enums[typeName] = append(enums[typeName], value)
+	}
+	return enums, rows.Err()
+}
+
+// readCatalogColumns reads the columns of the included tables, in table order
+func readCatalogColumns(db *sql.DB, query string, dialect Dialect, enums map[string][]string, opts IntrospectOptions) ([]*StructDef, []string, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var defs []*StructDef
+	var warnings []string
+	for rows.Next() {
+		var table, column, columnType, isNullable, comment, clauses string
+		var autoIncrement bool
+		var defaultValue sql.NullString
+		if err := rows.Scan(&table, &column, &columnType, &isNullable, &autoIncrement, &comment, &defaultValue, &clauses); err != nil {
+			return nil, warnings, err
+		}
+		if !opts.includesTable(table) {
+			continue
+		}
+
+		def := findCatalogTable(defs, table)
+		if def == nil {
+			def = &StructDef{Name: toPascalCase(table), TableName: table}
+			defs = append(defs, def)
+		}
+
+		// Reuse the DDL column parser so types map exactly like ParseSQL
+		definition := `"c" ` + catalogColumnType(columnType, dialect, enums)
+		if isNullable == "NO" {
+			definition += " NOT NULL"
+		}
+		if autoIncrement {
+			definition += " AUTO_INCREMENT"
+		}
+		if defaultValue.Valid {
+			definition += " DEFAULT " + defaultValue.String
+		}
+		definition += clauses
+		field, err := parseColumnDefinition(definition, dialect)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s.%s: skipping column of unsupported type %s", table, column, columnType))
+			continue
+		}
+		field.Name = toPascalCase(column)
+		field.ColumnName = column
+		field.Comment = comment
+		def.Fields = append(def.Fields, field)
+	}
+	return defs, warnings, rows.Err()
+}
+
+// catalogColumnType returns the column type reported by the catalog as written in DDL
+func catalogColumnType(columnType string, dialect Dialect, enums map[string][]string) string {
+	if dialect != DialectPostgres {
+		return columnType
+	}
+	if values, ok := enums[columnType]; ok {
+		return "ENUM(" + quoteValues(values) + ")"
+	}
+	// Array types are named after their element type with a leading underscore
+	if element, ok := strings.CutPrefix(columnType, "_"); ok {
+		return catalogColumnType(element, dialect, enums) + "[]"
+	}
+
+	name, args, _ := strings.Cut(columnType, "(")
+	if sqlName, ok := postgresTypeNames[name]; ok {
+		name = sqlName
+	}
+	if args != "" {
+		return name + "(" + args
+	}
+	return name
+}
+
+// readCatalogConstraints adds primary keys, foreign keys and UNIQUE constraints to defs
+func readCatalogConstraints(db *sql.DB, query string, defs []*StructDef) error {
+	rows, err := db.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	primaryKeys := make(map[*StructDef][]string)
+	for rows.Next() {
+		var table, name, constraintType, column, refTable, refColumn string
+		if err := rows.Scan(&table, &name, &constraintType, &column, &refTable, &refColumn); err != nil {
+			return err
+		}
+		def := findCatalogTable(defs, table)
+		if def == nil {
+			continue
+		}
+
+		switch constraintType {
+		case "PRIMARY KEY":
+			primaryKeys[def] = append(primaryKeys[def], column)
+		case "FOREIGN KEY":
+			if n := len(def.ForeignKeys); n > 0 && def.ForeignKeys[n-1].Name == name {
+				def.ForeignKeys[n-1].Columns = append(def.ForeignKeys[n-1].Columns, column)
+				def.ForeignKeys[n-1].RefColumns = append(def.ForeignKeys[n-1].RefColumns, refColumn)
+				continue
+			}
+			def.ForeignKeys = append(def.ForeignKeys, ForeignKey{
+				Name:       name,
+				Columns:    []string{column},
+				RefTable:   refTable,
+				RefColumns: []string{refColumn},
+			})
+		case "UNIQUE":
+			if i := indexByName(def.Indexes, name); i != -1 {
+				def.Indexes[i].Columns = append(def.Indexes[i].Columns, column)
+				continue
+			}
+			def.Indexes = append(def.Indexes, Index{Name: name, Columns: []string{column}, Unique: true})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for def, columns := range primaryKeys {
+		setPrimaryKey(def.Fields, columns)
+	}
+	return nil
+}
+
+// readCatalogIndexes adds the indexes not backing a constraint to defs.
+// Indexes on expressions, and MySQL's implicit indexes of foreign keys, are skipped.
+func readCatalogIndexes(db *sql.DB, query string, defs []*StructDef) error {
+	rows, err := db.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type indexKey struct {
+		def  *StructDef
+		name string
+	}
+	expressions := make(map[indexKey]bool)
+	var keys []indexKey
+	indexes := make(map[indexKey]*Index)
+	for rows.Next() {
+		var table, name string
+		var unique bool
+		var column sql.NullString
+		if err := rows.Scan(&table, &name, &unique, &column); err != nil {
+			return err
+		}
+		def := findCatalogTable(defs, table)
+		if def == nil {
+			continue
+		}
+
+		key := indexKey{def, name}
+		if !column.Valid {
+			expressions[key] = true
+		}
+		if index, ok := indexes[key]; ok {
+			index.Columns = append(index.Columns, column.String)
+			continue
+		}
+		indexes[key] = &Index{Name: name, Columns: []string{column.String}, Unique: unique}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if expressions[key] || isForeignKeyIndex(*key.def, key.name) {
+			continue
+		}
+		key.def.Indexes = append(key.def.Indexes, *indexes[key])
+	}
+	return nil
+}
+
+// isForeignKeyIndex reports whether an index is named after a foreign key of def,
+// as MySQL does for the index it creates for a foreign key
+func isForeignKeyIndex(def StructDef, index string) bool {
+	for _, fk := range def.ForeignKeys {
+		if strings.EqualFold(fk.Name, index) {
+			return true
+		}
+	}
+	return false
+}
+
+// findCatalogTable returns the table named name, or nil
+func findCatalogTable(defs []*StructDef, name string) *StructDef {
+	for _, def := range defs {
+		if def.TableName == name {
+			return def
+		}
+	}
+	return nil
+}
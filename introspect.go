@@ -0,0 +1,348 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver, registered as "sqlite"
+)
+
+// sqliteAutoIncrementRegex finds the AUTOINCREMENT keyword in a column definition
+var sqliteAutoIncrementRegex = regexp.MustCompile(`(?i)\bAUTOINCREMENT\b`)
+
+// IntrospectOptions controls which tables are read from a live database
+type IntrospectOptions struct {
+	Include []string // Table name globs to read (default all)
+	Exclude []string // Table name globs to skip
+}
+
+// includesTable reports whether a table passes the include and exclude globs
+func (opts IntrospectOptions) includesTable(table string) bool {
+	if len(opts.Include) > 0 && !matchesAny(opts.Include, table) {
+		return false
+	}
+	return !matchesAny(opts.Exclude, table)
+}
+
+// IntrospectSQLiteFile opens a SQLite database file read-only and introspects it
+// like IntrospectSQLite
+func IntrospectSQLiteFile(path string, opts IntrospectOptions) ([]StructDef, []string, error) {
+	// Opening a missing file would create an empty database
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+
+	dsn := "file:" + (&url.URL{Path: filepath.ToSlash(path)}).EscapedPath() + "?mode=ro"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	defer db.Close()
+
+	return IntrospectSQLite(db, opts)
+}
+
+// IntrospectSQLite reads the tables of a SQLite database from sqlite_master and
+// PRAGMA table_xinfo, index_list and foreign_key_list, and builds the same struct
+// definitions ParseSQL produces for their DDL. Internal sqlite_ tables are skipped.
+// Columns without a declared type are skipped and reported as warnings.
+func IntrospectSQLite(db *sql.DB, opts IntrospectOptions) ([]StructDef, []string, error) {
+	rows, err := db.Query(`SELECT name, COALESCE(sql, '') FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite\_%' ESCAPE '\' ORDER BY rowid`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	type table struct{ name, sql string }
+	var tables []table
+	for rows.Next() {
+		var t table
+		if err := rows.Scan(&t.name, &t.sql); err != nil {
+			rows.Close()
+			return nil, nil, fmt.Errorf("failed to list tables: %w", err)
+		}
+		if opts.includesTable(t.name) {
+			tables = append(tables, t)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	var defs []StructDef
+	var warnings []string
+	for _, t := range tables {
+		def, tableWarnings, err := introspectSQLiteTable(db, t.name, t.sql)
+		warnings = append(warnings, tableWarnings...)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("failed to introspect table %s: %w", t.name, err)
+		}
+		if len(def.Fields) == 0 {
+			warnings = append(warnings, fmt.Sprintf("skipping table %s: no valid columns found", t.name))
+			continue
+		}
+		defs = append(defs, def)
+	}
+
+	return defs, warnings, nil
+}
+
+// introspectSQLiteTable reads the columns, indexes and foreign keys of a table
+func introspectSQLiteTable(db *sql.DB, name, createSQL string) (StructDef, []string, error) {
+	def := StructDef{Name: toPascalCase(name), TableName: name}
+	var warnings []string
+
+	// table_xinfo also lists generated columns, which table_info hides
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_xinfo(%s)", sqliteQuote(name)))
+	if err != nil {
+		return def, nil, err
+	}
+	var primaryKey []string
+	keyOrder := make(map[string]int)
+	for rows.Next() {
+		var cid, notNull, pk, hidden int
+		var column, columnType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &column, &columnType, &notNull, &defaultValue, &pk, &hidden); err != nil {
+			rows.Close()
+			return def, warnings, err
+		}
+
+		// Reuse the DDL column parser so types map exactly like ParseSQL
+		definition := `"c" ` + columnType
+		if notNull == 1 {
+			definition += " NOT NULL"
+		}
+		if defaultValue.Valid {
+			definition += " DEFAULT " + defaultValue.String
+		}
+		// Generated columns are hidden 2 (virtual) or 3 (stored)
+		if hidden == 2 || hidden == 3 {
+			definition += sqliteGeneratedClause(createSQL, column)
+		}
+		field, err := parseColumnDefinition(definition, DialectSQLite)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s.%s: skipping column without a type", name, column))
+			continue
+		}
+		field.Name = toPascalCase(column)
+		field.ColumnName = column
+		def.Fields = append(def.Fields, field)
+
+		if pk > 0 {
+			primaryKey = append(primaryKey, column)
+			keyOrder[column] = pk
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return def, warnings, err
+	}
+
+	sort.SliceStable(primaryKey, func(i, j int) bool { return keyOrder[primaryKey[i]] < keyOrder[primaryKey[j]] })
+	setPrimaryKey(def.Fields, primaryKey)
+	if !withoutRowIDRegex.MatchString(createSQL) {
+		setSQLiteRowID(&def)
+	}
+	// Only an INTEGER PRIMARY KEY can be declared AUTOINCREMENT
+	if len(primaryKey) == 1 && sqliteAutoIncrement(createSQL, primaryKey[0]) {
+		def.Fields[fieldIndex(def.Fields, primaryKey[0])].AutoIncrement = true
+	}
+
+	if def.Indexes, err = introspectSQLiteIndexes(db, name); err != nil {
+		return def, warnings, err
+	}
+	if def.ForeignKeys, err = introspectSQLiteForeignKeys(db, name); err != nil {
+		return def, warnings, err
+	}
+
+	return def, warnings, nil
+}
+
+// sqliteGeneratedClause returns the AS clause of a generated column, which SQLite only
+// keeps in the stored CREATE TABLE statement, or "" when it cannot be read
+func sqliteGeneratedClause(createSQL, column string) string {
+	defs, _, err := ParseSQLWithOptions(createSQL, ParseOptions{Dialect: DialectSQLite})
+	if err != nil || len(defs) != 1 {
+		return ""
+	}
+	i := fieldIndex(defs[0].Fields, column)
+	if i < 0 || defs[0].Fields[i].Generated == "" {
+		return ""
+	}
+	field := defs[0].Fields[i]
+	return " " + generatedColumnDDL(field, DialectSQLite)
+}
+
+// sqliteAutoIncrement reports whether a column is declared AUTOINCREMENT in the stored
+// CREATE TABLE statement. Comments and quoted strings are removed first, so only the
+// keyword in the column's own definition counts.
+func sqliteAutoIncrement(createSQL, column string) bool {
+	statements := splitStatements(createSQL)
+	if len(statements) != 1 {
+		return false
+	}
+	stmt := removeQuoted(statements[0], "'")
+	start := strings.Index(stmt, "(")
+	if start == -1 {
+		return false
+	}
+	end := findMatchingParen(stmt, start)
+	if end == -1 {
+		return false
+	}
+	for _, line := range splitColumns(stmt[start+1 : end]) {
+		line = strings.TrimSpace(line)
+		if isConstraint(line) {
+			continue
+		}
+		if name, rest := extractColumnName(line); strings.EqualFold(name, column) {
+			return sqliteAutoIncrementRegex.MatchString(removeQuoted(rest, "'\"`"))
+		}
+	}
+	return false
+}
+
+// introspectSQLiteIndexes reads the indexes and UNIQUE constraints of a table.
+// Indexes on expressions are skipped.
+func introspectSQLiteIndexes(db *sql.DB, table string) ([]Index, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA index_list(%s)", sqliteQuote(table)))
+	if err != nil {
+		return nil, err
+	}
+
+	type indexInfo struct {
+		name   string
+		unique bool
+		origin string
+	}
+	var infos []indexInfo
+	for rows.Next() {
+		var seq, unique, partial int
+		var info indexInfo
+		if err := rows.Scan(&seq, &info.name, &unique, &info.origin, &partial); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		info.unique = unique == 1
+		infos = append(infos, info)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// index_list returns the most recent index first
+	var indexes []Index
+	for i := len(infos) - 1; i >= 0; i-- {
+		info := infos[i]
+		if info.origin == "pk" {
+			// The primary key is recorded on the fields
+			continue
+		}
+
+		columns, err := sqliteIndexColumns(db, info.name)
+		if err != nil {
+			return nil, err
+		}
+		if columns == nil {
+			continue
+		}
+
+		index := Index{Columns: columns, Unique: info.unique}
+		// UNIQUE constraints are backed by automatic indexes named sqlite_autoindex_*
+		if info.origin == "c" {
+			index.Name = info.name
+		}
+		indexes = append(indexes, index)
+	}
+
+	// Automatic indexes are created with the table, before any CREATE INDEX
+	sort.SliceStable(indexes, func(i, j int) bool { return indexes[i].Name == "" && indexes[j].Name != "" })
+	return indexes, nil
+}
+
+// sqliteIndexColumns returns the columns of an index, or nil if it indexes an expression
+func sqliteIndexColumns(db *sql.DB, index string) ([]string, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA index_info(%s)", sqliteQuote(index)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	expression := false
+	for rows.Next() {
+		var seqno, cid int
+		var column sql.NullString
+		if err := rows.Scan(&seqno, &cid, &column); err != nil {
+			return nil, err
+		}
+		if !column.Valid {
+			expression = true
+		}
+		columns = append(columns, column.String)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if expression {
+		return nil, nil
+	}
+	return columns, nil
+}
+
+// introspectSQLiteForeignKeys reads the foreign keys of a table in declaration order
+func introspectSQLiteForeignKeys(db *sql.DB, table string) ([]ForeignKey, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA foreign_key_list(%s)", sqliteQuote(table)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[int]*ForeignKey)
+	var ids []int
+	for rows.Next() {
+		var id, seq int
+		var refTable, from, onUpdate, onDelete, match string
+		var to sql.NullString
+		if err := rows.Scan(&id, &seq, &refTable, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+			return nil, err
+		}
+
+		fk, ok := byID[id]
+		if !ok {
+			fk = &ForeignKey{RefTable: refTable}
+			byID[id] = fk
+			ids = append(ids, id)
+		}
+		fk.Columns = append(fk.Columns, from)
+		// A NULL target column references the primary key
+		if to.Valid {
+			fk.RefColumns = append(fk.RefColumns, to.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// SQLite numbers foreign keys from the last declared one
+	sort.Sort(sort.Reverse(sort.IntSlice(ids)))
+	var fks []ForeignKey
+	for _, id := range ids {
+		fks = append(fks, *byID[id])
+	}
+	return fks, nil
+}
+
+// sqliteQuote quotes an identifier for use in a PRAGMA statement
+func sqliteQuote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
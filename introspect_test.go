@@ -0,0 +1,139 @@
+package main
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const introspectSQL = `
+CREATE TABLE users (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	email VARCHAR(255) NOT NULL UNIQUE,
+	name TEXT,
+	score DECIMAL(10,2) NOT NULL,
+	active BOOLEAN NOT NULL,
+	created_at DATETIME NOT NULL
+);
+CREATE TABLE orders (
+	id INTEGER NOT NULL,
+	user_id INTEGER NOT NULL REFERENCES users (id),
+	coupon_id INTEGER,
+	total REAL,
+	total_cents INTEGER GENERATED ALWAYS AS (total * 100) STORED,
+	label TEXT AS ('order ' || id) VIRTUAL,
+	PRIMARY KEY (id),
+	FOREIGN KEY (coupon_id) REFERENCES coupons
+);
+CREATE TABLE coupons (code TEXT NOT NULL, PRIMARY KEY (code));
+CREATE INDEX idx_orders_user ON orders (user_id, total);
+CREATE INDEX idx_lower_email ON users (lower(email));
+CREATE TABLE audit_log (id INTEGER PRIMARY KEY, entry TEXT);`
+
+// createSQLiteDatabase creates a SQLite database file holding the given schema
+func createSQLiteDatabase(t *testing.T, schema string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer db.Close()
+	for _, stmt := range splitStatements(schema) {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("Failed to execute %q: %v", stmt, err)
+		}
+	}
+	return path
+}
+
+// TestIntrospectSQLite tests that introspection matches parsing the same DDL
+func TestIntrospectSQLite(t *testing.T) {
+	path := createSQLiteDatabase(t, introspectSQL)
+
+	structs, warnings, err := IntrospectSQLiteFile(path, IntrospectOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got: %v", warnings)
+	}
+
+	parsed, _, err := ParseSQLWithOptions(introspectSQL, ParseOptions{Dialect: DialectSQLite})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(structs) != len(parsed) {
+		t.Fatalf("Expected %d tables, got %d", len(parsed), len(structs))
+	}
+	for i := range parsed {
+		if !reflect.DeepEqual(structs[i], parsed[i]) {
+			t.Errorf("Expected table %s to match the parsed DDL:\n%+v\ngot:\n%+v", parsed[i].TableName, parsed[i], structs[i])
+		}
+	}
+}
+
+// TestIntrospectSQLite_Filter tests include and exclude globs
+func TestIntrospectSQLite_Filter(t *testing.T) {
+	path := createSQLiteDatabase(t, introspectSQL)
+
+	structs, _, err := IntrospectSQLiteFile(path, IntrospectOptions{Include: []string{"*s"}, Exclude: []string{"coupons"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var names []string
+	for _, def := range structs {
+		names = append(names, def.TableName)
+	}
+	// sqlite_sequence is internal and never included
+	if strings.Join(names, ",") != "users,orders" {
+		t.Errorf("Expected tables users,orders, got %s", strings.Join(names, ","))
+	}
+
+	if _, _, err := IntrospectSQLiteFile(filepath.Join(t.TempDir(), "missing.db"), IntrospectOptions{}); err == nil {
+		t.Error("Expected error for a missing database file")
+	}
+}
+
+// TestIntrospectSQLite_AutoIncrement tests that AUTOINCREMENT only counts in the key column's definition
+func TestIntrospectSQLite_AutoIncrement(t *testing.T) {
+	path := createSQLiteDatabase(t, `CREATE TABLE tokens (
+	code TEXT PRIMARY KEY, -- not AUTOINCREMENT
+	kind TEXT DEFAULT 'AUTOINCREMENT' /* AUTOINCREMENT */,
+	seq INTEGER CHECK (seq > 0 OR kind = 'autoincrement')
+);`)
+
+	structs, _, err := IntrospectSQLiteFile(path, IntrospectOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(structs) != 1 {
+		t.Fatalf("Expected 1 table, got %d", len(structs))
+	}
+	for _, field := range structs[0].Fields {
+		if field.AutoIncrement {
+			t.Errorf("Expected %s not to be auto-increment", field.ColumnName)
+		}
+	}
+
+	tests := []struct {
+		createSQL string
+		expected  bool
+	}{
+		{`CREATE TABLE t (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT)`, true},
+		{`CREATE TABLE t ("id" INTEGER PRIMARY KEY /* key */ AUTOINCREMENT)`, true},
+		{`CREATE TABLE t (id INTEGER PRIMARY KEY, name TEXT DEFAULT 'a, AUTOINCREMENT')`, false},
+		{`CREATE TABLE t (id INTEGER PRIMARY KEY -- AUTOINCREMENT
+)`, false},
+		{`CREATE TABLE t (id INTEGER PRIMARY KEY, next INTEGER CHECK (next > id) /* AUTOINCREMENT */)`, false},
+	}
+
+	for _, tt := range tests {
+		if got := sqliteAutoIncrement(tt.createSQL, "id"); got != tt.expected {
+			t.Errorf("Expected %v for %q, got %v", tt.expected, tt.createSQL, got)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// jsonSchemaDialect is the JSON Schema version of generated schemas, which OpenAPI 3.1 also uses
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// openAPIVersion is the version of generated OpenAPI documents
+const openAPIVersion = "3.1.0"
+
+// jsonSchemaTypes maps Go types to JSON Schema types and OpenAPI formats
+var jsonSchemaTypes = map[string]struct{ Type, Format string }{
+	"bool":      {"boolean", ""},
+	"int":       {"integer", "int64"},
+	"int8":      {"integer", "int32"},
+	"int16":     {"integer", "int32"},
+	"int32":     {"integer", "int32"},
+	"int64":     {"integer", "int64"},
+	"uint":      {"integer", "int64"},
+	"uint8":     {"integer", "int32"},
+	"uint16":    {"integer", "int32"},
+	"uint32":    {"integer", "int64"},
+	"uint64":    {"integer", "int64"},
+	"float32":   {"number", "float"},
+	"float64":   {"number", "double"},
+	"string":    {"string", ""},
+	"time.Time": {"string", "date-time"},
+	"[]byte":    {"string", "byte"},
+}
+
+// jsonObject is a JSON object that keeps its keys in insertion order, so generated
+// schemas list properties in column order
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+// newJSONObject returns an empty ordered JSON object
+func newJSONObject() *jsonObject {
+	return &jsonObject{values: make(map[string]any)}
+}
+
+// Set sets key to value, keeping the position of an existing key
+func (o *jsonObject) Set(key string, value any) *jsonObject {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+	return o
+}
+
+// MarshalJSON encodes the object with its keys in insertion order
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := marshalJSON(key, "")
+		if err != nil {
+			return nil, err
+		}
+		value, err := marshalJSON(o.values[key], "")
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalJSON encodes v without escaping HTML characters, indented with indent if not empty
+func marshalJSON(v any, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	if indent == "" {
+		return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+	}
+	return buf.Bytes(), nil
+}
+
+// GenerateJSONSchema generates a JSON Schema (draft 2020-12) document holding the
+// schema of each struct definition under $defs, keyed by struct name. Properties use
+// the json tag names; NOT NULL columns are required and nullable columns accept null.
+func GenerateJSONSchema(defs []StructDef, config Config) string {
+	if len(defs) == 0 {
+		return ""
+	}
+
+	schemas := newJSONObject()
+	for _, def := range defs {
+		schemas.Set(def.Name, tableJSONSchema(def))
+	}
+
+	doc := newJSONObject().Set("$schema", jsonSchemaDialect).Set("$defs", schemas)
+	return encodeJSONDocument(doc)
+}
+
+// GenerateJSONSchemaFiles generates one JSON Schema document per table, keyed by file name
+// (e.g. "order_items.schema.json")
+func GenerateJSONSchemaFiles(defs []StructDef, config Config) map[string]string {
+	files := make(map[string]string)
+	for _, def := range defs {
+		table := tableJSONSchema(def)
+		schema := newJSONObject().Set("$schema", jsonSchemaDialect)
+		for _, key := range table.keys {
+			schema.Set(key, table.values[key])
+		}
+		name := strings.TrimSuffix(goFileName(def), ".go") + ".schema.json"
+		files[name] = encodeJSONDocument(schema)
+	}
+	return files
+}
+
+// GenerateOpenAPI generates an OpenAPI 3.1 document with the schema of each struct
+// definition in components/schemas, to be merged into or referenced from an API description
+func GenerateOpenAPI(defs []StructDef, config Config) string {
+	if len(defs) == 0 {
+		return ""
+	}
+
+	schemas := newJSONObject()
+	for _, def := range defs {
+		schemas.Set(def.Name, tableJSONSchema(def))
+	}
+
+	doc := newJSONObject().
+		Set("openapi", openAPIVersion).
+		Set("info", newJSONObject().Set("title", openAPITitle(config)).Set("version", "1.0.0")).
+		Set("components", newJSONObject().Set("schemas", schemas))
+	return encodeJSONDocument(doc)
+}
+
+// GenerateOpenAPIFiles generates the OpenAPI document as "openapi.json", as the
+// components of an API description belong to a single document
+func GenerateOpenAPIFiles(defs []StructDef, config Config) map[string]string {
+	files := make(map[string]string)
+	if len(defs) > 0 {
+		files["openapi.json"] = GenerateOpenAPI(defs, config)
+	}
+	return files
+}
+
+// openAPITitle returns the title of a generated OpenAPI document: the package name, if configured
+func openAPITitle(config Config) string {
+	if name := strings.TrimSpace(config.PackageName); name != "" {
+		return name
+	}
+	return "sql-to-go"
+}
+
+// encodeJSONDocument encodes a generated document indented with two spaces
+func encodeJSONDocument(doc *jsonObject) string {
+	data, err := marshalJSON(doc, "  ")
+	if err != nil {
+		// Documents only hold strings, numbers and nested objects
+		panic(err)
+	}
+	return string(data)
+}
+
+// tableJSONSchema returns the object schema of a table
+func tableJSONSchema(def StructDef) *jsonObject {
+	properties := newJSONObject()
+	required := []string{}
+	for _, field := range def.Fields {
+		// Association fields have no column
+		if field.ColumnName == "" {
+			continue
+		}
+		name := jsonFieldName(field)
+		properties.Set(name, fieldJSONSchema(field))
+		if !field.Nullable {
+			required = append(required, name)
+		}
+	}
+
+	schema := newJSONObject().
+		Set("title", def.Name).
+		Set("description", "A row of the "+def.TableName+" table.").
+		Set("type", "object").
+		Set("properties", properties)
+	if len(required) > 0 {
+		schema.Set("required", required)
+	}
+	return schema
+}
+
+// fieldJSONSchema returns the schema of a column: its type and format, maxLength
+// of character columns, the values of ENUM columns and the column comment.
+// Types without a JSON Schema equivalent, such as type overrides, accept any value.
+func fieldJSONSchema(field FieldDef) *jsonObject {
+	schema := newJSONObject()
+	mapped, ok := jsonSchemaTypes[strings.TrimPrefix(field.Type, "*")]
+	if ok {
+		if field.Nullable {
+			schema.Set("type", []string{mapped.Type, "null"})
+		} else {
+			schema.Set("type", mapped.Type)
+		}
+		format := mapped.Format
+		if field.SQLType == "UUID" {
+			format = "uuid"
+		}
+		if format != "" {
+			schema.Set("format", format)
+		}
+	}
+
+	if strings.HasPrefix(field.Type, "uint") || strings.HasPrefix(field.Type, "*uint") {
+		schema.Set("minimum", 0)
+	}
+	if strings.Contains(field.SQLType, "CHAR") {
+		if size, err := strconv.Atoi(field.Size); err == nil {
+			schema.Set("maxLength", size)
+		}
+	}
+	if ok && (field.SQLType == "JSON" || field.SQLType == "JSONB") {
+		schema.Set("contentMediaType", "application/json")
+	}
+
+	if len(field.EnumValues) > 0 {
+		values := make([]any, 0, len(field.EnumValues)+1)
+		for _, value := range field.EnumValues {
+			values = append(values, value)
+		}
+		// enum restricts the type, so null must be one of the values
+		if field.Nullable {
+			values = append(values, nil)
+		}
+		schema.Set("enum", values)
+	}
+
+	if field.Comment != "" {
+		schema.Set("description", field.Comment)
+	}
+	return schema
+}
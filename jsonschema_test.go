@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// jsonSchemaSQL has formats, a size limit, an ENUM, a comment and nullable columns
+const jsonSchemaSQL = `
+CREATE TABLE users (
+	id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	email VARCHAR(255) NOT NULL COMMENT 'login <email>',
+	status ENUM('active','banned'),
+	age TINYINT UNSIGNED NOT NULL,
+	avatar BLOB,
+	deleted_at DATETIME
+);`
+
+// TestGenerateJSONSchemaFiles tests the JSON Schema document of a table
+func TestGenerateJSONSchemaFiles(t *testing.T) {
+	structs, err := ParseSQL(jsonSchemaSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "Users",
+  "description": "A row of the users table.",
+  "type": "object",
+  "properties": {
+    "id": {
+      "type": "integer",
+      "format": "int64"
+    },
+    "email": {
+      "type": "string",
+      "maxLength": 255,
+      "description": "login <email>"
+    },
+    "status": {
+      "type": [
+        "string",
+        "null"
+      ],
+      "enum": [
+        "active",
+        "banned",
+        null
+      ]
+    },
+    "age": {
+      "type": "integer",
+      "format": "int32",
+      "minimum": 0
+    },
+    "avatar": {
+      "type": [
+        "string",
+        "null"
+      ],
+      "format": "byte"
+    },
+    "deleted_at": {
+      "type": [
+        "string",
+        "null"
+      ],
+      "format": "date-time"
+    }
+  },
+  "required": [
+    "id",
+    "email",
+    "age"
+  ]
+}
+`
+	files := GenerateJSONSchemaFiles(structs, Config{})
+	if got := files["users.schema.json"]; got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+// TestGenerateOpenAPI tests the components of an OpenAPI document and the $defs of a JSON Schema
+func TestGenerateOpenAPI(t *testing.T) {
+	structs, _, err := ParseSQLWithOptions(`
+		CREATE TABLE accounts (id UUID PRIMARY KEY, settings JSONB);
+		CREATE TABLE orders (id SERIAL PRIMARY KEY, total NUMERIC(10,2) NOT NULL);`, ParseOptions{Dialect: DialectPostgres})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var doc struct {
+		OpenAPI    string `json:"openapi"`
+		Info       struct{ Title, Version string }
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]any `json:"properties"`
+				Required   []string                  `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	code := GenerateOpenAPI(structs, Config{PackageName: "shop"})
+	if err := json.Unmarshal([]byte(code), &doc); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v\n%s", err, code)
+	}
+	if doc.OpenAPI != "3.1.0" || doc.Info.Title != "shop" || len(doc.Components.Schemas) != 2 {
+		t.Errorf("Unexpected document:\n%s", code)
+	}
+	accounts := doc.Components.Schemas["Accounts"]
+	if accounts.Properties["id"]["format"] != "uuid" || accounts.Properties["settings"]["contentMediaType"] != "application/json" {
+		t.Errorf("Expected uuid and JSON formats, got %v", accounts.Properties)
+	}
+	if orders := doc.Components.Schemas["Orders"]; orders.Properties["total"]["format"] != "double" || strings.Join(orders.Required, ",") != "id,total" {
+		t.Errorf("Unexpected Orders schema: %+v", orders)
+	}
+
+	schema := GenerateJSONSchema(structs, Config{})
+	if !strings.HasPrefix(schema, "{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$defs\": {\n    \"Accounts\": {") {
+		t.Errorf("Expected $defs in table order, got:\n%s", schema)
+	}
+}
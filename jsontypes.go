@@ -0,0 +1,133 @@
+package main
+
+import (
+	"strings"
+)
+
+// jsonTypeName is the name of the generated JSON column wrapper type
+const jsonTypeName = "JSON"
+
+// jsonTypeFileName is the shared file holding the JSON wrapper type in multi-file output
+const jsonTypeFileName = "json.go"
+
+// jsonTypeImports are the packages used by the generated JSON wrapper type
+var jsonTypeImports = []string{"database/sql/driver", "encoding/json", "fmt"}
+
+// generateJSONType generates the generic wrapper type of JSON columns, which marshals
+// its value on write and unmarshals it on read
+func generateJSONType() string {
+	return `// JSON is the value of a JSON column, stored as its JSON encoding.
+// It implements sql.Scanner and driver.Valuer, and encodes as V in JSON.
+type JSON[T any] struct {
+	V T
+}
+
+// Scan implements sql.Scanner by unmarshaling the column value into V
+func (j *JSON[T]) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		var zero T
+		j.V = zero
+		return nil
+	case []byte:
+		return json.Unmarshal(src, &j.V)
+	case string:
+		return json.Unmarshal([]byte(src), &j.V)
+	default:
+		return fmt.Errorf("cannot scan %T into JSON", src)
+	}
+}
+
+// Value implements driver.Valuer by marshaling V
+func (j JSON[T]) Value() (driver.Value, error) {
+	data, err := json.Marshal(j.V)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// MarshalJSON encodes V
+func (j JSON[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.V)
+}
+
+// UnmarshalJSON decodes into V
+func (j *JSON[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &j.V)
+}
+`
+}
+
+// applyJSONTypes returns a copy of defs where JSON and JSONB fields use the JSON wrapper
+// type, or config.JSONWrapper. The wrapped type is the type override of the column,
+// or any. Nullable columns get a pointer to the wrapper.
+func applyJSONTypes(defs []StructDef, config Config) []StructDef {
+	wrapper, _ := jsonWrapperType(config)
+
+	result := make([]StructDef, len(defs))
+	for i, def := range defs {
+		fields := make([]FieldDef, len(def.Fields))
+		for j, field := range def.Fields {
+			if isJSONColumn(field) {
+				field.Type = wrapper + "[" + jsonElementType(field) + "]"
+				if field.Nullable {
+					field.Type = "*" + field.Type
+				}
+			}
+			fields[j] = field
+		}
+		def.Fields = fields
+		result[i] = def
+	}
+	return result
+}
+
+// jsonWrapperType returns the wrapper type of JSON columns as written in code and its
+// import path: config.JSONWrapper, or the generated JSON type
+func jsonWrapperType(config Config) (goType, importPath string) {
+	if config.JSONWrapper != "" {
+		return resolveGoType(config.JSONWrapper)
+	}
+	return jsonTypeName, ""
+}
+
+// jsonElementType returns the type wrapped by the JSON wrapper of a column: its type
+// override without the pointer added for nullability, or any
+func jsonElementType(field FieldDef) string {
+	if field.TypeImport == "" && strings.TrimPrefix(field.Type, "*") == "string" {
+		return "any"
+	}
+	if field.Nullable {
+		return strings.TrimPrefix(field.Type, "*")
+	}
+	return field.Type
+}
+
+// isJSONColumn reports whether a field holds a JSON or JSONB column
+func isJSONColumn(field FieldDef) bool {
+	return field.Association == "" && (field.SQLType == "JSON" || field.SQLType == "JSONB")
+}
+
+// usesJSONType reports whether JSON wrapper types are enabled and used by a field of defs
+func usesJSONType(defs []StructDef, config Config) bool {
+	if !config.AddJSONTypes {
+		return false
+	}
+	for _, def := range defs {
+		for _, field := range def.Fields {
+			if isJSONColumn(field) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonWrapperImports returns the import of config.JSONWrapper when defs use it
+func jsonWrapperImports(defs []StructDef, config Config) []string {
+	if _, importPath := jsonWrapperType(config); importPath != "" && usesJSONType(defs, config) {
+		return []string{importPath}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// jsonTypesSQL has a NOT NULL and a nullable JSON column, and one for a type override
+const jsonTypesSQL = `
+CREATE TABLE users (
+	id INT NOT NULL PRIMARY KEY,
+	settings JSON NOT NULL,
+	meta JSONB,
+	prefs JSON
+);`
+
+// prefsStub is the package of the type override in the tests
+const prefsStub = "package prefs\n\ntype Prefs struct{ Theme string }\n"
+
+// TestGenerateGoCode_JSONTypes tests JSON columns mapped to the generated JSON[T] type
+func TestGenerateGoCode_JSONTypes(t *testing.T) {
+	structs, err := ParseSQL(jsonTypesSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	structs = ApplyTypeOverrides(structs, map[string]string{"users.prefs": "example.com/app/prefs.Prefs"})
+
+	code := GenerateGoCode(structs, Config{AddJSONTypes: true, AddRepository: true})
+
+	expected := `type Users struct {
+	Id       int
+	Settings JSON[any]
+	Meta     *JSON[any]
+	Prefs    *JSON[prefs.Prefs]
+}
+`
+	if !strings.Contains(code, expected) {
+		t.Errorf("Expected struct:\n%s\nGot:\n%s", expected, code)
+	}
+	for _, expected := range []string{
+		"type JSON[T any] struct {",
+		"func (j *JSON[T]) Scan(src any) error {",
+		"func (j JSON[T]) Value() (driver.Value, error) {",
+		"\"database/sql/driver\"\n\t\"encoding/json\"\n\t\"fmt\"\n\n\t\"example.com/app/prefs\"\n",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, code)
+		}
+	}
+
+	typeCheck(t, code, map[string]string{"example.com/app/prefs": prefsStub})
+}
+
+// TestGenerateGoCode_JSONWrapper tests JSON columns mapped to a user-supplied wrapper type
+func TestGenerateGoCode_JSONWrapper(t *testing.T) {
+	structs, err := ParseSQL(jsonTypesSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	code := GenerateGoCode(structs, Config{AddJSONTypes: true, JSONWrapper: "gorm.io/datatypes.JSONType"})
+
+	if !strings.Contains(code, "Settings datatypes.JSONType[any]") || !strings.Contains(code, "Meta     *datatypes.JSONType[any]") {
+		t.Errorf("Expected the wrapper type, got:\n%s", code)
+	}
+	if !strings.Contains(code, `import "gorm.io/datatypes"`) {
+		t.Errorf("Expected the wrapper import, got:\n%s", code)
+	}
+	if strings.Contains(code, "type JSON[") {
+		t.Errorf("Expected no generated JSON type, got:\n%s", code)
+	}
+
+	typeCheck(t, code, map[string]string{"gorm.io/datatypes": "package datatypes\n\ntype JSONType[T any] struct{ data T }\n"})
+}
+
+// TestGenerateGoFiles_JSONTypes tests that the JSON type goes to json.go
+func TestGenerateGoFiles_JSONTypes(t *testing.T) {
+	structs, err := ParseSQL(jsonTypesSQL + "CREATE TABLE json (id INT);")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	files := GenerateGoFiles(structs, Config{AddJSONTypes: true})
+
+	if !strings.Contains(files["json.go"], "type JSON[T any] struct {") {
+		t.Errorf("Expected the JSON type in json.go, got:\n%s", files["json.go"])
+	}
+	if _, ok := files["json_table.go"]; !ok {
+		t.Errorf("Expected the json table in json_table.go, got files %v", sortedFileNames(files))
+	}
+	if strings.Contains(files["users.go"], "type JSON[") || strings.Contains(files["users.go"], "import") {
+		t.Errorf("Expected users.go without the JSON type and imports, got:\n%s", files["users.go"])
+	}
+
+	// Without JSON columns there is no json.go
+	files = GenerateGoFiles(structs[1:], Config{AddJSONTypes: true})
+	if _, ok := files["json.go"]; ok {
+		t.Error("Expected no json.go without JSON columns")
+	}
+}
+
+// TestParseGoStructs_JSONTypes tests that JSON wrapper fields become JSON columns
+func TestParseGoStructs_JSONTypes(t *testing.T) {
+	structs, warnings, err := ParseGoStructs(`package models
+
+type Users struct {
+	ID       int
+	Settings JSON[map[string]any]
+	Meta     *datatypes.JSONType[Meta]
+}`)
+	if err != nil || len(warnings) > 0 {
+		t.Fatalf("Expected no error or warnings, got: %v %v", err, warnings)
+	}
+
+	fields := structs[0].Fields
+	if fields[1].SQLType != "JSON" || fields[1].Nullable {
+		t.Errorf("Expected a NOT NULL JSON column, got %+v", fields[1])
+	}
+	if fields[2].SQLType != "JSON" || !fields[2].Nullable {
+		t.Errorf("Expected a nullable JSON column, got %+v", fields[2])
+	}
+}
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"bytes"
 	"embed"
 	"encoding/json"
 	"io"
 	"log"
 	"net/http"
+	"os"
 	"strings"
 )
 
@@ -14,29 +16,74 @@ var content embed.FS
 
 // ConvertRequest represents the API request body
 type ConvertRequest struct {
-	SQL    string `json:"sql"`
-	Config Config `json:"config"`
+	SQL    string          `json:"sql"`
+	Schema *SchemaDocument `json:"schema,omitempty"` // Schema JSON to generate from instead of SQL
+	Config Config          `json:"config"`
+}
+
+// ParseRequest represents the body of a schema parsing request
+type ParseRequest struct {
+	SQL     string `json:"sql"`
+	Dialect string `json:"dialect"`
+}
+
+// ParseResponse represents the body of a schema parsing response
+type ParseResponse struct {
+	Schema   *SchemaDocument `json:"schema,omitempty"`
+	Warnings []string        `json:"warnings,omitempty"` // Skipped lines and statements
+	Error    string          `json:"error,omitempty"`
+}
+
+// DDLRequest represents the body of a Go struct to DDL request
+type DDLRequest struct {
+	Go      string `json:"go"`
+	Dialect string `json:"dialect"`
 }
 
 // ConvertResponse represents the API response body
 type ConvertResponse struct {
-	Code  string `json:"code,omitempty"`
-	Error string `json:"error,omitempty"`
+	Code  string            `json:"code,omitempty"`
+	Files map[string]string `json:"files,omitempty"`
+	Error string            `json:"error,omitempty"`
 }
 
 func main() {
+	os.Exit(runCLI(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// newServeMux registers the web interface and API routes
+func newServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+
 	// Serve embedded HTML at root
-	http.HandleFunc("/", serveIndex)
+	mux.HandleFunc("/", serveIndex)
 
 	// Serve favicon
-	http.HandleFunc("/favicon.svg", serveFavicon)
+	mux.HandleFunc("/favicon.svg", serveFavicon)
 
 	// API endpoint for conversion
-	http.HandleFunc("/api/convert", handleConvert)
+	mux.HandleFunc("/api/convert", handleConvert)
+
+	// API endpoint for one-file-per-table conversion (JSON or ?format=zip)
+	mux.HandleFunc("/api/convert/files", handleConvertFiles)
+
+	// API endpoint for reverse generation of CREATE TABLE DDL from Go structs
+	mux.HandleFunc("/api/ddl", handleDDL)
+
+	// API endpoint for the parsed schema as schema JSON
+	mux.HandleFunc("/api/parse", handleParse)
 
-	port := ":7860"
-	log.Printf("🚀 SQL to Go Converter server starting on http://localhost%s", port)
-	log.Fatal(http.ListenAndServe(port, nil))
+	return mux
+}
+
+// serve starts the HTTP server on addr
+func serve(addr string) error {
+	host := addr
+	if strings.HasPrefix(addr, ":") {
+		host = "localhost" + addr
+	}
+	log.Printf("🚀 SQL to Go Converter server starting on http://%s", host)
+	return http.ListenAndServe(addr, newServeMux())
 }
 
 // serveIndex serves the embedded index.html
@@ -73,6 +120,136 @@ func serveFavicon(w http.ResponseWriter, r *http.Request) {
 
 // handleConvert handles POST /api/convert
 func handleConvert(w http.ResponseWriter, r *http.Request) {
+	req, structs, ok := decodeConvertRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// Generate Go code
+	code := GenerateGoCode(structs, req.Config)
+
+	// Send success response
+	response := ConvertResponse{
+		Code: code,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleConvertFiles handles POST /api/convert/files
+// Responds with a JSON map of file name to content, or a zip archive with ?format=zip
+func handleConvertFiles(w http.ResponseWriter, r *http.Request) {
+	req, structs, ok := decodeConvertRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// Generate one file per table
+	files := GenerateGoFiles(structs, req.Config)
+
+	if r.URL.Query().Get("format") == "zip" {
+		var buf bytes.Buffer
+		if err := writeZip(&buf, files); err != nil {
+			sendError(w, "Failed to create zip archive", http.StatusInternalServerError)
+			log.Printf("Error creating zip archive: %v", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="models.zip"`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.Bytes())
+		return
+	}
+
+	// Send success response
+	response := ConvertResponse{
+		Files: files,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleDDL handles POST /api/ddl
+func handleDDL(w http.ResponseWriter, r *http.Request) {
+	var req DDLRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	// Validate Go source is not empty
+	if strings.TrimSpace(req.Go) == "" {
+		sendError(w, "Go source cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	dialect, err := ParseDialect(req.Dialect)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Parse Go structs
+	structs, warnings, err := ParseGoStructs(req.Go)
+	for _, warning := range warnings {
+		log.Printf("Warning: %s", warning)
+	}
+	if err != nil {
+		sendError(w, "Go parsing error: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Send success response
+	response := ConvertResponse{
+		Code: GenerateDDL(structs, dialect),
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleParse handles POST /api/parse
+func handleParse(w http.ResponseWriter, r *http.Request) {
+	var req ParseRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	// Validate SQL is not empty
+	if strings.TrimSpace(req.SQL) == "" {
+		sendError(w, "SQL cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	dialect, err := ParseDialect(req.Dialect)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Parse SQL
+	structs, warnings, err := ParseSQLWithOptions(req.SQL, ParseOptions{Dialect: dialect})
+	if err != nil {
+		sendError(w, "SQL parsing error: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Send success response
+	doc := NewSchemaDocument(structs, dialect)
+	response := ParseResponse{
+		Schema:   &doc,
+		Warnings: warnings,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// decodeJSONRequest handles CORS, accepts only POST and decodes the JSON body into v.
+// It returns false when a response has already been written.
+func decodeJSONRequest(w http.ResponseWriter, r *http.Request, v any) bool {
 	// Set CORS headers for local development
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -82,52 +259,62 @@ func handleConvert(w http.ResponseWriter, r *http.Request) {
 	// Handle preflight
 	if r.Method == "OPTIONS" {
 		w.WriteHeader(http.StatusOK)
-		return
+		return false
 	}
 
 	// Only accept POST
 	if r.Method != "POST" {
 		sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+		return false
 	}
 
 	// Read and decode request body
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		sendError(w, "Failed to read request body", http.StatusBadRequest)
-		return
+		return false
 	}
 	defer r.Body.Close()
 
-	var req ConvertRequest
-	if err := json.Unmarshal(body, &req); err != nil {
+	if err := json.Unmarshal(body, v); err != nil {
 		sendError(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
-		return
+		return false
+	}
+
+	return true
+}
+
+// decodeConvertRequest validates and decodes a conversion request and parses its SQL,
+// or converts its schema JSON. It returns false when a response has already been written.
+func decodeConvertRequest(w http.ResponseWriter, r *http.Request) (ConvertRequest, []StructDef, bool) {
+	var req ConvertRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return ConvertRequest{}, nil, false
+	}
+
+	if req.Schema != nil {
+		structs, err := req.Schema.StructDefs()
+		if err != nil {
+			sendError(w, "Invalid schema: "+err.Error(), http.StatusBadRequest)
+			return ConvertRequest{}, nil, false
+		}
+		return req, structs, true
 	}
 
 	// Validate SQL is not empty
 	if strings.TrimSpace(req.SQL) == "" {
 		sendError(w, "SQL cannot be empty", http.StatusBadRequest)
-		return
+		return ConvertRequest{}, nil, false
 	}
 
 	// Parse SQL
 	structs, err := ParseSQL(req.SQL)
 	if err != nil {
 		sendError(w, "SQL parsing error: "+err.Error(), http.StatusBadRequest)
-		return
+		return ConvertRequest{}, nil, false
 	}
 
-	// Generate Go code
-	code := GenerateGoCode(structs, req.Config)
-
-	// Send success response
-	response := ConvertResponse{
-		Code: code,
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	return req, structs, true
 }
 
 // sendError sends an error response
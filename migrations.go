@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration file naming conventions
+var (
+	// golang-migrate: 20240101120000_create_users.up.sql
+	migrateUpFileRegex = regexp.MustCompile(`^(\d+)_(.*)\.up\.sql$`)
+	// Flyway versioned: V1_2__create_users.sql (versions may use . or _ between parts)
+	flywayFileRegex = regexp.MustCompile(`^V(\d+(?:[._]\d+)*)__(.*)\.sql$`)
+	// goose: 00001_create_users.sql
+	gooseFileRegex = regexp.MustCompile(`^(\d+)_(.*)\.sql$`)
+	// goose annotations: -- +goose Up / -- +goose Down
+	gooseAnnotationRegex = regexp.MustCompile(`(?im)^\s*--\s*\+goose\s+(Up|Down)\b.*$`)
+)
+
+// Migration is a single versioned migration file
+type Migration struct {
+	Version []int  // Version parts, compared numerically (Flyway versions have several)
+	Name    string // Description from the file name
+	Path    string // Path of the migration file
+}
+
+// LoadMigrations lists the up migrations of a golang-migrate, goose or Flyway
+// migrations directory in version order. Down/undo migrations and other files are ignored.
+func LoadMigrations(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+
+		var matches []string
+		switch {
+		case strings.HasSuffix(name, ".down.sql"):
+			continue
+		case migrateUpFileRegex.MatchString(name):
+			matches = migrateUpFileRegex.FindStringSubmatch(name)
+		case flywayFileRegex.MatchString(name):
+			matches = flywayFileRegex.FindStringSubmatch(name)
+		case gooseFileRegex.MatchString(name):
+			matches = gooseFileRegex.FindStringSubmatch(name)
+		default:
+			continue
+		}
+
+		version, err := parseMigrationVersion(matches[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    matches[2],
+			Path:    filepath.Join(dir, name),
+		})
+	}
+
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return compareVersions(migrations[i].Version, migrations[j].Version) < 0
+	})
+
+	for i := 1; i < len(migrations); i++ {
+		if compareVersions(migrations[i-1].Version, migrations[i].Version) == 0 {
+			return nil, fmt.Errorf("duplicate migration version in %s and %s",
+				filepath.Base(migrations[i-1].Path), filepath.Base(migrations[i].Path))
+		}
+	}
+
+	return migrations, nil
+}
+
+// ReplayMigrations applies the up migrations of dir in version order
+// and returns the tables of the final schema
+func ReplayMigrations(dir string, opts ParseOptions) ([]StructDef, []string, error) {
+	migrations, err := LoadMigrations(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(migrations) == 0 {
+		return nil, nil, fmt.Errorf("no migrations found in %s", dir)
+	}
+
+	schema := NewSchema(opts)
+	for _, m := range migrations {
+		data, err := os.ReadFile(m.Path)
+		if err != nil {
+			return nil, schema.Warnings(), err
+		}
+		if err := schema.ApplySQL(upMigrationSQL(string(data))); err != nil {
+			return nil, schema.Warnings(), fmt.Errorf("%s: %w", filepath.Base(m.Path), err)
+		}
+	}
+
+	if len(schema.Tables()) == 0 {
+		return nil, schema.Warnings(), fmt.Errorf("migrations in %s define no tables", dir)
+	}
+
+	return schema.Tables(), schema.Warnings(), nil
+}
+
+// upMigrationSQL returns the SQL of the Up section of a goose migration.
+// Files without goose annotations are returned unchanged.
+func upMigrationSQL(sql string) string {
+	annotations := gooseAnnotationRegex.FindAllStringSubmatchIndex(sql, -1)
+	if len(annotations) == 0 {
+		return sql
+	}
+
+	var up strings.Builder
+	for i, a := range annotations {
+		if !strings.EqualFold(sql[a[2]:a[3]], "up") {
+			continue
+		}
+		end := len(sql)
+		if i+1 < len(annotations) {
+			end = annotations[i+1][0]
+		}
+		up.WriteString(sql[a[1]:end])
+		up.WriteString("\n")
+	}
+	return up.String()
+}
+
+// parseMigrationVersion splits a version such as "3", "20240101120000" or "1.2_1" into numeric parts
+func parseMigrationVersion(version string) ([]int, error) {
+	var parts []int
+	for _, part := range strings.FieldsFunc(version, func(r rune) bool { return r == '.' || r == '_' }) {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q", version)
+		}
+		parts = append(parts, n)
+	}
+	return parts, nil
+}
+
+// compareVersions compares two versions part by part; missing parts count as zero
+func compareVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeMigrations(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+// TestReplayMigrations_GolangMigrate tests *.up.sql files applied in numeric version order
+func TestReplayMigrations_GolangMigrate(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"2_add_email.up.sql":      "ALTER TABLE users ADD COLUMN email VARCHAR(255) NOT NULL;",
+		"2_add_email.down.sql":    "ALTER TABLE users DROP COLUMN email;",
+		"10_drop_tmp.up.sql":      "DROP TABLE tmp;",
+		"1_create_users.up.sql":   "CREATE TABLE users (id INT NOT NULL); CREATE TABLE tmp (id INT);",
+		"1_create_users.down.sql": "DROP TABLE users;",
+		"README.md":               "not a migration",
+	})
+
+	structs, warnings, err := ReplayMigrations(dir, ParseOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got: %v", warnings)
+	}
+
+	if len(structs) != 1 || fieldNames(structs[0]) != "id,email" {
+		t.Fatalf("Expected users(id,email), got %+v", structs)
+	}
+}
+
+// TestReplayMigrations_Goose tests that only the goose Up sections are applied
+func TestReplayMigrations_Goose(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"00001_create_users.sql": `-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE users (id INT NOT NULL, name TEXT);
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE users;
+`,
+		"00002_rename.sql": `-- +goose Up
+ALTER TABLE users RENAME TO members;
+-- +goose Down
+ALTER TABLE members RENAME TO users;
+`,
+	})
+
+	structs, _, err := ReplayMigrations(dir, ParseOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(structs) != 1 || structs[0].TableName != "members" || fieldNames(structs[0]) != "id,name" {
+		t.Fatalf("Expected members(id,name), got %+v", structs)
+	}
+}
+
+// TestReplayMigrations_Flyway tests dotted Flyway versions ordered numerically
+func TestReplayMigrations_Flyway(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"V1__init.sql":         "CREATE TABLE users (id INT NOT NULL);",
+		"V1.10__add_b.sql":     "ALTER TABLE users ADD COLUMN b INT;",
+		"V1.2__add_a.sql":      "ALTER TABLE users ADD COLUMN a INT;",
+		"U1.2__undo_add_a.sql": "ALTER TABLE users DROP COLUMN a;",
+	})
+
+	structs, _, err := ReplayMigrations(dir, ParseOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if fieldNames(structs[0]) != "id,a,b" {
+		t.Errorf("Expected id,a,b, got %s", fieldNames(structs[0]))
+	}
+}
+
+// TestReplayMigrations_Errors tests empty directories and duplicate versions
+func TestReplayMigrations_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{"No migrations", map[string]string{"notes.txt": ""}},
+		{"Duplicate version", map[string]string{"1_a.up.sql": "", "1_b.up.sql": ""}},
+		{"No tables", map[string]string{"1_a.up.sql": "SELECT 1;"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ReplayMigrations(writeMigrations(t, tt.files), ParseOptions{}); err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"strings"
+)
+
+// NamingRules controls how table and column names become Go identifiers
+type NamingRules struct {
+	Initialisms bool `json:"initialisms" yaml:"initialisms"` // Upper-case common initialisms: user_id -> UserID
+	Singular    bool `json:"singular" yaml:"singular"`       // Singular struct names: users -> User
+}
+
+// commonInitialisms are the initialisms golint expects to be written in upper case
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true,
+	"EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "LHS": true, "QPS": true, "RAM": true, "RHS": true,
+	"RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true, "XMPP": true,
+	"XSRF": true, "XSS": true,
+}
+
+// ApplyNamingRules returns a copy of defs with struct and field names recomputed from
+// the original table and column names according to rules
+func ApplyNamingRules(defs []StructDef, rules NamingRules) []StructDef {
+	if rules == (NamingRules{}) {
+		return defs
+	}
+
+	result := make([]StructDef, len(defs))
+	for i, def := range defs {
+		if def.TableName != "" {
+			def.Name = structName(def.TableName, rules)
+		}
+
+		fields := make([]FieldDef, len(def.Fields))
+		for j, field := range def.Fields {
+			field.Name = goName(field.ColumnName, rules.Initialisms)
+			fields[j] = field
+		}
+		def.Fields = fields
+		result[i] = def
+	}
+	return result
+}
+
+// structName converts a table name to a struct name
+func structName(tableName string, rules NamingRules) string {
+	if rules.Singular {
+		parts := strings.Split(tableName, "_")
+		parts[len(parts)-1] = singularize(parts[len(parts)-1])
+		tableName = strings.Join(parts, "_")
+	}
+	return goName(tableName, rules.Initialisms)
+}
+
+// goName converts a snake_case name to PascalCase, optionally upper-casing initialisms
+func goName(s string, initialisms bool) string {
+	if !initialisms {
+		return toPascalCase(s)
+	}
+
+	var result strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if commonInitialisms[strings.ToUpper(part)] {
+			result.WriteString(strings.ToUpper(part))
+		} else {
+			result.WriteString(toPascalCase(part))
+		}
+	}
+	return result.String()
+}
+
+// singularize returns the singular form of an English plural noun.
+// It covers the regular forms that show up in table names.
+func singularize(word string) string {
+	lower := strings.ToLower(word)
+
+	switch {
+	case lower == "" || strings.HasSuffix(lower, "ss") || strings.HasSuffix(lower, "us") || strings.HasSuffix(lower, "is"):
+		// address, status, analysis
+		return word
+	case lower == "people":
+		return word[:1] + "erson"
+	case strings.HasSuffix(lower, "ies") && len(lower) > 3:
+		// categories -> category
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "shes"), strings.HasSuffix(lower, "ches"),
+		strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "zes"), strings.HasSuffix(lower, "uses"):
+		// addresses, wishes, batches, boxes, statuses
+		return word[:len(word)-2]
+	case strings.HasSuffix(lower, "s"):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
+
+// pluralize returns the plural form of an English noun.
+// Words that are already plural are returned unchanged.
+func pluralize(word string) string {
+	lower := strings.ToLower(word)
+
+	switch {
+	case lower == "" || singularize(word) != word:
+		return word
+	case strings.HasSuffix(lower, "person"):
+		return word[:len(word)-5] + "eople"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		// category -> categories
+		return word[:len(word)-1] + "ies"
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "sh"), strings.HasSuffix(lower, "ch"),
+		strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"):
+		// status, wish, batch, box
+		return word + "es"
+	default:
+		return word + "s"
+	}
+}
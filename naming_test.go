@@ -0,0 +1,81 @@
+package main
+
+import "testing"
+
+// TestSingularize tests plural table names becoming singular struct names
+func TestSingularize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"users", "user"},
+		{"categories", "category"},
+		{"addresses", "address"},
+		{"statuses", "status"},
+		{"boxes", "box"},
+		{"batches", "batch"},
+		{"people", "person"},
+		{"status", "status"},
+		{"data", "data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := singularize(tt.input); result != tt.expected {
+				t.Errorf("singularize(%s): expected '%s', got '%s'", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestPluralize tests struct names becoming has-many association names
+func TestPluralize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Order", "Orders"},
+		{"Category", "Categories"},
+		{"Day", "Days"},
+		{"Address", "Addresses"},
+		{"Status", "Statuses"},
+		{"Box", "Boxes"},
+		{"Batch", "Batches"},
+		{"Person", "People"},
+		{"Orders", "Orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := pluralize(tt.input); result != tt.expected {
+				t.Errorf("pluralize(%s): expected '%s', got '%s'", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestApplyNamingRules tests initialisms and singular struct names
+func TestApplyNamingRules(t *testing.T) {
+	structs, err := ParseSQL(`CREATE TABLE order_items (id INT NOT NULL, api_url VARCHAR(255), user_id INT)`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	result := ApplyNamingRules(structs, NamingRules{Initialisms: true, Singular: true})
+
+	if result[0].Name != "OrderItem" {
+		t.Errorf("Expected OrderItem, got %s", result[0].Name)
+	}
+
+	expected := []string{"ID", "APIURL", "UserID"}
+	for i, field := range result[0].Fields {
+		if field.Name != expected[i] {
+			t.Errorf("Expected field %s, got %s", expected[i], field.Name)
+		}
+	}
+
+	// The input must not be modified
+	if structs[0].Name != "OrderItems" || structs[0].Fields[0].Name != "Id" {
+		t.Errorf("ApplyNamingRules modified its input: %+v", structs[0])
+	}
+}
@@ -0,0 +1,438 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// projectConfigNames are the file names the CLI looks for, in order
+var projectConfigNames = []string{"sql-to-go.yaml", "sql-to-go.yml", "sql-to-go.json"}
+
+// ProjectConfig is the project configuration file (sql-to-go.yaml or sql-to-go.json).
+// It captures everything needed to reproduce the generated models without flags.
+type ProjectConfig struct {
+	Version int `json:"version" yaml:"version"` // File format version (1)
+
+	// Input and output
+	Dialect    string   `json:"dialect" yaml:"dialect"`       // mysql, postgres or sqlite
+	Input      []string `json:"input" yaml:"input"`           // SQL files or globs, relative to the config file
+	Migrations string   `json:"migrations" yaml:"migrations"` // Migrations directory to replay instead of Input
+	Database   string   `json:"database" yaml:"database"`     // SQLite file or MySQL/PostgreSQL DSN to introspect instead of Input
+	Schema     string   `json:"schema" yaml:"schema"`         // Schema JSON file (or "-" for stdin) to generate from instead of Input
+	Output     string   `json:"output" yaml:"output"`         // Directory, .go file or "-", relative to the config file
+	Target     string   `json:"target" yaml:"target"`         // go (default), ent, proto, typescript, jsonschema, openapi, graphql or template
+	Templates  []string `json:"templates" yaml:"templates"`   // text/template files or globs for the template target, relative to the config file
+
+	// Code generation (see Config)
+	Package    string   `json:"package" yaml:"package"`
+	Tags       []string `json:"tags" yaml:"tags"` // json, db, gorm, xml
+	Header     bool     `json:"header" yaml:"header"`
+	BuildTags  string   `json:"build_tags" yaml:"build_tags"`
+	SourceHash bool     `json:"source_hash" yaml:"source_hash"`
+	EnumTypes  bool     `json:"enum_types" yaml:"enum_types"`
+
+	Int64AsString bool `json:"int64_as_string" yaml:"int64_as_string"` // int64 fields as JSON strings
+
+	JSONTypes   bool   `json:"json_types" yaml:"json_types"`     // JSON[T] wrapper type for JSON and JSONB columns
+	JSONWrapper string `json:"json_wrapper" yaml:"json_wrapper"` // Own generic wrapper type instead of JSON[T] (implies json_types)
+
+	Constructors    bool     `json:"constructors" yaml:"constructors"`         // NewX functions setting literal DEFAULT values
+	DTOs            bool     `json:"dtos" yaml:"dtos"`                         // Create, Update and Response structs
+	ResponseExclude []string `json:"response_exclude" yaml:"response_exclude"` // Columns or table.columns left out of Response structs
+
+	BaseStruct  string   `json:"base_struct" yaml:"base_struct"`   // Embedded struct for shared columns, or gorm.Model
+	BaseColumns []string `json:"base_columns" yaml:"base_columns"` // Columns of base_struct (default detected)
+
+	Associations bool `json:"associations" yaml:"associations"` // Association fields for foreign keys
+	Repository   bool `json:"repository" yaml:"repository"`     // CRUD functions for database/sql
+	SQLX         bool `json:"sqlx" yaml:"sqlx"`                 // sqlx named queries and Get/Select helpers
+
+	// Protocol Buffers output
+	ProtoPackage  string `json:"proto_package" yaml:"proto_package"`   // Package of the .proto files (defaults to package)
+	GoPackage     string `json:"go_package" yaml:"go_package"`         // go_package option
+	ProtoWrappers bool   `json:"proto_wrappers" yaml:"proto_wrappers"` // Wrapper messages instead of optional for nullable columns
+
+	// GraphQL output
+	GraphQLScalars map[string]string `json:"graphql_scalars" yaml:"graphql_scalars"` // SQL type or table.column -> custom scalar
+
+	// Schema transformations
+	Naming        NamingRules            `json:"naming" yaml:"naming"`
+	TypeOverrides map[string]string      `json:"type_overrides" yaml:"type_overrides"` // SQL type or table.column -> Go type
+	Include       []string               `json:"include" yaml:"include"`               // Table name globs to generate (default all)
+	Exclude       []string               `json:"exclude" yaml:"exclude"`               // Table name globs to skip
+	Tables        map[string]TableConfig `json:"tables" yaml:"tables"`                 // Per-table settings by table name
+}
+
+// TableConfig holds the settings of a single table
+type TableConfig struct {
+	Name   string            `json:"name" yaml:"name"`     // Struct name override
+	Fields map[string]string `json:"fields" yaml:"fields"` // Column name -> field name overrides
+}
+
+// FindProjectConfig returns the path of the project configuration file in dir, or "" if there is none
+func FindProjectConfig(dir string) string {
+	for _, name := range projectConfigNames {
+		p := filepath.Join(dir, name)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// LoadProjectConfig reads and validates a YAML or JSON project configuration file.
+// Unknown keys are rejected.
+func LoadProjectConfig(filename string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var pc ProjectConfig
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&pc)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&pc)
+	default:
+		return nil, fmt.Errorf("%s: unsupported config format (expected .yaml, .yml or .json)", filename)
+	}
+	// An empty file decodes to the zero config
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	if err := pc.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return &pc, nil
+}
+
+// Validate checks the configuration for invalid values
+func (pc *ProjectConfig) Validate() error {
+	var errs []error
+
+	if pc.Version != 0 && pc.Version != 1 {
+		errs = append(errs, fmt.Errorf("version: unsupported version %d (expected 1)", pc.Version))
+	}
+
+	if _, err := ParseDialect(pc.Dialect); err != nil {
+		errs = append(errs, fmt.Errorf("dialect: %w", err))
+	}
+
+	if len(pc.Input) > 0 && pc.Migrations != "" {
+		errs = append(errs, fmt.Errorf("input and migrations cannot be used together"))
+	}
+	if pc.Database != "" && (len(pc.Input) > 0 || pc.Migrations != "") {
+		errs = append(errs, fmt.Errorf("database cannot be used together with input or migrations"))
+	}
+	if pc.Schema != "" && (len(pc.Input) > 0 || pc.Migrations != "" || pc.Database != "") {
+		errs = append(errs, fmt.Errorf("schema cannot be used together with input, migrations or database"))
+	}
+
+	switch target := pc.OutputTarget(); {
+	case target == templateTarget:
+		if len(pc.Templates) == 0 {
+			errs = append(errs, fmt.Errorf("target: the template target requires templates"))
+		}
+	case len(pc.Templates) > 0:
+		errs = append(errs, fmt.Errorf("templates cannot be used with target %q", pc.Target))
+	default:
+		if _, ok := LookupGenerator(target); !ok {
+			errs = append(errs, fmt.Errorf("target: unknown target %q (expected one of %s or %s)",
+				pc.Target, strings.Join(GeneratorNames(), ", "), templateTarget))
+		}
+	}
+
+	if pc.Repository && pc.SQLX {
+		errs = append(errs, fmt.Errorf("repository and sqlx cannot be used together"))
+	}
+
+	switch {
+	case pc.BaseStruct == gormModelType:
+		if len(pc.BaseColumns) > 0 {
+			errs = append(errs, fmt.Errorf("base_columns cannot be used with base_struct %s, whose columns are fixed", gormModelType))
+		}
+		// sqlx maps columns by db tags, which gorm.Model's fields don't have
+		if pc.SQLX {
+			errs = append(errs, fmt.Errorf("base_struct %s cannot be used with sqlx", gormModelType))
+		}
+	case pc.BaseStruct != "" && !token.IsIdentifier(pc.BaseStruct):
+		errs = append(errs, fmt.Errorf("base_struct: %q is not a Go identifier or %s", pc.BaseStruct, gormModelType))
+	case pc.BaseStruct == "" && len(pc.BaseColumns) > 0:
+		errs = append(errs, fmt.Errorf("base_columns requires base_struct"))
+	}
+
+	if pc.JSONWrapper != "" && !isQualifiedTypeName(pc.JSONWrapper) {
+		errs = append(errs, fmt.Errorf("json_wrapper: %q is not a qualified Go type name", pc.JSONWrapper))
+	}
+
+	if pc.ProtoPackage != "" && !protoPackageRegex.MatchString(pc.ProtoPackage) {
+		errs = append(errs, fmt.Errorf("proto_package: %q is not a valid protobuf package", pc.ProtoPackage))
+	}
+
+	for key, scalar := range pc.GraphQLScalars {
+		if !graphQLNameRegex.MatchString(scalar) {
+			errs = append(errs, fmt.Errorf("graphql_scalars: %q for %q is not a valid GraphQL name", scalar, key))
+		}
+	}
+
+	if err := applyTagList(&Config{}, strings.Join(pc.Tags, ",")); err != nil {
+		errs = append(errs, fmt.Errorf("tags: %w", err))
+	}
+
+	if pc.Package != "" && !token.IsIdentifier(pc.Package) {
+		errs = append(errs, fmt.Errorf("package: %q is not a valid Go identifier", pc.Package))
+	}
+
+	for _, pattern := range append(append([]string{}, pc.Include...), pc.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("include/exclude: invalid pattern %q", pattern))
+		}
+	}
+
+	for key, goType := range pc.TypeOverrides {
+		if strings.TrimSpace(goType) == "" {
+			errs = append(errs, fmt.Errorf("type_overrides: %q has an empty Go type", key))
+		}
+	}
+
+	for table, tc := range pc.Tables {
+		if tc.Name != "" && !token.IsIdentifier(tc.Name) {
+			errs = append(errs, fmt.Errorf("tables.%s.name: %q is not a valid Go identifier", table, tc.Name))
+		}
+		for column, name := range tc.Fields {
+			if !token.IsIdentifier(name) {
+				errs = append(errs, fmt.Errorf("tables.%s.fields.%s: %q is not a valid Go identifier", table, column, name))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// OutputTarget returns the name of the code generator, defaulting to template
+// when templates are configured and to go otherwise
+func (pc *ProjectConfig) OutputTarget() string {
+	switch {
+	case pc.Target != "":
+		return strings.ToLower(pc.Target)
+	case len(pc.Templates) > 0:
+		return templateTarget
+	}
+	return "go"
+}
+
+// Generator returns the generator of the output target, loading the templates of the template target
+func (pc *ProjectConfig) Generator() (Generator, error) {
+	target := pc.OutputTarget()
+	if target == templateTarget {
+		return LoadTemplateGenerator(pc.Templates...)
+	}
+	gen, ok := LookupGenerator(target)
+	if !ok {
+		return nil, fmt.Errorf("unknown target %q", target)
+	}
+	return gen, nil
+}
+
+// IntrospectOptions returns the options for introspecting the project's database
+func (pc *ProjectConfig) IntrospectOptions() IntrospectOptions {
+	return IntrospectOptions{Include: pc.Include, Exclude: pc.Exclude}
+}
+
+// ParseOptions returns the options for parsing the project's SQL
+func (pc *ProjectConfig) ParseOptions() ParseOptions {
+	dialect, _ := ParseDialect(pc.Dialect)
+	return ParseOptions{Dialect: dialect}
+}
+
+// Config returns the code generation config described by the project
+func (pc *ProjectConfig) Config() Config {
+	config := Config{
+		PackageName:   pc.Package,
+		AddHeader:     pc.Header,
+		BuildTags:     pc.BuildTags,
+		AddSourceHash: pc.SourceHash,
+		AddEnumTypes:  pc.EnumTypes,
+		Int64AsString: pc.Int64AsString,
+		AddJSONTypes:  pc.JSONTypes || pc.JSONWrapper != "",
+		JSONWrapper:   pc.JSONWrapper,
+
+		AddConstructors: pc.Constructors,
+		AddDTOs:         pc.DTOs || len(pc.ResponseExclude) > 0,
+		ResponseExclude: pc.ResponseExclude,
+
+		BaseStruct:  pc.BaseStruct,
+		BaseColumns: pc.BaseColumns,
+
+		AddAssociations: pc.Associations,
+
+		AddRepository: pc.Repository,
+		AddSQLX:       pc.SQLX,
+		Dialect:       pc.ParseOptions().Dialect,
+
+		ProtoPackage:  pc.ProtoPackage,
+		GoPackage:     pc.GoPackage,
+		ProtoWrappers: pc.ProtoWrappers,
+
+		GraphQLScalars: pc.GraphQLScalars,
+	}
+	applyTagList(&config, strings.Join(pc.Tags, ","))
+	return config
+}
+
+// Apply filters, renames and retypes parsed struct definitions according to the project settings
+func (pc *ProjectConfig) Apply(defs []StructDef) []StructDef {
+	var result []StructDef
+	for _, def := range defs {
+		if pc.includesTable(def.TableName) {
+			result = append(result, def)
+		}
+	}
+
+	result = ApplyNamingRules(result, pc.Naming)
+	result = ApplyTypeOverrides(result, pc.TypeOverrides)
+
+	for i, def := range result {
+		tc, ok := pc.Tables[def.TableName]
+		if !ok {
+			continue
+		}
+		if tc.Name != "" {
+			result[i].Name = tc.Name
+		}
+		fields := make([]FieldDef, len(def.Fields))
+		for j, field := range def.Fields {
+			if name, ok := tc.Fields[field.ColumnName]; ok {
+				field.Name = name
+			}
+			fields[j] = field
+		}
+		result[i].Fields = fields
+	}
+
+	return result
+}
+
+// includesTable reports whether a table passes the include and exclude globs
+func (pc *ProjectConfig) includesTable(table string) bool {
+	if len(pc.Include) > 0 && !matchesAny(pc.Include, table) {
+		return false
+	}
+	return !matchesAny(pc.Exclude, table)
+}
+
+// matchesAny reports whether name matches one of the glob patterns
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyTypeOverrides returns a copy of defs with Go types replaced by user-chosen types.
+// Keys are either an SQL type ("UUID") or a column ("users.settings"); column keys win.
+// Values are Go types, qualified by their full import path when needed
+// (e.g. "github.com/google/uuid.UUID" or "encoding/json.RawMessage").
+// Nullable columns get a pointer unless the type is already nillable.
+func ApplyTypeOverrides(defs []StructDef, overrides map[string]string) []StructDef {
+	if len(overrides) == 0 {
+		return defs
+	}
+
+	// SQL type keys are matched case-insensitively
+	bySQLType := make(map[string]string)
+	for key, goType := range overrides {
+		if !strings.Contains(key, ".") {
+			bySQLType[strings.ToUpper(key)] = goType
+		}
+	}
+
+	result := make([]StructDef, len(defs))
+	for i, def := range defs {
+		fields := make([]FieldDef, len(def.Fields))
+		for j, field := range def.Fields {
+			override, ok := overrides[def.TableName+"."+field.ColumnName]
+			if !ok {
+				override, ok = bySQLType[field.SQLType]
+			}
+			if ok {
+				field.Type, field.TypeImport = resolveGoType(override)
+				if field.Nullable && !isNillableType(field.Type) {
+					field.Type = "*" + field.Type
+				}
+			}
+			fields[j] = field
+		}
+		def.Fields = fields
+		result[i] = def
+	}
+	return result
+}
+
+// resolveGoType splits an import-path-qualified type such as "github.com/google/uuid.UUID"
+// into the type as written in code ("uuid.UUID") and its import path
+func resolveGoType(qualified string) (goType, importPath string) {
+	qualified = strings.TrimSpace(qualified)
+
+	// Keep type decorations such as [] or * in front of the qualified name
+	prefixLen := strings.LastIndexAny(qualified, "*]") + 1
+	prefix, name := qualified[:prefixLen], qualified[prefixLen:]
+
+	slash := strings.LastIndex(name, "/")
+	dot := strings.LastIndex(name, ".")
+	if dot <= slash {
+		// Builtin or unqualified type
+		return qualified, ""
+	}
+
+	importPath = name[:dot]
+	return prefix + goPackageName(importPath) + name[dot:], importPath
+}
+
+// isQualifiedTypeName reports whether an import-path-qualified type is a plain type name,
+// such as "gorm.io/datatypes.JSONType" or "JSON", without pointer or slice decorations
+func isQualifiedTypeName(qualified string) bool {
+	goType, _ := resolveGoType(qualified)
+	if pkg, name, ok := strings.Cut(goType, "."); ok {
+		return token.IsIdentifier(pkg) && token.IsIdentifier(name)
+	}
+	return token.IsIdentifier(goType)
+}
+
+// goPackageName guesses the package name of an import path from its last element,
+// skipping major version suffixes (/v2) and gopkg.in style versions (yaml.v3)
+func goPackageName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+	name, _, _ = strings.Cut(name, ".")
+	return strings.ReplaceAll(name, "-", "")
+}
+
+// isNillableType reports whether a Go type already has a nil value
+func isNillableType(goType string) bool {
+	return strings.HasPrefix(goType, "*") || strings.HasPrefix(goType, "[]") ||
+		strings.HasPrefix(goType, "map[") || goType == "any" || goType == "interface{}" ||
+		goType == "json.RawMessage"
+}
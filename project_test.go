@@ -0,0 +1,198 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return p
+}
+
+// TestLoadProjectConfig_YAML tests loading a complete YAML configuration
+func TestLoadProjectConfig_YAML(t *testing.T) {
+	p := writeTestFile(t, t.TempDir(), "sql-to-go.yaml", `
+version: 1
+dialect: postgres
+input: [schema.sql]
+output: models/
+package: models
+tags: [json, db]
+header: true
+naming:
+  initialisms: true
+  singular: true
+type_overrides:
+  UUID: github.com/google/uuid.UUID
+exclude: [schema_migrations]
+tables:
+  users:
+    name: Account
+`)
+
+	pc, err := LoadProjectConfig(p)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if pc.ParseOptions().Dialect != DialectPostgres {
+		t.Errorf("Expected postgres dialect, got %s", pc.ParseOptions().Dialect)
+	}
+
+	config := pc.Config()
+	if config.PackageName != "models" || !config.AddJSONTag || !config.AddDBTag || config.AddGormTag || !config.AddHeader {
+		t.Errorf("Unexpected generation config: %+v", config)
+	}
+
+	if pc.Tables["users"].Name != "Account" {
+		t.Errorf("Expected users renamed to Account, got %+v", pc.Tables)
+	}
+}
+
+// TestLoadProjectConfig_JSON tests loading a JSON configuration
+func TestLoadProjectConfig_JSON(t *testing.T) {
+	p := writeTestFile(t, t.TempDir(), "sql-to-go.json", `{"dialect": "sqlite", "tags": ["gorm"]}`)
+
+	pc, err := LoadProjectConfig(p)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if pc.ParseOptions().Dialect != DialectSQLite || !pc.Config().AddGormTag {
+		t.Errorf("Unexpected config: %+v", pc)
+	}
+}
+
+// TestLoadProjectConfig_Errors tests that unknown keys and invalid values are rejected with clear errors
+func TestLoadProjectConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		expected string
+	}{
+		{"Unknown YAML key", "sql-to-go.yaml", "dialect: mysql\npackge: models\n", "field packge not found"},
+		{"Unknown nested YAML key", "sql-to-go.yaml", "naming:\n  plural: true\n", "field plural not found"},
+		{"Unknown JSON key", "sql-to-go.json", `{"packge": "models"}`, `unknown field "packge"`},
+		{"Invalid dialect", "sql-to-go.yaml", "dialect: oracle\n", "dialect: unknown dialect"},
+		{"Invalid tag", "sql-to-go.yaml", "tags: [yaml]\n", "tags: unknown tag"},
+		{"Invalid package", "sql-to-go.yaml", "package: my-models\n", "package:"},
+		{"Invalid rename", "sql-to-go.yaml", "tables:\n  users:\n    name: 1User\n", "tables.users.name"},
+		{"Database with input", "sql-to-go.yaml", "dialect: sqlite\ndatabase: app.db\ninput: [schema.sql]\n", "database cannot be used"},
+		{"Schema with migrations", "sql-to-go.yaml", "schema: schema.json\nmigrations: migrations\n", "schema cannot be used"},
+		{"Invalid JSON wrapper", "sql-to-go.yaml", "json_wrapper: \"*datatypes.JSONType\"\n", "json_wrapper:"},
+		{"Repository with sqlx", "sql-to-go.yaml", "repository: true\nsqlx: true\n", "repository and sqlx"},
+		{"Invalid base struct", "sql-to-go.yaml", "base_struct: models.Base\n", "base_struct:"},
+		{"Base columns without base struct", "sql-to-go.yaml", "base_columns: [id]\n", "base_columns requires base_struct"},
+		{"gorm.Model with base columns", "sql-to-go.yaml", "base_struct: gorm.Model\nbase_columns: [id]\n", "columns are fixed"},
+		{"gorm.Model with sqlx", "sql-to-go.yaml", "base_struct: gorm.Model\nsqlx: true\n", "cannot be used with sqlx"},
+		{"Invalid GraphQL scalar", "sql-to-go.yaml", "graphql_scalars:\n  JSON: json-scalar\n", "graphql_scalars:"},
+		{"Invalid proto package", "sql-to-go.yaml", "target: proto\nproto_package: acme..v1\n", "proto_package:"},
+		{"Unsupported format", "sql-to-go.toml", "", "unsupported config format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := writeTestFile(t, t.TempDir(), tt.file, tt.content)
+			_, err := LoadProjectConfig(p)
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// TestProjectConfig_Apply tests table filtering, naming rules, renames and type overrides
+func TestProjectConfig_Apply(t *testing.T) {
+	sql := `CREATE TABLE users (
+		user_id INT NOT NULL,
+		external_id UUID NOT NULL,
+		backup_id UUID,
+		settings JSON,
+		email_addr VARCHAR(255)
+	);
+	CREATE TABLE categories (id INT NOT NULL);
+	CREATE TABLE schema_migrations (version BIGINT NOT NULL);`
+
+	structs, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	pc := &ProjectConfig{
+		Naming:  NamingRules{Initialisms: true, Singular: true},
+		Exclude: []string{"schema_*"},
+		TypeOverrides: map[string]string{
+			"uuid":           "github.com/google/uuid.UUID",
+			"users.settings": "encoding/json.RawMessage",
+		},
+		Tables: map[string]TableConfig{
+			"users": {Fields: map[string]string{"email_addr": "Email"}},
+		},
+	}
+
+	result := pc.Apply(structs)
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 structs after exclude, got %d", len(result))
+	}
+	if result[0].Name != "User" || result[1].Name != "Category" {
+		t.Errorf("Expected User and Category, got %s and %s", result[0].Name, result[1].Name)
+	}
+
+	expected := map[string]string{
+		"UserID":     "int",
+		"ExternalID": "uuid.UUID",
+		"BackupID":   "*uuid.UUID",
+		"Settings":   "json.RawMessage",
+		"Email":      "*string",
+	}
+	for _, field := range result[0].Fields {
+		if field.Type != expected[field.Name] {
+			t.Errorf("Field %s: expected type %q, got %q", field.Name, expected[field.Name], field.Type)
+		}
+	}
+
+	code := GenerateGoCode(result[:1], Config{})
+	if !strings.Contains(code, "import (\n\t\"encoding/json\"\n\n\t\"github.com/google/uuid\"\n)") {
+		t.Errorf("Expected grouped imports, got:\n%s", code)
+	}
+}
+
+// TestResolveGoType tests splitting import-path-qualified types
+func TestResolveGoType(t *testing.T) {
+	tests := []struct {
+		input      string
+		goType     string
+		importPath string
+	}{
+		{"string", "string", ""},
+		{"[]byte", "[]byte", ""},
+		{"time.Time", "time.Time", "time"},
+		{"github.com/google/uuid.UUID", "uuid.UUID", "github.com/google/uuid"},
+		{"github.com/shopspring/decimal.Decimal", "decimal.Decimal", "github.com/shopspring/decimal"},
+		{"github.com/jackc/pgx/v5/pgtype.Text", "pgtype.Text", "github.com/jackc/pgx/v5/pgtype"},
+		{"github.com/gofrs/uuid/v5.UUID", "uuid.UUID", "github.com/gofrs/uuid/v5"},
+		{"gopkg.in/guregu/null.v4.String", "null.String", "gopkg.in/guregu/null.v4"},
+		{"[]github.com/google/uuid.UUID", "[]uuid.UUID", "github.com/google/uuid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			goType, importPath := resolveGoType(tt.input)
+			if goType != tt.goType || importPath != tt.importPath {
+				t.Errorf("resolveGoType(%s): expected (%s, %s), got (%s, %s)", tt.input, tt.goType, tt.importPath, goType, importPath)
+			}
+		})
+	}
+}
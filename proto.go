@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Imports of the protobuf well-known types used by generated messages
+const (
+	protoTimestampImport = "google/protobuf/timestamp.proto"
+	protoWrappersImport  = "google/protobuf/wrappers.proto"
+)
+
+// protoPackageRegex matches a dotted protobuf package name such as "acme.users.v1"
+var protoPackageRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// protoScalarTypes maps Go types to protobuf scalar types
+var protoScalarTypes = map[string]string{
+	"bool":    "bool",
+	"int":     "int64",
+	"int8":    "int32",
+	"int16":   "int32",
+	"int32":   "int32",
+	"int64":   "int64",
+	"uint":    "uint64",
+	"uint8":   "uint32",
+	"uint16":  "uint32",
+	"uint32":  "uint32",
+	"uint64":  "uint64",
+	"float32": "float",
+	"float64": "double",
+	"string":  "string",
+	"[]byte":  "bytes",
+}
+
+// protoWrapperTypes maps protobuf scalar types to the wrapper messages used for nullable columns
+var protoWrapperTypes = map[string]string{
+	"bool":   "google.protobuf.BoolValue",
+	"int32":  "google.protobuf.Int32Value",
+	"int64":  "google.protobuf.Int64Value",
+	"uint32": "google.protobuf.UInt32Value",
+	"uint64": "google.protobuf.UInt64Value",
+	"float":  "google.protobuf.FloatValue",
+	"double": "google.protobuf.DoubleValue",
+	"string": "google.protobuf.StringValue",
+	"bytes":  "google.protobuf.BytesValue",
+}
+
+// GenerateProto generates a proto3 file with one message per struct definition.
+// Fields keep the snake_case column names and are numbered in column order, time
+// columns use google.protobuf.Timestamp and ENUM columns get a nested enum.
+// Nullable columns are optional, or wrapper messages with config.ProtoWrappers.
+func GenerateProto(defs []StructDef, config Config) string {
+	if len(defs) == 0 {
+		return ""
+	}
+
+	var body strings.Builder
+	imports := make(map[string]bool)
+	for i, def := range defs {
+		if i > 0 {
+			body.WriteString("\n")
+		}
+		body.WriteString(generateProtoMessage(def, config, imports))
+	}
+
+	return generateProtoFile(defs, imports, body.String(), config)
+}
+
+// GenerateProtoFiles generates one .proto file per table, keyed by file name (e.g. "order_items.proto")
+func GenerateProtoFiles(defs []StructDef, config Config) map[string]string {
+	files := make(map[string]string)
+	for i, def := range defs {
+		imports := make(map[string]bool)
+		body := generateProtoMessage(def, config, imports)
+		name := strings.TrimSuffix(goFileName(def), ".go") + ".proto"
+		files[name] = generateProtoFile(defs[i:i+1], imports, body, config)
+	}
+	return files
+}
+
+// generateProtoFile assembles a .proto file: header comments, syntax, package, imports and options
+func generateProtoFile(source []StructDef, imports map[string]bool, body string, config Config) string {
+	var output strings.Builder
+
+	// Build constraints don't apply to .proto files
+	config.BuildTags = ""
+	output.WriteString(generateFileHeader(source, config))
+	output.WriteString("syntax = \"proto3\";\n\n")
+
+	if pkg := protoPackage(config); pkg != "" {
+		output.WriteString(fmt.Sprintf("package %s;\n\n", pkg))
+	}
+
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for imp := range imports {
+			paths = append(paths, imp)
+		}
+		sort.Strings(paths)
+		for _, imp := range paths {
+			output.WriteString(fmt.Sprintf("import %q;\n", imp))
+		}
+		output.WriteString("\n")
+	}
+
+	if goPackage := strings.TrimSpace(config.GoPackage); goPackage != "" {
+		output.WriteString(fmt.Sprintf("option go_package = %q;\n\n", goPackage))
+	}
+
+	output.WriteString(body)
+	return output.String()
+}
+
+// protoPackage returns the configured protobuf package, defaulting to the Go package name
+func protoPackage(config Config) string {
+	if pkg := strings.TrimSpace(config.ProtoPackage); pkg != "" {
+		return pkg
+	}
+	return strings.TrimSpace(config.PackageName)
+}
+
+// generateProtoMessage generates the message of a table, adding the well-known types it uses to imports
+func generateProtoMessage(def StructDef, config Config, imports map[string]bool) string {
+	var enums, fields strings.Builder
+
+	number := 0
+	for _, field := range def.Fields {
+		// Association fields have no column
+		if field.ColumnName == "" {
+			continue
+		}
+		number++
+
+		protoType := protoFieldType(field)
+		if len(field.EnumValues) > 0 {
+			protoType = field.Name
+			if enums.Len() > 0 {
+				enums.WriteString("\n")
+			}
+			enums.WriteString(generateProtoEnum(field))
+		}
+
+		label := ""
+		switch {
+		case protoType == "google.protobuf.Timestamp":
+			imports[protoTimestampImport] = true
+		case !field.Nullable:
+		case config.ProtoWrappers && protoWrapperTypes[protoType] != "":
+			protoType = protoWrapperTypes[protoType]
+			imports[protoWrappersImport] = true
+		default:
+			label = "optional "
+		}
+
+		if field.Comment != "" {
+			fields.WriteString(fmt.Sprintf("  // %s\n", strings.Join(strings.Fields(field.Comment), " ")))
+		}
+		fields.WriteString(fmt.Sprintf("  %s%s %s = %d;\n", label, protoType, toSnakeCase(field.ColumnName), number))
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("// %s is a row of the %s table.\n", def.Name, def.TableName))
+	output.WriteString(fmt.Sprintf("message %s {\n", def.Name))
+	if enums.Len() > 0 {
+		output.WriteString(enums.String())
+		output.WriteString("\n")
+	}
+	output.WriteString(fields.String())
+	output.WriteString("}\n")
+	return output.String()
+}
+
+// protoFieldType returns the protobuf type of a column. Types without a protobuf
+// equivalent, such as type overrides, are carried as strings.
+func protoFieldType(field FieldDef) string {
+	goType := strings.TrimPrefix(field.Type, "*")
+	if goType == "time.Time" {
+		return "google.protobuf.Timestamp"
+	}
+	if protoType, ok := protoScalarTypes[goType]; ok {
+		return protoType
+	}
+	return "string"
+}
+
+// generateProtoEnum generates the nested enum of an ENUM column. Values are prefixed
+// with the field name, as enum values share the scope of the message, and start
+// with the UNSPECIFIED zero value proto3 requires.
+func generateProtoEnum(field FieldDef) string {
+	prefix := strings.ToUpper(toSnakeCase(field.Name))
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("  enum %s {\n", field.Name))
+	output.WriteString(fmt.Sprintf("    %s_UNSPECIFIED = 0;\n", prefix))
+
+	seen := map[string]bool{prefix + "_UNSPECIFIED": true}
+	for i, value := range field.EnumValues {
+		name := prefix + "_" + strings.ToUpper(toSnakeCase(enumConstSuffix(value)))
+		for n := 2; seen[name]; n++ {
+			name = fmt.Sprintf("%s_%s_%d", prefix, strings.ToUpper(toSnakeCase(enumConstSuffix(value))), n)
+		}
+		seen[name] = true
+		output.WriteString(fmt.Sprintf("    %s = %d;\n", name, i+1))
+	}
+
+	output.WriteString("  }\n")
+	return output.String()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// protoSQL has the column types with a dedicated protobuf mapping
+const protoSQL = `
+CREATE TABLE users (
+	id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	email VARCHAR(255) NOT NULL COMMENT 'login email',
+	age TINYINT UNSIGNED,
+	status ENUM('active','in-progress','') NOT NULL,
+	avatar BLOB,
+	score DECIMAL(5,2),
+	created_at TIMESTAMP NOT NULL,
+	deleted_at DATETIME
+);
+CREATE TABLE tags (name VARCHAR(50) NOT NULL);`
+
+// TestGenerateProto tests the message, enum and options of a .proto file
+func TestGenerateProto(t *testing.T) {
+	structs, err := ParseSQL(protoSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := `// Code generated by sql-to-go; DO NOT EDIT.
+
+syntax = "proto3";
+
+package acme.users.v1;
+
+import "google/protobuf/timestamp.proto";
+
+option go_package = "example.com/app/pb";
+
+// Users is a row of the users table.
+message Users {
+  enum Status {
+    STATUS_UNSPECIFIED = 0;
+    STATUS_ACTIVE = 1;
+    STATUS_IN_PROGRESS = 2;
+    STATUS_EMPTY = 3;
+  }
+
+  int64 id = 1;
+  // login email
+  string email = 2;
+  optional uint32 age = 3;
+  Status status = 4;
+  optional bytes avatar = 5;
+  optional double score = 6;
+  google.protobuf.Timestamp created_at = 7;
+  google.protobuf.Timestamp deleted_at = 8;
+}
+
+// Tags is a row of the tags table.
+message Tags {
+  string name = 1;
+}
+`
+	config := Config{AddHeader: true, BuildTags: "integration", ProtoPackage: "acme.users.v1", GoPackage: "example.com/app/pb"}
+	if code := GenerateProto(structs, config); code != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, code)
+	}
+}
+
+// TestGenerateProto_Wrappers tests wrapper messages for nullable columns
+func TestGenerateProto_Wrappers(t *testing.T) {
+	structs, err := ParseSQL(protoSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	code := GenerateProto(structs, Config{PackageName: "models", ProtoWrappers: true})
+	expected := []string{
+		"package models;",
+		"import \"google/protobuf/timestamp.proto\";\nimport \"google/protobuf/wrappers.proto\";",
+		"google.protobuf.UInt32Value age = 3;",
+		"google.protobuf.BytesValue avatar = 5;",
+		"google.protobuf.Timestamp deleted_at = 8;",
+	}
+	for _, want := range expected {
+		if !strings.Contains(code, want) {
+			t.Errorf("Expected %q in:\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "optional") {
+		t.Errorf("Expected no optional fields, got:\n%s", code)
+	}
+}
+
+// TestGenerateProtoFiles tests that each file only imports the well-known types it uses
+func TestGenerateProtoFiles(t *testing.T) {
+	structs, err := ParseSQL(protoSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	files := GenerateProtoFiles(structs, Config{})
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 files, got %v", sortedFileNames(files))
+	}
+	if !strings.Contains(files["users.proto"], "import \"google/protobuf/timestamp.proto\";") {
+		t.Errorf("Expected the Timestamp import in users.proto, got:\n%s", files["users.proto"])
+	}
+	if tags := files["tags.proto"]; tags != "syntax = \"proto3\";\n\n// Tags is a row of the tags table.\nmessage Tags {\n  string name = 1;\n}\n" {
+		t.Errorf("Unexpected tags.proto:\n%s", tags)
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Pre-compiled regex patterns for foreign keys
+var (
+	// [CONSTRAINT name] FOREIGN KEY [index_name] (cols) REFERENCES table [(cols)]
+	foreignKeyRegex = regexp.MustCompile(`(?i)^(?:CONSTRAINT\s+` + identPattern + `\s+)?FOREIGN\s+KEY\s*(?:` + identPattern + `\s*)?\(([^)]+)\)\s*REFERENCES\s+` + identPattern + `\s*(?:\(([^)]+)\))?`)
+	// Inline column constraint: REFERENCES table [(col)]
+	referencesRegex = regexp.MustCompile(`(?i)\bREFERENCES\s+` + identPattern + `\s*(?:\(([^)]+)\))?`)
+)
+
+// ForeignKey is a FOREIGN KEY constraint or an inline REFERENCES clause
+type ForeignKey struct {
+	Name       string   // Constraint name, if given
+	Columns    []string // Referencing columns
+	RefTable   string   // Referenced table
+	RefColumns []string // Referenced columns (empty means the primary key)
+}
+
+// Relationship is a single-column foreign key resolved against the parsed tables.
+// Seen from Table it is a belongs-to, seen from RefTable a has-many.
+type Relationship struct {
+	Table     string // Referencing table
+	Column    string // Foreign key column in Table
+	RefTable  string // Referenced table
+	RefColumn string // Referenced column in RefTable
+}
+
+// ManyToMany links two tables through a join table holding a foreign key to each
+type ManyToMany struct {
+	JoinTable string
+	Left      Relationship // Join table -> first table
+	Right     Relationship // Join table -> second table
+}
+
+// RelationshipGraph holds the relationships between a set of tables
+type RelationshipGraph struct {
+	Relationships []Relationship
+	ManyToMany    []ManyToMany
+}
+
+// parseForeignKey parses a table-level FOREIGN KEY constraint
+func parseForeignKey(line string) (ForeignKey, bool) {
+	matches := foreignKeyRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return ForeignKey{}, false
+	}
+	return ForeignKey{
+		Name:       matches[1],
+		Columns:    splitIdentList(matches[3]),
+		RefTable:   matches[4],
+		RefColumns: splitIdentList(matches[5]),
+	}, true
+}
+
+// parseInlineReference parses a REFERENCES clause in the definition of column
+func parseInlineReference(column, definition string) (ForeignKey, bool) {
+	matches := referencesRegex.FindStringSubmatch(removeCommentsAndDefaults(definition))
+	if matches == nil {
+		return ForeignKey{}, false
+	}
+	return ForeignKey{
+		Columns:    []string{column},
+		RefTable:   matches[1],
+		RefColumns: splitIdentList(matches[2]),
+	}, true
+}
+
+// splitIdentList splits a comma-separated list of possibly quoted identifiers
+func splitIdentList(list string) []string {
+	var idents []string
+	for _, item := range strings.Split(list, ",") {
+		if matches := identRegex.FindStringSubmatch(strings.TrimSpace(item)); matches != nil {
+			idents = append(idents, matches[1])
+		}
+	}
+	return idents
+}
+
+// BuildRelationshipGraph resolves the foreign keys of defs into relationships.
+// Only single-column foreign keys to tables in defs are included. A referenced
+// column list that is omitted resolves to the "id" column.
+// Tables holding exactly two NOT NULL foreign keys to two different tables, and
+// otherwise only an id or timestamp columns, are detected as many-to-many join tables.
+func BuildRelationshipGraph(defs []StructDef) RelationshipGraph {
+	var graph RelationshipGraph
+
+	for _, def := range defs {
+		var rels []Relationship
+		for _, fk := range def.ForeignKeys {
+			if rel, ok := resolveForeignKey(defs, def, fk); ok {
+				rels = append(rels, rel)
+			}
+		}
+		graph.Relationships = append(graph.Relationships, rels...)
+
+		if isJoinTable(def, rels) {
+			graph.ManyToMany = append(graph.ManyToMany, ManyToMany{
+				JoinTable: def.TableName,
+				Left:      rels[0],
+				Right:     rels[1],
+			})
+		}
+	}
+
+	return graph
+}
+
+// resolveForeignKey turns a single-column foreign key of def into a relationship
+func resolveForeignKey(defs []StructDef, def StructDef, fk ForeignKey) (Relationship, bool) {
+	if len(fk.Columns) != 1 || len(fk.RefColumns) > 1 {
+		return Relationship{}, false
+	}
+
+	ref := findTable(defs, fk.RefTable)
+	if ref == nil {
+		return Relationship{}, false
+	}
+	// Without a column list, the foreign key references the primary key
+	refColumn := "id"
+	switch pk := primaryKeyColumns(*ref); {
+	case len(fk.RefColumns) == 1:
+		refColumn = fk.RefColumns[0]
+	case len(pk) == 1:
+		refColumn = pk[0]
+	case len(pk) > 1:
+		return Relationship{}, false
+	}
+
+	i := fieldIndex(def.Fields, fk.Columns[0])
+	j := fieldIndex(ref.Fields, refColumn)
+	if i == -1 || j == -1 {
+		return Relationship{}, false
+	}
+
+	return Relationship{
+		Table:     def.TableName,
+		Column:    def.Fields[i].ColumnName,
+		RefTable:  ref.TableName,
+		RefColumn: ref.Fields[j].ColumnName,
+	}, true
+}
+
+// isJoinTable reports whether def only links two other tables
+func isJoinTable(def StructDef, rels []Relationship) bool {
+	if len(rels) != 2 || strings.EqualFold(rels[0].RefTable, rels[1].RefTable) {
+		return false
+	}
+	for _, rel := range rels {
+		if strings.EqualFold(rel.RefTable, def.TableName) {
+			return false
+		}
+	}
+
+	for _, field := range def.Fields {
+		switch {
+		case strings.EqualFold(field.ColumnName, rels[0].Column), strings.EqualFold(field.ColumnName, rels[1].Column):
+			if field.Nullable {
+				return false
+			}
+		case strings.EqualFold(field.ColumnName, "id"):
+		case field.SQLType == "DATETIME" || strings.HasPrefix(field.SQLType, "TIMESTAMP"):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// findTable returns the table named name (case-insensitive), or nil
+func findTable(defs []StructDef, name string) *StructDef {
+	for i := range defs {
+		if strings.EqualFold(defs[i].TableName, name) {
+			return &defs[i]
+		}
+	}
+	return nil
+}
+
+// fieldName returns the Go field name of a column of def
+func fieldName(def *StructDef, column string) string {
+	return def.Fields[fieldIndex(def.Fields, column)].Name
+}
+
+// applyAssociations returns a copy of defs with GORM-style association fields
+// appended for their relationships: belongs-to (User *User next to UserID),
+// has-many (Orders []Order) on the referenced side, and many-to-many through
+// detected join tables. Associations whose name is already taken are skipped.
+func applyAssociations(defs []StructDef) []StructDef {
+	result := make([]StructDef, len(defs))
+	for i, def := range defs {
+		def.Fields = append([]FieldDef(nil), def.Fields...)
+		result[i] = def
+	}
+
+	graph := BuildRelationshipGraph(defs)
+
+	joinTables := make(map[string]bool)
+	for _, m2m := range graph.ManyToMany {
+		joinTables[strings.ToLower(m2m.JoinTable)] = true
+	}
+
+	// Foreign keys from one table to the same parent need distinct has-many names
+	parentCount := make(map[string]int)
+	for _, rel := range graph.Relationships {
+		parentCount[strings.ToLower(rel.Table+"\x00"+rel.RefTable)]++
+	}
+
+	for _, rel := range graph.Relationships {
+		child, parent := findTable(result, rel.Table), findTable(result, rel.RefTable)
+		foreignKey := fieldName(child, rel.Column)
+		references := fieldName(parent, rel.RefColumn)
+		tag := fmt.Sprintf("foreignKey:%s;references:%s", foreignKey, references)
+
+		belongsTo := belongsToName(foreignKey, parent.Name)
+		addAssociation(child, belongsTo, "*"+parent.Name, tag)
+
+		if joinTables[strings.ToLower(rel.Table)] {
+			continue
+		}
+		hasMany := pluralize(child.Name)
+		if parentCount[strings.ToLower(rel.Table+"\x00"+rel.RefTable)] > 1 {
+			hasMany = belongsTo + hasMany
+		}
+		addAssociation(parent, hasMany, "[]"+child.Name, tag)
+	}
+
+	for _, m2m := range graph.ManyToMany {
+		left, right := findTable(result, m2m.Left.RefTable), findTable(result, m2m.Right.RefTable)
+		join := findTable(result, m2m.JoinTable)
+		leftTag := fmt.Sprintf("many2many:%s;foreignKey:%s;joinForeignKey:%s;references:%s;joinReferences:%s",
+			m2m.JoinTable, fieldName(left, m2m.Left.RefColumn), fieldName(join, m2m.Left.Column),
+			fieldName(right, m2m.Right.RefColumn), fieldName(join, m2m.Right.Column))
+		rightTag := fmt.Sprintf("many2many:%s;foreignKey:%s;joinForeignKey:%s;references:%s;joinReferences:%s",
+			m2m.JoinTable, fieldName(right, m2m.Right.RefColumn), fieldName(join, m2m.Right.Column),
+			fieldName(left, m2m.Left.RefColumn), fieldName(join, m2m.Left.Column))
+
+		addAssociation(left, pluralize(right.Name), "[]"+right.Name, leftTag)
+		addAssociation(right, pluralize(left.Name), "[]"+left.Name, rightTag)
+	}
+
+	return result
+}
+
+// belongsToName derives the association name from the foreign key field (UserID -> User),
+// falling back to the referenced struct name
+func belongsToName(foreignKey, parentName string) string {
+	for _, suffix := range []string{"ID", "Id"} {
+		if name := strings.TrimSuffix(foreignKey, suffix); name != foreignKey && name != "" {
+			return name
+		}
+	}
+	return parentName
+}
+
+// addAssociation appends an association field to def unless the name is taken
+func addAssociation(def *StructDef, name, goType, tag string) {
+	for _, field := range def.Fields {
+		if field.Name == name {
+			return
+		}
+	}
+	def.Fields = append(def.Fields, FieldDef{Name: name, Type: goType, Association: tag})
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const relationsSQL = `
+CREATE TABLE users (
+	id INT NOT NULL PRIMARY KEY,
+	name VARCHAR(100) NOT NULL
+);
+CREATE TABLE orders (
+	id INT NOT NULL PRIMARY KEY,
+	user_id INT NOT NULL,
+	total DECIMAL(10,2) NOT NULL,
+	CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE TABLE posts (
+	id INT NOT NULL PRIMARY KEY,
+	author_id INT NOT NULL REFERENCES users,
+	editor_id INT REFERENCES users(id)
+);
+CREATE TABLE tags (
+	id INT NOT NULL PRIMARY KEY,
+	label VARCHAR(50) NOT NULL
+);
+CREATE TABLE post_tags (
+	post_id INT NOT NULL,
+	tag_id INT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (post_id, tag_id),
+	FOREIGN KEY idx_post (post_id) REFERENCES posts (id),
+	FOREIGN KEY (tag_id) REFERENCES tags (id)
+);`
+
+// TestParseSQL_ForeignKeys tests FOREIGN KEY constraints and inline REFERENCES clauses
+func TestParseSQL_ForeignKeys(t *testing.T) {
+	structs, err := ParseSQL(relationsSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	orders := structs[1]
+	if len(orders.ForeignKeys) != 1 {
+		t.Fatalf("Expected 1 foreign key on orders, got %d", len(orders.ForeignKeys))
+	}
+	fk := orders.ForeignKeys[0]
+	if fk.Name != "fk_orders_user" || fk.RefTable != "users" ||
+		strings.Join(fk.Columns, ",") != "user_id" || strings.Join(fk.RefColumns, ",") != "id" {
+		t.Errorf("Unexpected foreign key: %+v", fk)
+	}
+
+	posts := structs[2]
+	if len(posts.ForeignKeys) != 2 {
+		t.Fatalf("Expected 2 inline references on posts, got %d", len(posts.ForeignKeys))
+	}
+	if posts.ForeignKeys[0].Columns[0] != "author_id" || len(posts.ForeignKeys[0].RefColumns) != 0 {
+		t.Errorf("Unexpected inline reference: %+v", posts.ForeignKeys[0])
+	}
+	if len(posts.Fields) != 3 {
+		t.Errorf("Expected 3 columns on posts, got %d", len(posts.Fields))
+	}
+
+	postTags := structs[4]
+	if len(postTags.ForeignKeys) != 2 || postTags.ForeignKeys[0].RefTable != "posts" {
+		t.Errorf("Unexpected foreign keys on post_tags: %+v", postTags.ForeignKeys)
+	}
+}
+
+// TestBuildRelationshipGraph tests relationship resolution and join table detection
+func TestBuildRelationshipGraph(t *testing.T) {
+	structs, err := ParseSQL(relationsSQL + `
+		CREATE TABLE audits (id INT NOT NULL, user_id INT, FOREIGN KEY (user_id) REFERENCES missing (id));`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	graph := BuildRelationshipGraph(structs)
+
+	// orders.user_id, posts.author_id, posts.editor_id, post_tags.post_id, post_tags.tag_id
+	if len(graph.Relationships) != 5 {
+		t.Fatalf("Expected 5 relationships, got %d: %+v", len(graph.Relationships), graph.Relationships)
+	}
+	expected := Relationship{Table: "posts", Column: "author_id", RefTable: "users", RefColumn: "id"}
+	if graph.Relationships[1] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, graph.Relationships[1])
+	}
+
+	if len(graph.ManyToMany) != 1 {
+		t.Fatalf("Expected 1 many-to-many relationship, got %d", len(graph.ManyToMany))
+	}
+	m2m := graph.ManyToMany[0]
+	if m2m.JoinTable != "post_tags" || m2m.Left.RefTable != "posts" || m2m.Right.RefTable != "tags" {
+		t.Errorf("Unexpected many-to-many relationship: %+v", m2m)
+	}
+}
+
+// TestBuildRelationshipGraph_PrimaryKeyReference tests references without a column list to a key not named id
+func TestBuildRelationshipGraph_PrimaryKeyReference(t *testing.T) {
+	structs, err := ParseSQL(`
+		CREATE TABLE users (user_id INT NOT NULL PRIMARY KEY, name VARCHAR(100));
+		CREATE TABLE posts (id INT NOT NULL PRIMARY KEY, author_id INT NOT NULL REFERENCES users);
+		CREATE TABLE memberships (a INT NOT NULL, b INT NOT NULL, PRIMARY KEY (a, b));
+		CREATE TABLE notes (id INT NOT NULL PRIMARY KEY, membership_a INT REFERENCES memberships);`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	graph := BuildRelationshipGraph(structs)
+	expected := []Relationship{{Table: "posts", Column: "author_id", RefTable: "users", RefColumn: "user_id"}}
+	if len(graph.Relationships) != 1 || graph.Relationships[0] != expected[0] {
+		t.Errorf("Expected %+v, got %+v", expected, graph.Relationships)
+	}
+
+	code := GenerateGoCode(structs, Config{AddAssociations: true, AddGormTag: true})
+	for _, exp := range []string{"Author   *Users", "Posts  []Posts"} {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Expected code to contain %q, got:\n%s", exp, code)
+		}
+	}
+}
+
+// TestGenerateGoCode_Associations tests belongs-to, has-many and many-to-many fields
+func TestGenerateGoCode_Associations(t *testing.T) {
+	structs, err := ParseSQL(relationsSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	structs = ApplyNamingRules(structs, NamingRules{Initialisms: true, Singular: true})
+
+	code := GenerateGoCode(structs, Config{AddAssociations: true, AddGormTag: true, AddJSONTag: true})
+
+	expected := []string{
+		// belongs-to
+		"User   *User   `json:\"user,omitempty\" gorm:\"foreignKey:UserID;references:ID\"`",
+		"Author   *User",
+		"Editor   *User",
+		// has-many, with names telling apart two keys to the same table
+		"Orders      []Order `json:\"orders,omitempty\" gorm:\"foreignKey:UserID;references:ID\"`",
+		"AuthorPosts []Post  `json:\"author_posts,omitempty\" gorm:\"foreignKey:AuthorID;references:ID\"`",
+		"EditorPosts []Post",
+		// many-to-many through post_tags
+		"Tags     []Tag `json:\"tags,omitempty\" gorm:\"many2many:post_tags;foreignKey:ID;joinForeignKey:PostID;references:ID;joinReferences:TagID\"`",
+		"Posts []Post `json:\"posts,omitempty\" gorm:\"many2many:post_tags;foreignKey:ID;joinForeignKey:TagID;references:ID;joinReferences:PostID\"`",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Expected code to contain %q, got:\n%s", exp, code)
+		}
+	}
+
+	// Join tables don't get has-many fields on the linked tables
+	if strings.Contains(code, "[]PostTag") {
+		t.Errorf("Expected no has-many fields for the join table, got:\n%s", code)
+	}
+
+	// Without the option the structs only hold columns
+	if plain := GenerateGoCode(structs, Config{}); strings.Contains(plain, "*User") {
+		t.Errorf("Expected no association fields by default, got:\n%s", plain)
+	}
+}
+
+// TestSchema_ForeignKeyChanges tests foreign keys added, renamed and dropped by later statements
+func TestSchema_ForeignKeyChanges(t *testing.T) {
+	schema := NewSchema(ParseOptions{})
+	err := schema.ApplySQL(`
+		CREATE TABLE users (id INT NOT NULL);
+		CREATE TABLE orders (id INT NOT NULL, user_id INT NOT NULL, note TEXT);
+		ALTER TABLE orders ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id);
+		ALTER TABLE orders ADD COLUMN reviewer_id INT REFERENCES users (id);
+		RENAME TABLE users TO accounts;
+		ALTER TABLE orders RENAME COLUMN user_id TO account_id;
+		ALTER TABLE orders DROP FOREIGN KEY fk_user;
+		ALTER TABLE orders ADD CONSTRAINT fk_account FOREIGN KEY (account_id) REFERENCES accounts (id);
+		ALTER TABLE orders DROP COLUMN reviewer_id;
+	`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	orders := schema.Tables()[1]
+	if len(orders.ForeignKeys) != 1 {
+		t.Fatalf("Expected 1 foreign key, got %+v", orders.ForeignKeys)
+	}
+	fk := orders.ForeignKeys[0]
+	if fk.Name != "fk_account" || fk.Columns[0] != "account_id" || fk.RefTable != "accounts" {
+		t.Errorf("Unexpected foreign key: %+v", fk)
+	}
+	if len(schema.Warnings()) != 0 {
+		t.Errorf("Expected no warnings, got: %v", schema.Warnings())
+	}
+}
+
+// TestSchema_ForeignKeyRenames tests that renaming a referenced table and column updates references
+func TestSchema_ForeignKeyRenames(t *testing.T) {
+	schema := NewSchema(ParseOptions{Dialect: DialectPostgres})
+	err := schema.ApplySQL(`
+		CREATE TABLE users (id INTEGER NOT NULL);
+		CREATE TABLE orders (id INTEGER NOT NULL, user_id INTEGER REFERENCES users (id));
+		ALTER TABLE users RENAME TO accounts;
+		ALTER TABLE accounts RENAME COLUMN id TO account_id;
+	`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	fk := schema.Tables()[1].ForeignKeys[0]
+	if fk.RefTable != "accounts" || fk.RefColumns[0] != "account_id" {
+		t.Errorf("Expected reference to accounts(account_id), got %+v", fk)
+	}
+}
+
+// TestSchema_ModifyReferences tests that MODIFY with a REFERENCES clause replaces the column's foreign key
+func TestSchema_ModifyReferences(t *testing.T) {
+	schema := NewSchema(ParseOptions{})
+	err := schema.ApplySQL(`
+		CREATE TABLE users (id INT NOT NULL PRIMARY KEY);
+		CREATE TABLE posts (id INT NOT NULL PRIMARY KEY, user_id INT REFERENCES users(id), note TEXT);
+		ALTER TABLE posts MODIFY user_id INT NOT NULL REFERENCES users(id);
+		ALTER TABLE posts MODIFY note VARCHAR(100);
+	`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	structs := schema.Tables()
+	if len(structs[1].ForeignKeys) != 1 {
+		t.Fatalf("Expected 1 foreign key, got %+v", structs[1].ForeignKeys)
+	}
+
+	code := GenerateGoCode(structs, Config{AddAssociations: true})
+	if !strings.Contains(code, "Posts []Posts") || strings.Contains(code, "UserPosts") {
+		t.Errorf("Expected a single has-many field, got:\n%s", code)
+	}
+}
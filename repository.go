@@ -0,0 +1,301 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// repositoryFileName is the shared file holding the DBTX interface in multi-file output
+const repositoryFileName = "db.go"
+
+// repositoryImports are the packages used by the generated repository functions
+var repositoryImports = []string{"context"}
+
+// dbtxImports are the packages used by the DBTX interface
+var dbtxImports = []string{"context", "database/sql"}
+
+// repositoryLocals are the variable names used inside the generated functions,
+// which key parameters must not shadow
+var repositoryLocals = map[string]bool{"ctx": true, "db": true, "row": true, "err": true, "query": true}
+
+// generateDBTX generates the database handle interface accepted by the repository functions
+func generateDBTX() string {
+	return `// DBTX is the database handle used by the repository functions: *sql.DB, *sql.Tx or *sql.Conn
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+`
+}
+
+// generateRepository generates the Insert, Get, Update, Delete and List functions of a
+// table for database/sql. Get, Update and Delete need a primary key and are omitted
+// for tables without one.
+func generateRepository(def StructDef, config Config) string {
+	dialect := repositoryDialect(config)
+	columns := repositoryColumns(def)
+	var keys []FieldDef
+	for _, field := range columns {
+		if field.PrimaryKey {
+			keys = append(keys, field)
+		}
+	}
+
+	parts := []string{generateInsert(def, columns, dialect)}
+	if len(keys) > 0 {
+		parts = append(parts, generateGet(def, columns, keys, dialect))
+		if update := generateUpdate(def, columns, keys, dialect); update != "" {
+			parts = append(parts, update)
+		}
+		parts = append(parts, generateDelete(def, keys, dialect))
+	}
+	parts = append(parts, generateList(def, columns, keys, dialect))
+
+	return strings.Join(parts, "\n")
+}
+
+// repositoryDialect returns the dialect of the generated queries, defaulting to MySQL
+func repositoryDialect(config Config) Dialect {
+	if config.Dialect == "" {
+		return DialectMySQL
+	}
+	return config.Dialect
+}
+
+// repositoryColumns returns the fields backed by a column, in column order
+func repositoryColumns(def StructDef) []FieldDef {
+	var columns []FieldDef
+	for _, field := range def.Fields {
+		if field.Association == "" && field.ColumnName != "" {
+			columns = append(columns, field)
+		}
+	}
+	return columns
+}
+
+// generateInsert generates the Insert function, leaving the columns the database sets
+// to it. An auto-increment key is read back with LastInsertId, or with RETURNING on PostgreSQL.
+func generateInsert(def StructDef, columns []FieldDef, dialect Dialect) string {
+	var inserted []FieldDef
+	var generated *FieldDef
+	for i, field := range columns {
+		if field.AutoIncrement && generated == nil {
+			generated = &columns[i]
+		}
+		if isInsertedColumn(field) {
+			inserted = append(inserted, field)
+		}
+	}
+
+	table := quoteIdent(def.TableName, dialect)
+	var query string
+	switch {
+	case len(inserted) > 0:
+		placeholders := make([]string, len(inserted))
+		for i := range inserted {
+			placeholders[i] = placeholder(dialect, i+1)
+		}
+		query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, quoteIdents(columnNames(inserted), dialect), strings.Join(placeholders, ", "))
+	case dialect == DialectMySQL:
+		query = fmt.Sprintf("INSERT INTO %s () VALUES ()", table)
+	default:
+		query = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", table)
+	}
+	args := fieldArgs("row", inserted)
+
+	var output strings.Builder
+	name := "Insert" + def.Name
+	switch {
+	case generated == nil:
+		output.WriteString(fmt.Sprintf("// %s inserts row into %s\n", name, def.TableName))
+		output.WriteString(fmt.Sprintf("func %s(ctx context.Context, db DBTX, row *%s) error {\n", name, def.Name))
+		output.WriteString(fmt.Sprintf("\t_, err := db.ExecContext(ctx, %q%s)\n", query, args))
+		output.WriteString("\treturn err\n")
+	case dialect == DialectPostgres:
+		query += " RETURNING " + quoteIdent(generated.ColumnName, dialect)
+		output.WriteString(fmt.Sprintf("// %s inserts row into %s and sets its generated %s\n", name, def.TableName, generated.Name))
+		output.WriteString(fmt.Sprintf("func %s(ctx context.Context, db DBTX, row *%s) error {\n", name, def.Name))
+		output.WriteString(fmt.Sprintf("\treturn db.QueryRowContext(ctx, %q%s).Scan(&row.%s)\n", query, args, generated.Name))
+	default:
+		output.WriteString(fmt.Sprintf("// %s inserts row into %s and sets its generated %s\n", name, def.TableName, generated.Name))
+		output.WriteString(fmt.Sprintf("func %s(ctx context.Context, db DBTX, row *%s) error {\n", name, def.Name))
+		output.WriteString(fmt.Sprintf("\tres, err := db.ExecContext(ctx, %q%s)\n", query, args))
+		output.WriteString("\tif err != nil {\n\t\treturn err\n\t}\n")
+		output.WriteString("\tid, err := res.LastInsertId()\n")
+		output.WriteString("\tif err != nil {\n\t\treturn err\n\t}\n")
+		if elem, ok := strings.CutPrefix(generated.Type, "*"); ok {
+			output.WriteString(fmt.Sprintf("\tkey := %s(id)\n", elem))
+			output.WriteString(fmt.Sprintf("\trow.%s = &key\n", generated.Name))
+		} else {
+			output.WriteString(fmt.Sprintf("\trow.%s = %s(id)\n", generated.Name, generated.Type))
+		}
+		output.WriteString("\treturn nil\n")
+	}
+	output.WriteString("}\n")
+	return output.String()
+}
+
+// generateGet generates the function returning the row with a given primary key
+func generateGet(def StructDef, columns, keys []FieldDef, dialect Dialect) string {
+	where, params, args := keyCondition(keys, dialect, 1)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s", quoteIdents(columnNames(columns), dialect), quoteIdent(def.TableName, dialect), where)
+
+	var output strings.Builder
+	name := "Get" + def.Name
+	output.WriteString(fmt.Sprintf("// %s returns the %s row with the given primary key\n", name, def.TableName))
+	output.WriteString(fmt.Sprintf("func %s(ctx context.Context, db DBTX, %s) (*%s, error) {\n", name, params, def.Name))
+	output.WriteString(fmt.Sprintf("\tvar row %s\n", def.Name))
+	output.WriteString(fmt.Sprintf("\terr := db.QueryRowContext(ctx, %q%s).Scan(%s)\n", query, args, scanTargets(columns)))
+	output.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	output.WriteString("\treturn &row, nil\n")
+	output.WriteString("}\n")
+	return output.String()
+}
+
+// generateUpdate generates the function writing the non-key columns of a row that the
+// database doesn't set. It returns "" when there are none.
+func generateUpdate(def StructDef, columns, keys []FieldDef, dialect Dialect) string {
+	var assignments []string
+	var updated []FieldDef
+	for _, field := range columns {
+		if !isUpdatedColumn(field) {
+			continue
+		}
+		updated = append(updated, field)
+		assignments = append(assignments, fmt.Sprintf("%s = %s", quoteIdent(field.ColumnName, dialect), placeholder(dialect, len(updated))))
+	}
+	if len(updated) == 0 {
+		return ""
+	}
+
+	where, _, _ := keyCondition(keys, dialect, len(updated)+1)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", quoteIdent(def.TableName, dialect), strings.Join(assignments, ", "), where)
+
+	var output strings.Builder
+	name := "Update" + def.Name
+	output.WriteString(fmt.Sprintf("// %s updates the %s row with the primary key of row\n", name, def.TableName))
+	output.WriteString(fmt.Sprintf("func %s(ctx context.Context, db DBTX, row *%s) error {\n", name, def.Name))
+	output.WriteString(fmt.Sprintf("\t_, err := db.ExecContext(ctx, %q%s%s)\n", query, fieldArgs("row", updated), fieldArgs("row", keys)))
+	output.WriteString("\treturn err\n")
+	output.WriteString("}\n")
+	return output.String()
+}
+
+// generateDelete generates the function deleting the row with a given primary key
+func generateDelete(def StructDef, keys []FieldDef, dialect Dialect) string {
+	where, params, args := keyCondition(keys, dialect, 1)
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s", quoteIdent(def.TableName, dialect), where)
+
+	var output strings.Builder
+	name := "Delete" + def.Name
+	output.WriteString(fmt.Sprintf("// %s deletes the %s row with the given primary key\n", name, def.TableName))
+	output.WriteString(fmt.Sprintf("func %s(ctx context.Context, db DBTX, %s) error {\n", name, params))
+	output.WriteString(fmt.Sprintf("\t_, err := db.ExecContext(ctx, %q%s)\n", query, args))
+	output.WriteString("\treturn err\n")
+	output.WriteString("}\n")
+	return output.String()
+}
+
+// generateList generates the function returning all rows, ordered by primary key
+func generateList(def StructDef, columns, keys []FieldDef, dialect Dialect) string {
+	query := fmt.Sprintf("SELECT %s FROM %s", quoteIdents(columnNames(columns), dialect), quoteIdent(def.TableName, dialect))
+	if len(keys) > 0 {
+		query += " ORDER BY " + quoteIdents(columnNames(keys), dialect)
+	}
+
+	var output strings.Builder
+	name := "List" + pluralize(def.Name)
+	output.WriteString(fmt.Sprintf("// %s returns all %s rows\n", name, def.TableName))
+	output.WriteString(fmt.Sprintf("func %s(ctx context.Context, db DBTX) ([]%s, error) {\n", name, def.Name))
+	output.WriteString(fmt.Sprintf("\trows, err := db.QueryContext(ctx, %q)\n", query))
+	output.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	output.WriteString("\tdefer rows.Close()\n\n")
+	output.WriteString(fmt.Sprintf("\tvar items []%s\n", def.Name))
+	output.WriteString("\tfor rows.Next() {\n")
+	output.WriteString(fmt.Sprintf("\t\tvar row %s\n", def.Name))
+	output.WriteString(fmt.Sprintf("\t\tif err := rows.Scan(%s); err != nil {\n", scanTargets(columns)))
+	output.WriteString("\t\t\treturn nil, err\n\t\t}\n")
+	output.WriteString("\t\titems = append(items, row)\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\treturn items, rows.Err()\n")
+	output.WriteString("}\n")
+	return output.String()
+}
+
+// keyCondition returns the WHERE condition on the primary key starting at placeholder
+// number first, the key parameters of the function and the matching call arguments
+func keyCondition(keys []FieldDef, dialect Dialect, first int) (where, params, args string) {
+	conditions := make([]string, len(keys))
+	paramList := make([]string, len(keys))
+	var argList strings.Builder
+	for i, key := range keys {
+		conditions[i] = fmt.Sprintf("%s = %s", quoteIdent(key.ColumnName, dialect), placeholder(dialect, first+i))
+		param := paramName(key.Name)
+		paramList[i] = param + " " + key.Type
+		argList.WriteString(", " + param)
+	}
+	return strings.Join(conditions, " AND "), strings.Join(paramList, ", "), argList.String()
+}
+
+// placeholder returns the n-th (1-based) query parameter placeholder of dialect
+func placeholder(dialect Dialect, n int) string {
+	if dialect == DialectPostgres {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+// columnNames returns the column names of fields
+func columnNames(fields []FieldDef) []string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.ColumnName
+	}
+	return names
+}
+
+// fieldArgs returns the call arguments passing the fields of a variable, each preceded by ", "
+func fieldArgs(variable string, fields []FieldDef) string {
+	var args strings.Builder
+	for _, field := range fields {
+		args.WriteString(", " + variable + "." + field.Name)
+	}
+	return args.String()
+}
+
+// scanTargets returns the Scan arguments for the fields of row, in column order
+func scanTargets(fields []FieldDef) string {
+	targets := make([]string, len(fields))
+	for i, field := range fields {
+		targets[i] = "&row." + field.Name
+	}
+	return strings.Join(targets, ", ")
+}
+
+// paramName converts a field name to a parameter name: ID -> id, UserID -> userID.
+// Names that are Go keywords or clash with local variables get a Key suffix.
+func paramName(field string) string {
+	runes := []rune(field)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	switch {
+	case upper == len(runes):
+		// ID -> id
+	case upper > 1:
+		// URLPath -> urlPath
+		upper--
+	default:
+		upper = 1
+	}
+	name := strings.ToLower(string(runes[:upper])) + string(runes[upper:])
+
+	if token.IsKeyword(name) || repositoryLocals[name] {
+		name += "Key"
+	}
+	return name
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+// repositorySQL has an auto-increment key, a composite key with a keyword column and no key
+const repositorySQL = `
+CREATE TABLE users (
+	id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	name VARCHAR(100) NOT NULL,
+	email VARCHAR(255)
+);
+CREATE TABLE user_roles (
+	user_id INT NOT NULL,
+	type VARCHAR(20) NOT NULL,
+	PRIMARY KEY (user_id, type)
+);
+CREATE TABLE events (payload TEXT);`
+
+// typeCheck fails the test if code is not gofmt-formatted, valid Go.
+// Imports outside the standard library are type-checked against stubs, by import path.
+func typeCheck(t *testing.T, code string, stubs map[string]string) {
+	t.Helper()
+	formatted, err := format.Source([]byte(code))
+	if err != nil {
+		t.Fatalf("Expected valid Go, got: %v\n%s", err, code)
+	}
+	if string(formatted) != code {
+		t.Errorf("Expected gofmt-formatted code, got:\n%s", code)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "models.go", code, 0)
+	if err != nil {
+		t.Fatalf("Expected no parse error, got: %v", err)
+	}
+	conf := types.Config{Importer: stubImporter{fset, importer.ForCompiler(fset, "source", nil).(types.ImporterFrom), stubs}}
+	if _, err := conf.Check("models", fset, []*ast.File{file}, nil); err != nil {
+		t.Errorf("Expected the code to type-check, got: %v\n%s", err, code)
+	}
+}
+
+// stubImporter imports packages from stub sources, and the standard library from source
+type stubImporter struct {
+	fset  *token.FileSet
+	std   types.ImporterFrom
+	stubs map[string]string
+}
+
+func (imp stubImporter) Import(path string) (*types.Package, error) {
+	stub, ok := imp.stubs[path]
+	if !ok {
+		return imp.std.Import(path)
+	}
+	file, err := parser.ParseFile(imp.fset, path+".go", stub, 0)
+	if err != nil {
+		return nil, err
+	}
+	conf := types.Config{Importer: imp}
+	return conf.Check(path, imp.fset, []*ast.File{file}, nil)
+}
+
+// TestGenerateRepository_MySQL tests the functions generated for an auto-increment key
+func TestGenerateRepository_MySQL(t *testing.T) {
+	structs, err := ParseSQL(repositorySQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := `// InsertUsers inserts row into users and sets its generated Id
+func InsertUsers(ctx context.Context, db DBTX, row *Users) error {
+	res, err := db.ExecContext(ctx, "INSERT INTO users (name, email) VALUES (?, ?)", row.Name, row.Email)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	row.Id = int(id)
+	return nil
+}
+
+// GetUsers returns the users row with the given primary key
+func GetUsers(ctx context.Context, db DBTX, id int) (*Users, error) {
+	var row Users
+	err := db.QueryRowContext(ctx, "SELECT id, name, email FROM users WHERE id = ?", id).Scan(&row.Id, &row.Name, &row.Email)
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// UpdateUsers updates the users row with the primary key of row
+func UpdateUsers(ctx context.Context, db DBTX, row *Users) error {
+	_, err := db.ExecContext(ctx, "UPDATE users SET name = ?, email = ? WHERE id = ?", row.Name, row.Email, row.Id)
+	return err
+}
+
+// DeleteUsers deletes the users row with the given primary key
+func DeleteUsers(ctx context.Context, db DBTX, id int) error {
+	_, err := db.ExecContext(ctx, "DELETE FROM users WHERE id = ?", id)
+	return err
+}
+
+// ListUsers returns all users rows
+func ListUsers(ctx context.Context, db DBTX) ([]Users, error) {
+	rows, err := db.QueryContext(ctx, "SELECT id, name, email FROM users ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []Users
+	for rows.Next() {
+		var row Users
+		if err := rows.Scan(&row.Id, &row.Name, &row.Email); err != nil {
+			return nil, err
+		}
+		items = append(items, row)
+	}
+	return items, rows.Err()
+}
+`
+	if code := generateRepository(structs[0], Config{}); code != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, code)
+	}
+
+	code := GenerateGoCode(structs, Config{AddRepository: true})
+	if !strings.Contains(code, "import (\n\t\"context\"\n\t\"database/sql\"\n)") {
+		t.Errorf("Expected context and database/sql imports, got:\n%s", code)
+	}
+	typeCheck(t, code, nil)
+}
+
+// TestGenerateRepository_Postgres tests numbered placeholders and RETURNING
+func TestGenerateRepository_Postgres(t *testing.T) {
+	structs, _, err := ParseSQLWithOptions(strings.ReplaceAll(repositorySQL, "INT NOT NULL AUTO_INCREMENT", "SERIAL"), ParseOptions{Dialect: DialectPostgres})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	code := GenerateGoCode(structs, Config{AddRepository: true, Dialect: DialectPostgres})
+	expected := []string{
+		`return db.QueryRowContext(ctx, "INSERT INTO users (name, email) VALUES ($1, $2) RETURNING id", row.Name, row.Email).Scan(&row.Id)`,
+		`"UPDATE users SET name = $1, email = $2 WHERE id = $3", row.Name, row.Email, row.Id)`,
+		// Key parameters don't use keywords
+		`func GetUserRoles(ctx context.Context, db DBTX, userId int, typeKey string) (*UserRoles, error) {`,
+		`"DELETE FROM user_roles WHERE user_id = $1 AND type = $2", userId, typeKey)`,
+		`"SELECT user_id, type FROM user_roles ORDER BY user_id, type"`,
+		`"INSERT INTO events (payload) VALUES ($1)", row.Payload)`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(code, want) {
+			t.Errorf("Expected %q in:\n%s", want, code)
+		}
+	}
+
+	// Every column of user_roles is part of the key, and events has no key
+	for _, name := range []string{"UpdateUserRoles", "GetEvents", "UpdateEvents", "DeleteEvents"} {
+		if strings.Contains(code, "func "+name+"(") {
+			t.Errorf("Expected no %s, got:\n%s", name, code)
+		}
+	}
+	typeCheck(t, code, nil)
+}
+
+// TestGenerateRepository_SQLiteKeys tests nullable-declared keys and the SQLite rowid alias
+func TestGenerateRepository_SQLiteKeys(t *testing.T) {
+	structs, _, err := ParseSQLWithOptions(`
+		CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT);
+		CREATE TABLE posts (post_key INTEGER, title TEXT, PRIMARY KEY (post_key)) WITHOUT ROWID;
+		CREATE TABLE tags (name TEXT PRIMARY KEY);`, ParseOptions{Dialect: DialectSQLite})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	code := GenerateGoCode(structs, Config{AddRepository: true, Dialect: DialectSQLite})
+	expected := []string{
+		// The rowid alias is assigned by SQLite
+		`"INSERT INTO notes (body) VALUES (?)", row.Body)`,
+		"row.Id = int64(id)",
+		// Key columns are NOT NULL
+		"func GetPosts(ctx context.Context, db DBTX, postKey int64) (*Posts, error) {",
+		`"INSERT INTO posts (post_key, title) VALUES (?, ?)", row.PostKey, row.Title)`,
+		"func GetTags(ctx context.Context, db DBTX, name string) (*Tags, error) {",
+	}
+	for _, want := range expected {
+		if !strings.Contains(code, want) {
+			t.Errorf("Expected %q in:\n%s", want, code)
+		}
+	}
+	typeCheck(t, code, nil)
+}
+
+// TestGenerateGoFiles_Repository tests that the DBTX interface goes to a shared file
+func TestGenerateGoFiles_Repository(t *testing.T) {
+	structs, err := ParseSQL(repositorySQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	files := GenerateGoFiles(structs, Config{PackageName: "models", AddRepository: true, Dialect: DialectSQLite})
+	if len(files) != 4 {
+		t.Fatalf("Expected 4 files, got %v", sortedFileNames(files))
+	}
+
+	db := files[repositoryFileName]
+	if !strings.Contains(db, "type DBTX interface") || strings.Contains(db, "func ") {
+		t.Errorf("Expected db.go to hold only DBTX, got:\n%s", db)
+	}
+	users := files["users.go"]
+	if !strings.Contains(users, `import "context"`) || !strings.Contains(users, "res.LastInsertId()") {
+		t.Errorf("Unexpected users.go:\n%s", users)
+	}
+}
+
+// TestParamName tests key parameter names
+func TestParamName(t *testing.T) {
+	tests := map[string]string{
+		"ID":      "id",
+		"Id":      "id",
+		"UserID":  "userID",
+		"URLPath": "urlPath",
+		"Type":    "typeKey",
+		"Row":     "rowKey",
+	}
+	for field, expected := range tests {
+		if got := paramName(field); got != expected {
+			t.Errorf("paramName(%q): expected %q, got %q", field, expected, got)
+		}
+	}
+}
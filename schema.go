@@ -0,0 +1,491 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// identPattern matches a possibly quoted and schema-qualified identifier, capturing the bare name
+const identPattern = "(?:[`\"']?[a-zA-Z0-9_]+[`\"']?\\.)?[`\"']?([a-zA-Z0-9_]+)[`\"']?"
+
+// Pre-compiled regex patterns for DDL statements other than CREATE TABLE
+var (
+	createTableIfNotExistsRegex = regexp.MustCompile(`(?i)^CREATE\s+TABLE\s+IF\s+NOT\s+EXISTS\b`)
+	dropTableRegex              = regexp.MustCompile(`(?i)^DROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?(.+?)(?:\s+(?:CASCADE|RESTRICT))?$`)
+	renameTableRegex            = regexp.MustCompile(`(?i)^RENAME\s+TABLE\s+(.+)$`)
+	renamePairRegex             = regexp.MustCompile(`(?i)^` + identPattern + `\s+TO\s+` + identPattern + `$`)
+	alterTableRegex             = regexp.MustCompile(`(?i)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?` + identPattern + `\s+(.+)$`)
+	identRegex                  = regexp.MustCompile(`^` + identPattern + `$`)
+	commentOnColumnRegex        = regexp.MustCompile(`(?i)^COMMENT\s+ON\s+COLUMN\s+` + identPattern + `\.[` + "`" + `"']?([a-zA-Z0-9_]+)[` + "`" + `"']?\s+IS\s+(?:'((?:[^']|'')*)'|NULL)$`)
+
+	// ALTER TABLE actions (MySQL and PostgreSQL)
+	renameToRegex       = regexp.MustCompile(`(?i)^RENAME\s+(?:TO\s+|AS\s+)?` + identPattern + `$`)
+	renameColumnRegex   = regexp.MustCompile(`(?i)^RENAME\s+(?:COLUMN\s+)?` + identPattern + `\s+TO\s+` + identPattern + `$`)
+	addColumnRegex      = regexp.MustCompile(`(?i)^ADD\s+(?:COLUMN\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(.+)$`)
+	dropColumnRegex     = regexp.MustCompile(`(?i)^DROP\s+(?:COLUMN\s+)?(?:IF\s+EXISTS\s+)?` + identPattern + `(?:\s+(?:CASCADE|RESTRICT))?$`)
+	modifyColumnRegex   = regexp.MustCompile(`(?i)^MODIFY\s+(?:COLUMN\s+)?(.+)$`)
+	changeColumnRegex   = regexp.MustCompile(`(?i)^CHANGE\s+(?:COLUMN\s+)?` + identPattern + `\s+(.+)$`)
+	alterColumnRegex    = regexp.MustCompile(`(?i)^ALTER\s+(?:COLUMN\s+)?` + identPattern + `\s+(.+)$`)
+	dropPrimaryKeyRegex = regexp.MustCompile(`(?i)^DROP\s+PRIMARY\s+KEY$`)
+	dropConstraintRegex = regexp.MustCompile(`(?i)^DROP\s+(?:FOREIGN\s+KEY|CONSTRAINT)\s+(?:IF\s+EXISTS\s+)?` + identPattern + `(?:\s+(?:CASCADE|RESTRICT))?$`)
+	columnPositionRegex = regexp.MustCompile(`(?i)\s+(FIRST|AFTER\s+` + identPattern + `)$`)
+	ignoredActionRegex  = regexp.MustCompile(`(?i)^(?:DROP\s+(?:PRIMARY\s+KEY|FOREIGN\s+KEY|INDEX|KEY|CONSTRAINT|CHECK)|RENAME\s+(?:INDEX|KEY|CONSTRAINT)|ALTER\s+(?:INDEX|CONSTRAINT|CHECK)|VALIDATE\s+CONSTRAINT|OWNER\s+TO|ENGINE|AUTO_INCREMENT|ALGORITHM|LOCK|COMMENT|CONVERT\s+TO|(?:DEFAULT\s+)?(?:CHARACTER\s+SET|CHARSET|COLLATE)|ENABLE|DISABLE|SET\s+(?:SCHEMA|TABLESPACE|LOGGED|UNLOGGED|\())\b`)
+
+	// ALTER COLUMN sub-actions
+	setNotNullRegex          = regexp.MustCompile(`(?i)^SET\s+NOT\s+NULL$`)
+	dropNotNullRegex         = regexp.MustCompile(`(?i)^DROP\s+NOT\s+NULL$`)
+	setDefaultRegex          = regexp.MustCompile(`(?i)^SET\s+DEFAULT\b`)
+	dropDefaultRegex         = regexp.MustCompile(`(?i)^DROP\s+DEFAULT$`)
+	setTypeRegex             = regexp.MustCompile(`(?i)^(?:SET\s+DATA\s+)?TYPE\s+(.+?)(?:\s+(?:COLLATE|USING)\s+.*)?$`)
+	ignoredColumnActionRegex = regexp.MustCompile(`(?i)^(?:SET\s+STATISTICS\b|SET\s+STORAGE\b|SET\s+\(|RESET\s+\(|(?:SET|DROP)\s+(?:VISIBLE|INVISIBLE)$|(?:ADD|DROP)\s+(?:GENERATED|IDENTITY)\b|DROP\s+EXPRESSION\b)`)
+)
+
+// Schema is an in-memory model of a database schema, built by replaying DDL statements
+// in order. CREATE TABLE adds a table, ALTER TABLE changes it, RENAME and DROP
+// rename and remove tables, CREATE/DROP INDEX and COMMENT ON COLUMN update their
+// indexes and comments. Other statements are ignored.
+type Schema struct {
+	opts     ParseOptions
+	tables   []StructDef
+	warnings []string
+}
+
+// NewSchema creates an empty schema for the given parse options
+func NewSchema(opts ParseOptions) *Schema {
+	return &Schema{opts: opts}
+}
+
+// Tables returns the tables in creation order
+func (s *Schema) Tables() []StructDef {
+	return s.tables
+}
+
+// Warnings returns the warnings collected while applying statements
+func (s *Schema) Warnings() []string {
+	return s.warnings
+}
+
+// ApplySQL applies every statement of a SQL script
+func (s *Schema) ApplySQL(sql string) error {
+	for _, stmt := range splitStatements(sql) {
+		if err := s.Apply(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply applies a single DDL statement to the schema.
+// Statements that reference unknown tables are skipped with a warning.
+func (s *Schema) Apply(stmt string) error {
+	stmt = normalizeWhitespace(strings.TrimSpace(stmt))
+
+	switch {
+	case tableNameRegex.MatchString(stmt):
+		return s.applyCreateTable(stmt)
+	case createIndexRegex.MatchString(stmt):
+		s.applyCreateIndex(stmt)
+	case dropIndexRegex.MatchString(stmt):
+		matches := dropIndexRegex.FindStringSubmatch(stmt)
+		s.applyDropIndex(matches[1], matches[2])
+	case dropTableRegex.MatchString(stmt):
+		s.applyDropTable(dropTableRegex.FindStringSubmatch(stmt)[1])
+	case renameTableRegex.MatchString(stmt):
+		s.applyRenameTables(renameTableRegex.FindStringSubmatch(stmt)[1])
+	case alterTableRegex.MatchString(stmt):
+		matches := alterTableRegex.FindStringSubmatch(stmt)
+		s.applyAlterTable(matches[1], matches[2])
+	case commentOnColumnRegex.MatchString(stmt):
+		matches := commentOnColumnRegex.FindStringSubmatch(stmt)
+		s.applyColumnComment(matches[1], matches[2], strings.ReplaceAll(matches[3], "''", "'"))
+	}
+
+	return nil
+}
+
+// applyColumnComment sets the comment of a column; an empty comment removes it
+func (s *Schema) applyColumnComment(table, column, comment string) {
+	i := s.tableIndex(table)
+	if i == -1 {
+		s.warn("COMMENT ON COLUMN of unknown table %s", table)
+		return
+	}
+	j := fieldIndex(s.tables[i].Fields, column)
+	if j == -1 {
+		s.warn("COMMENT ON COLUMN of unknown column %s.%s", table, column)
+		return
+	}
+	s.tables[i].Fields[j].Comment = comment
+}
+
+// applyCreateTable adds a table, replacing an existing one unless IF NOT EXISTS is given
+func (s *Schema) applyCreateTable(stmt string) error {
+	def, warnings, err := parseCreateTable(stmt, s.opts)
+	s.warnings = append(s.warnings, warnings...)
+	if err != nil {
+		return err
+	}
+
+	if i := s.tableIndex(def.TableName); i != -1 {
+		if createTableIfNotExistsRegex.MatchString(stmt) {
+			return nil
+		}
+		s.warn("table %s created twice; keeping the last definition", def.TableName)
+		s.tables[i] = def
+		return nil
+	}
+
+	s.tables = append(s.tables, def)
+	return nil
+}
+
+// applyDropTable removes the tables in a comma-separated list
+func (s *Schema) applyDropTable(list string) {
+	for _, name := range splitColumns(list) {
+		matches := identRegex.FindStringSubmatch(strings.TrimSpace(name))
+		if matches == nil {
+			s.warn("cannot parse table name in DROP TABLE: %s", name)
+			continue
+		}
+		if i := s.tableIndex(matches[1]); i != -1 {
+			s.tables = append(s.tables[:i], s.tables[i+1:]...)
+		}
+	}
+}
+
+// applyRenameTables applies a MySQL RENAME TABLE a TO b[, c TO d] list
+func (s *Schema) applyRenameTables(list string) {
+	for _, pair := range splitColumns(list) {
+		matches := renamePairRegex.FindStringSubmatch(strings.TrimSpace(pair))
+		if matches == nil {
+			s.warn("cannot parse RENAME TABLE: %s", pair)
+			continue
+		}
+		s.renameTable(matches[1], matches[2])
+	}
+}
+
+// applyAlterTable applies the comma-separated actions of an ALTER TABLE statement
+func (s *Schema) applyAlterTable(table, actions string) {
+	i := s.tableIndex(table)
+	if i == -1 {
+		s.warn("ALTER TABLE on unknown table %s", table)
+		return
+	}
+
+	for _, action := range splitColumns(actions) {
+		action = strings.TrimSpace(action)
+		if action == "" {
+			continue
+		}
+		if err := s.applyAlterAction(&s.tables[i], action); err != nil {
+			s.warn("ALTER TABLE %s: %v", table, err)
+		}
+	}
+}
+
+// applyAlterAction applies a single ALTER TABLE action to def
+func (s *Schema) applyAlterAction(def *StructDef, action string) error {
+	switch {
+	case dropPrimaryKeyRegex.MatchString(action):
+		for i := range def.Fields {
+			def.Fields[i].PrimaryKey = false
+		}
+	case dropConstraintRegex.MatchString(action):
+		// Foreign keys and UNIQUE constraints are tracked; others don't change the struct
+		name := dropConstraintRegex.FindStringSubmatch(action)[1]
+		def.ForeignKeys = removeForeignKeys(def.ForeignKeys, func(fk ForeignKey) bool {
+			return strings.EqualFold(fk.Name, name)
+		})
+		def.Indexes = removeIndexes(def.Indexes, func(index Index) bool {
+			return strings.EqualFold(index.Name, name)
+		})
+	case dropIndexActionRegex.MatchString(action):
+		s.applyDropIndex(dropIndexActionRegex.FindStringSubmatch(action)[1], def.TableName)
+	case renameIndexActionRegex.MatchString(action):
+		matches := renameIndexActionRegex.FindStringSubmatch(action)
+		for k := range def.Indexes {
+			if strings.EqualFold(def.Indexes[k].Name, matches[1]) {
+				def.Indexes[k].Name = matches[2]
+			}
+		}
+	case ignoredActionRegex.MatchString(action):
+		// Keys, indexes, constraints and table options don't change the struct
+	case renameColumnRegex.MatchString(action):
+		matches := renameColumnRegex.FindStringSubmatch(action)
+		j := fieldIndex(def.Fields, matches[1])
+		if j == -1 {
+			return fmt.Errorf("unknown column %s", matches[1])
+		}
+		s.renameColumn(def, def.Fields[j].ColumnName, matches[2])
+	case renameToRegex.MatchString(action):
+		s.renameTable(def.TableName, renameToRegex.FindStringSubmatch(action)[1])
+	case dropColumnRegex.MatchString(action):
+		column := dropColumnRegex.FindStringSubmatch(action)[1]
+		j := fieldIndex(def.Fields, column)
+		if j == -1 {
+			return fmt.Errorf("unknown column %s", column)
+		}
+		def.Fields = append(def.Fields[:j], def.Fields[j+1:]...)
+		def.ForeignKeys = removeForeignKeys(def.ForeignKeys, func(fk ForeignKey) bool {
+			return containsFold(fk.Columns, column)
+		})
+		def.Indexes = removeIndexes(def.Indexes, func(index Index) bool {
+			return containsFold(index.Columns, column)
+		})
+	case addColumnRegex.MatchString(action):
+		return s.addColumns(def, addColumnRegex.FindStringSubmatch(action)[1])
+	case modifyColumnRegex.MatchString(action):
+		definition := modifyColumnRegex.FindStringSubmatch(action)[1]
+		column, _ := extractColumnName(definition)
+		return s.replaceColumn(def, column, definition)
+	case changeColumnRegex.MatchString(action):
+		matches := changeColumnRegex.FindStringSubmatch(action)
+		return s.replaceColumn(def, matches[1], matches[2])
+	case alterColumnRegex.MatchString(action):
+		matches := alterColumnRegex.FindStringSubmatch(action)
+		j := fieldIndex(def.Fields, matches[1])
+		if j == -1 {
+			return fmt.Errorf("unknown column %s", matches[1])
+		}
+		return s.alterColumn(&def.Fields[j], matches[2])
+	default:
+		return fmt.Errorf("unsupported action: %s", action)
+	}
+	return nil
+}
+
+// addColumns adds one column, or a parenthesized list of columns (MySQL), to def
+func (s *Schema) addColumns(def *StructDef, definition string) error {
+	if fk, ok := parseForeignKey(definition); ok {
+		def.ForeignKeys = append(def.ForeignKeys, fk)
+		return nil
+	}
+	if matches := primaryKeyRegex.FindStringSubmatch(definition); matches != nil {
+		setPrimaryKey(def.Fields, splitIdentList(matches[2]))
+		return nil
+	}
+	if index, ok := parseIndex(definition); ok {
+		def.Indexes = append(def.Indexes, index)
+		return nil
+	}
+	if isConstraint(definition) || strings.HasPrefix(strings.ToUpper(definition), "UNIQUE") {
+		return nil
+	}
+
+	definitions := []string{definition}
+	if strings.HasPrefix(definition, "(") && strings.HasSuffix(definition, ")") {
+		definitions = splitColumns(definition[1 : len(definition)-1])
+	}
+
+	for _, definition := range definitions {
+		definition, position := splitColumnPosition(strings.TrimSpace(definition))
+		field, err := parseColumnDefinition(definition, s.opts.Dialect)
+		if err != nil {
+			return err
+		}
+		if fieldIndex(def.Fields, field.ColumnName) != -1 {
+			return fmt.Errorf("column %s already exists", field.ColumnName)
+		}
+		def.Fields = append(def.Fields, field)
+		addInlineConstraints(def, field, definition)
+		if err := moveColumn(def, len(def.Fields)-1, position); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceColumn replaces the definition of column (MODIFY, CHANGE), keeping its position
+// unless FIRST or AFTER is given
+func (s *Schema) replaceColumn(def *StructDef, column, definition string) error {
+	j := fieldIndex(def.Fields, column)
+	if j == -1 {
+		return fmt.Errorf("unknown column %s", column)
+	}
+
+	definition, position := splitColumnPosition(definition)
+	field, err := parseColumnDefinition(definition, s.opts.Dialect)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(field.ColumnName, def.Fields[j].ColumnName) {
+		s.renameColumn(def, def.Fields[j].ColumnName, field.ColumnName)
+	}
+	// The column stays in a table-level PRIMARY KEY unless redefined
+	if def.Fields[j].PrimaryKey {
+		field.PrimaryKey = true
+		setNotNull(&field)
+	}
+	def.Fields[j] = field
+	// A REFERENCES clause replaces the column's own foreign key
+	if _, ok := parseInlineReference(field.ColumnName, definition); ok {
+		def.ForeignKeys = removeForeignKeys(def.ForeignKeys, func(fk ForeignKey) bool {
+			return len(fk.Columns) == 1 && strings.EqualFold(fk.Columns[0], field.ColumnName)
+		})
+	}
+	addInlineConstraints(def, field, definition)
+	return moveColumn(def, j, position)
+}
+
+// alterColumn applies an ALTER COLUMN sub-action such as SET NOT NULL or TYPE
+func (s *Schema) alterColumn(field *FieldDef, change string) error {
+	switch {
+	case setNotNullRegex.MatchString(change):
+		field.Nullable = false
+	case dropNotNullRegex.MatchString(change):
+		field.Nullable = true
+	case setTypeRegex.MatchString(change):
+		typeDef := setTypeRegex.FindStringSubmatch(change)[1]
+		dataType, typeArgs := extractDataType(typeDef)
+		if dataType == "" {
+			return fmt.Errorf("could not extract data type from: %s", typeDef)
+		}
+		setColumnType(field, dataType, typeArgs)
+		field.Unsigned = strings.Contains(strings.ToUpper(typeDef), "UNSIGNED")
+	case setDefaultRegex.MatchString(change):
+		field.Default = parseDefault(change)
+	case dropDefaultRegex.MatchString(change):
+		field.Default = ""
+	case ignoredColumnActionRegex.MatchString(change):
+		// Storage settings and the like don't change the struct
+		return nil
+	default:
+		return fmt.Errorf("unsupported ALTER COLUMN %s: %s", field.ColumnName, change)
+	}
+
+	field.Type = goTypeForColumn(*field, s.opts.Dialect)
+	return nil
+}
+
+// splitColumnPosition splits a trailing MySQL FIRST or AFTER clause from a column definition
+func splitColumnPosition(definition string) (string, string) {
+	loc := columnPositionRegex.FindStringSubmatchIndex(definition)
+	if loc == nil {
+		return definition, ""
+	}
+	return definition[:loc[0]], definition[loc[2]:loc[3]]
+}
+
+// moveColumn moves the field at index j to the position given by FIRST or AFTER col
+func moveColumn(def *StructDef, j int, position string) error {
+	if position == "" {
+		return nil
+	}
+
+	field := def.Fields[j]
+	def.Fields = append(def.Fields[:j], def.Fields[j+1:]...)
+
+	target := 0
+	if !strings.EqualFold(position, "FIRST") {
+		after := columnPositionRegex.FindStringSubmatch(" " + position)[2]
+		k := fieldIndex(def.Fields, after)
+		if k == -1 {
+			def.Fields = append(def.Fields[:j], append([]FieldDef{field}, def.Fields[j:]...)...)
+			return fmt.Errorf("unknown column %s in AFTER", after)
+		}
+		target = k + 1
+	}
+
+	def.Fields = append(def.Fields[:target], append([]FieldDef{field}, def.Fields[target:]...)...)
+	return nil
+}
+
+// renameTable renames a table and its struct
+func (s *Schema) renameTable(from, to string) {
+	i := s.tableIndex(from)
+	if i == -1 {
+		s.warn("RENAME of unknown table %s", from)
+		return
+	}
+	s.tables[i].TableName = to
+	s.tables[i].Name = toPascalCase(to)
+
+	// Keep foreign keys pointing at the renamed table
+	for _, def := range s.tables {
+		for k := range def.ForeignKeys {
+			if strings.EqualFold(def.ForeignKeys[k].RefTable, from) {
+				def.ForeignKeys[k].RefTable = to
+			}
+		}
+	}
+}
+
+// renameColumn renames a column of def, along with the foreign keys that use it
+func (s *Schema) renameColumn(def *StructDef, from, to string) {
+	if j := fieldIndex(def.Fields, from); j != -1 {
+		def.Fields[j].ColumnName = to
+		def.Fields[j].Name = toPascalCase(to)
+	}
+
+	for k := range def.ForeignKeys {
+		replaceFold(def.ForeignKeys[k].Columns, from, to)
+	}
+	for k := range def.Indexes {
+		replaceFold(def.Indexes[k].Columns, from, to)
+	}
+	for _, other := range s.tables {
+		for k := range other.ForeignKeys {
+			if strings.EqualFold(other.ForeignKeys[k].RefTable, def.TableName) {
+				replaceFold(other.ForeignKeys[k].RefColumns, from, to)
+			}
+		}
+	}
+}
+
+// removeForeignKeys returns the foreign keys for which remove reports false
+func removeForeignKeys(fks []ForeignKey, remove func(ForeignKey) bool) []ForeignKey {
+	var kept []ForeignKey
+	for _, fk := range fks {
+		if !remove(fk) {
+			kept = append(kept, fk)
+		}
+	}
+	return kept
+}
+
+// containsFold reports whether list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceFold replaces the items of list equal to from (ignoring case) with to
+func replaceFold(list []string, from, to string) {
+	for i, item := range list {
+		if strings.EqualFold(item, from) {
+			list[i] = to
+		}
+	}
+}
+
+// tableIndex returns the index of a table by name (case-insensitive), or -1
+func (s *Schema) tableIndex(name string) int {
+	for i, def := range s.tables {
+		if strings.EqualFold(def.TableName, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// warn records a warning
+func (s *Schema) warn(format string, args ...any) {
+	s.warnings = append(s.warnings, fmt.Sprintf(format, args...))
+}
+
+// fieldIndex returns the index of a field by column name (case-insensitive), or -1
+func fieldIndex(fields []FieldDef, column string) int {
+	for i, field := range fields {
+		if strings.EqualFold(field.ColumnName, column) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// fieldNames returns the column names of a struct for compact assertions
+func fieldNames(def StructDef) string {
+	var names []string
+	for _, field := range def.Fields {
+		names = append(names, field.ColumnName)
+	}
+	return strings.Join(names, ",")
+}
+
+// TestSchema_CreateDropRename tests replaying table-level DDL
+func TestSchema_CreateDropRename(t *testing.T) {
+	schema := NewSchema(ParseOptions{})
+	err := schema.ApplySQL(`
+		CREATE TABLE users (id INT NOT NULL);
+		CREATE TABLE posts (id INT NOT NULL);
+		CREATE TABLE tmp (id INT NOT NULL);
+		CREATE TABLE IF NOT EXISTS users (id INT NOT NULL, ignored INT);
+		RENAME TABLE posts TO articles;
+		ALTER TABLE users RENAME TO accounts;
+		DROP TABLE IF EXISTS tmp, missing CASCADE;
+		INSERT INTO accounts (id) VALUES (1);
+	`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	tables := schema.Tables()
+	if len(tables) != 2 {
+		t.Fatalf("Expected 2 tables, got %d", len(tables))
+	}
+	if tables[0].TableName != "accounts" || tables[0].Name != "Accounts" {
+		t.Errorf("Expected accounts/Accounts, got %s/%s", tables[0].TableName, tables[0].Name)
+	}
+	if tables[1].TableName != "articles" {
+		t.Errorf("Expected articles, got %s", tables[1].TableName)
+	}
+	if fieldNames(tables[0]) != "id" {
+		t.Errorf("CREATE TABLE IF NOT EXISTS should keep the existing table, got %s", fieldNames(tables[0]))
+	}
+}
+
+// TestSchema_AddDropColumn tests ALTER TABLE ADD and DROP COLUMN
+func TestSchema_AddDropColumn(t *testing.T) {
+	schema := NewSchema(ParseOptions{})
+	err := schema.ApplySQL(`
+		CREATE TABLE "public"."users" (id INT NOT NULL, legacy TEXT);
+		ALTER TABLE public.users ADD COLUMN email VARCHAR(255) NOT NULL, ADD nickname TEXT;
+		ALTER TABLE users DROP COLUMN legacy, ADD CONSTRAINT uq_email UNIQUE (email), DROP INDEX idx_old;
+	`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	users := schema.Tables()[0]
+	if fieldNames(users) != "id,email,nickname" {
+		t.Errorf("Expected id,email,nickname, got %s", fieldNames(users))
+	}
+	if users.Fields[1].Type != "string" || users.Fields[2].Type != "*string" {
+		t.Errorf("Unexpected types: %s, %s", users.Fields[1].Type, users.Fields[2].Type)
+	}
+	if len(schema.Warnings()) != 0 {
+		t.Errorf("Expected no warnings, got: %v", schema.Warnings())
+	}
+}
+
+// TestSchema_Warnings tests that statements on unknown tables and columns are reported
+func TestSchema_Warnings(t *testing.T) {
+	schema := NewSchema(ParseOptions{})
+	err := schema.ApplySQL(`
+		CREATE TABLE users (id INT NOT NULL);
+		ALTER TABLE missing ADD COLUMN x INT;
+		ALTER TABLE users DROP COLUMN nope;
+	`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(schema.Warnings()) != 2 {
+		t.Errorf("Expected 2 warnings, got: %v", schema.Warnings())
+	}
+}
+
+// TestSchema_AlterMySQL tests MySQL MODIFY, CHANGE, RENAME COLUMN and column positions
+func TestSchema_AlterMySQL(t *testing.T) {
+	schema := NewSchema(ParseOptions{Dialect: DialectMySQL})
+	err := schema.ApplySQL("CREATE TABLE users (id INT NOT NULL, name VARCHAR(50), age INT);" +
+		"ALTER TABLE users MODIFY COLUMN name VARCHAR(100) NOT NULL, CHANGE age years_old SMALLINT UNSIGNED NOT NULL;" +
+		"ALTER TABLE users ADD COLUMN email VARCHAR(255) AFTER id, ADD (token CHAR(32) FIRST, flag TINYINT(1));" +
+		"ALTER TABLE `users` RENAME COLUMN `years_old` TO `age`, ADD INDEX idx_name (name), ENGINE=InnoDB;")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	users := schema.Tables()[0]
+	if fieldNames(users) != "token,id,email,name,age,flag" {
+		t.Errorf("Expected token,id,email,name,age,flag, got %s", fieldNames(users))
+	}
+
+	expected := map[string]string{
+		"Token": "*string",
+		"Id":    "int",
+		"Email": "*string",
+		"Name":  "string",
+		"Age":   "uint16",
+		"Flag":  "*bool",
+	}
+	for _, field := range users.Fields {
+		if field.Type != expected[field.Name] {
+			t.Errorf("Field %s: expected type %q, got %q", field.Name, expected[field.Name], field.Type)
+		}
+	}
+	if users.Fields[3].Size != "100" {
+		t.Errorf("Expected name size 100, got %q", users.Fields[3].Size)
+	}
+	if len(schema.Warnings()) != 0 {
+		t.Errorf("Expected no warnings, got: %v", schema.Warnings())
+	}
+}
+
+// TestSchema_ModifyKeepsPrimaryKey tests that MODIFY and CHANGE keep columns in a table-level PRIMARY KEY
+func TestSchema_ModifyKeepsPrimaryKey(t *testing.T) {
+	schema := NewSchema(ParseOptions{Dialect: DialectMySQL})
+	err := schema.ApplySQL("CREATE TABLE users (id INT NOT NULL AUTO_INCREMENT, name VARCHAR(10), PRIMARY KEY (id));" +
+		"CREATE TABLE memberships (user_id INT NOT NULL, group_id INT NOT NULL, role VARCHAR(10), PRIMARY KEY (user_id, group_id));" +
+		"ALTER TABLE users MODIFY id BIGINT NOT NULL AUTO_INCREMENT;" +
+		"ALTER TABLE memberships CHANGE group_id team_id BIGINT NOT NULL, MODIFY role VARCHAR(20);")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	users, memberships := schema.Tables()[0], schema.Tables()[1]
+	if !users.Fields[0].PrimaryKey || !users.Fields[0].AutoIncrement || users.Fields[0].Type != "int64" {
+		t.Errorf("Expected id to stay an int64 auto-increment primary key, got %+v", users.Fields[0])
+	}
+	for i, expected := range []bool{true, true, false} {
+		if memberships.Fields[i].PrimaryKey != expected {
+			t.Errorf("Field %s: expected primary key %v, got %v", memberships.Fields[i].ColumnName, expected, memberships.Fields[i].PrimaryKey)
+		}
+	}
+	if memberships.Fields[1].ColumnName != "team_id" {
+		t.Errorf("Expected team_id, got %s", memberships.Fields[1].ColumnName)
+	}
+}
+
+// TestSchema_AlterPostgres tests PostgreSQL ALTER COLUMN actions
+func TestSchema_AlterPostgres(t *testing.T) {
+	schema := NewSchema(ParseOptions{Dialect: DialectPostgres})
+	err := schema.ApplySQL(`
+		CREATE TABLE users (id INTEGER NOT NULL, email TEXT, score INTEGER NOT NULL, nick TEXT NOT NULL);
+		ALTER TABLE ONLY public.users
+			ALTER COLUMN email SET NOT NULL,
+			ALTER COLUMN score TYPE NUMERIC(10, 2) USING score::numeric,
+			ALTER COLUMN score DROP NOT NULL,
+			ALTER nick SET DEFAULT 'anon',
+			ALTER COLUMN id SET DATA TYPE BIGINT;
+		ALTER TABLE users RENAME nick TO nickname;
+	`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	users := schema.Tables()[0]
+	expected := map[string]string{
+		"Id":       "int64",
+		"Email":    "string",
+		"Score":    "*float64",
+		"Nickname": "string",
+	}
+	for _, field := range users.Fields {
+		if field.Type != expected[field.Name] {
+			t.Errorf("Field %s: expected type %q, got %q", field.Name, expected[field.Name], field.Type)
+		}
+	}
+	if users.Fields[2].SQLType != "NUMERIC" || users.Fields[2].Size != "10,2" {
+		t.Errorf("Expected NUMERIC(10,2), got %s(%s)", users.Fields[2].SQLType, users.Fields[2].Size)
+	}
+	if len(schema.Warnings()) != 0 {
+		t.Errorf("Expected no warnings, got: %v", schema.Warnings())
+	}
+}
+
+// TestSchema_UnsupportedAlter tests that unknown actions are reported without failing
+func TestSchema_UnsupportedAlter(t *testing.T) {
+	schema := NewSchema(ParseOptions{})
+	err := schema.ApplySQL(`
+		CREATE TABLE users (id INT NOT NULL);
+		ALTER TABLE users FROBNICATE everything;
+		ALTER TABLE users ALTER COLUMN id FROBNICATE;
+	`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(schema.Warnings()) != 2 {
+		t.Errorf("Expected 2 warnings, got: %v", schema.Warnings())
+	}
+}
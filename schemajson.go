@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SchemaFormatVersion is the version of the schema JSON format. It changes when
+// fields are removed or change meaning; new optional fields keep the version.
+const SchemaFormatVersion = 1
+
+// SchemaDocument is the parsed schema as JSON, the intermediate model between
+// parsing and generation (see "Schema JSON" in the README)
+type SchemaDocument struct {
+	Version int           `json:"version"`           // SchemaFormatVersion
+	Dialect string        `json:"dialect,omitempty"` // Dialect the schema was parsed as: mysql, postgres or sqlite
+	Tables  []SchemaTable `json:"tables"`
+}
+
+// SchemaTable is a table of a SchemaDocument
+type SchemaTable struct {
+	Name        string             `json:"name"`                   // Table name
+	GoName      string             `json:"go_name,omitempty"`      // Struct name (default: PascalCase table name)
+	Columns     []SchemaColumn     `json:"columns"`                // Columns in order
+	PrimaryKey  []string           `json:"primary_key,omitempty"`  // Primary key columns
+	Indexes     []SchemaIndex      `json:"indexes,omitempty"`      // Indexes and UNIQUE constraints
+	ForeignKeys []SchemaForeignKey `json:"foreign_keys,omitempty"` // FOREIGN KEY constraints
+}
+
+// SchemaColumn is a column of a SchemaTable
+type SchemaColumn struct {
+	Name          string   `json:"name"`                     // Column name
+	SQLType       string   `json:"sql_type"`                 // Upper-case data type without arguments (e.g. "VARCHAR")
+	Size          string   `json:"size,omitempty"`           // Type arguments such as length or precision (e.g. "10,2")
+	Nullable      bool     `json:"nullable"`                 // Column accepts NULL
+	Unsigned      bool     `json:"unsigned,omitempty"`       // Integer column is UNSIGNED
+	AutoIncrement bool     `json:"auto_increment,omitempty"` // AUTO_INCREMENT, SERIAL or identity column
+	Default       string   `json:"default,omitempty"`        // DEFAULT expression as written (e.g. "'pending'", "CURRENT_TIMESTAMP")
+	OnUpdate      string   `json:"on_update,omitempty"`      // ON UPDATE expression of a MySQL column (e.g. "CURRENT_TIMESTAMP")
+	Generated     string   `json:"generated,omitempty"`      // Expression of a generated column
+	Stored        bool     `json:"stored,omitempty"`         // Generated column is STORED rather than VIRTUAL
+	EnumValues    []string `json:"enum_values,omitempty"`    // Values of an ENUM column
+	Comment       string   `json:"comment,omitempty"`        // Column comment
+	GoName        string   `json:"go_name,omitempty"`        // Field name (default: PascalCase column name)
+	GoType        string   `json:"go_type,omitempty"`        // Go type (default: mapped from sql_type and nullable)
+	GoTypeImport  string   `json:"go_type_import,omitempty"` // Import path of go_type, if any
+}
+
+// SchemaIndex is an index of a SchemaTable
+type SchemaIndex struct {
+	Name    string   `json:"name,omitempty"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique,omitempty"`
+	Lengths []int    `json:"lengths,omitempty"` // Prefix length of each column, 0 for the whole column
+}
+
+// SchemaForeignKey is a foreign key of a SchemaTable
+type SchemaForeignKey struct {
+	Name       string   `json:"name,omitempty"`
+	Columns    []string `json:"columns"`
+	RefTable   string   `json:"ref_table"`
+	RefColumns []string `json:"ref_columns,omitempty"` // Empty means the primary key of ref_table
+}
+
+// NewSchemaDocument converts parsed struct definitions to the schema JSON model.
+// Association fields are generated, not parsed, and are left out.
+func NewSchemaDocument(defs []StructDef, dialect Dialect) SchemaDocument {
+	doc := SchemaDocument{Version: SchemaFormatVersion, Dialect: string(dialect), Tables: []SchemaTable{}}
+	for _, def := range defs {
+		table := SchemaTable{Name: def.TableName, GoName: def.Name, Columns: []SchemaColumn{}}
+		for _, field := range def.Fields {
+			if field.Association != "" {
+				continue
+			}
+			table.Columns = append(table.Columns, SchemaColumn{
+				Name:          field.ColumnName,
+				SQLType:       field.SQLType,
+				Size:          field.Size,
+				Nullable:      field.Nullable,
+				Unsigned:      field.Unsigned,
+				AutoIncrement: field.AutoIncrement,
+				Default:       field.Default,
+				OnUpdate:      field.OnUpdate,
+				Generated:     field.Generated,
+				Stored:        field.GeneratedStored,
+				EnumValues:    field.EnumValues,
+				Comment:       field.Comment,
+				GoName:        field.Name,
+				GoType:        field.Type,
+				GoTypeImport:  field.TypeImport,
+			})
+		}
+		table.PrimaryKey = primaryKeyColumns(def)
+		for _, index := range def.Indexes {
+			table.Indexes = append(table.Indexes, SchemaIndex{Name: index.Name, Columns: index.Columns, Unique: index.Unique, Lengths: index.Lengths})
+		}
+		for _, fk := range def.ForeignKeys {
+			table.ForeignKeys = append(table.ForeignKeys, SchemaForeignKey(fk))
+		}
+		doc.Tables = append(doc.Tables, table)
+	}
+	return doc
+}
+
+// MarshalSchemaJSON encodes parsed struct definitions as an indented schema JSON document
+func MarshalSchemaJSON(defs []StructDef, dialect Dialect) ([]byte, error) {
+	data, err := json.MarshalIndent(NewSchemaDocument(defs, dialect), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// DecodeSchemaDocument decodes a schema JSON document.
+// Unknown fields are errors, so that typos don't go unnoticed.
+func DecodeSchemaDocument(data []byte) (SchemaDocument, error) {
+	var doc SchemaDocument
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return SchemaDocument{}, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+	return doc, nil
+}
+
+// ParseSchemaJSON decodes a schema JSON document into struct definitions
+func ParseSchemaJSON(data []byte) ([]StructDef, error) {
+	doc, err := DecodeSchemaDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	return doc.StructDefs()
+}
+
+// StructDefs converts the document to struct definitions, deriving missing Go names
+// and types like the SQL parser. It reports an unsupported version, missing or
+// duplicate names, and keys referring to unknown columns.
+func (doc SchemaDocument) StructDefs() ([]StructDef, error) {
+	if doc.Version != SchemaFormatVersion {
+		return nil, fmt.Errorf("unsupported schema version %d (expected %d)", doc.Version, SchemaFormatVersion)
+	}
+	dialect, err := ParseDialect(doc.Dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	var defs []StructDef
+	tables := make(map[string]bool)
+	for i, table := range doc.Tables {
+		if table.Name == "" {
+			return nil, fmt.Errorf("tables[%d]: missing name", i)
+		}
+		if tables[strings.ToLower(table.Name)] {
+			return nil, fmt.Errorf("table %s: defined twice", table.Name)
+		}
+		tables[strings.ToLower(table.Name)] = true
+
+		def, err := table.structDef(dialect)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", table.Name, err)
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+// structDef converts a table to its struct definition
+func (table SchemaTable) structDef(dialect Dialect) (StructDef, error) {
+	def := StructDef{Name: table.GoName, TableName: table.Name}
+	if def.Name == "" {
+		def.Name = toPascalCase(table.Name)
+	}
+
+	for i, column := range table.Columns {
+		if column.Name == "" {
+			return StructDef{}, fmt.Errorf("columns[%d]: missing name", i)
+		}
+		if fieldIndex(def.Fields, column.Name) >= 0 {
+			return StructDef{}, fmt.Errorf("column %s: defined twice", column.Name)
+		}
+
+		field := FieldDef{
+			Name:            column.GoName,
+			Type:            column.GoType,
+			TypeImport:      column.GoTypeImport,
+			ColumnName:      column.Name,
+			SQLType:         strings.ToUpper(column.SQLType),
+			Size:            column.Size,
+			Nullable:        column.Nullable,
+			Unsigned:        column.Unsigned,
+			EnumValues:      column.EnumValues,
+			Comment:         column.Comment,
+			Default:         column.Default,
+			OnUpdate:        column.OnUpdate,
+			Generated:       column.Generated,
+			GeneratedStored: column.Stored,
+			AutoIncrement:   column.AutoIncrement,
+		}
+		if field.Name == "" {
+			field.Name = toPascalCase(column.Name)
+		}
+		if field.Type == "" {
+			field.Type = goTypeForColumn(field, dialect)
+		}
+		def.Fields = append(def.Fields, field)
+	}
+
+	for _, column := range table.PrimaryKey {
+		i := fieldIndex(def.Fields, column)
+		if i < 0 {
+			return StructDef{}, fmt.Errorf("primary key column %s does not exist", column)
+		}
+		def.Fields[i].PrimaryKey = true
+	}
+
+	for _, index := range table.Indexes {
+		if err := checkColumnsExist(def, index.Columns); err != nil {
+			return StructDef{}, fmt.Errorf("index %s: %w", index.Name, err)
+		}
+		def.Indexes = append(def.Indexes, Index{Name: index.Name, Columns: index.Columns, Unique: index.Unique, Lengths: index.Lengths})
+	}
+	for _, fk := range table.ForeignKeys {
+		if err := checkColumnsExist(def, fk.Columns); err != nil {
+			return StructDef{}, fmt.Errorf("foreign key to %s: %w", fk.RefTable, err)
+		}
+		if fk.RefTable == "" {
+			return StructDef{}, fmt.Errorf("foreign key on %s: missing ref_table", strings.Join(fk.Columns, ", "))
+		}
+		def.ForeignKeys = append(def.ForeignKeys, ForeignKey(fk))
+	}
+
+	return def, nil
+}
+
+// checkColumnsExist reports an error for an empty column list or a column that is not in def
+func checkColumnsExist(def StructDef, columns []string) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("no columns")
+	}
+	for _, column := range columns {
+		if fieldIndex(def.Fields, column) < 0 {
+			return fmt.Errorf("column %s does not exist", column)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestMarshalSchemaJSON tests the schema JSON of tables with keys, indexes and comments
+func TestMarshalSchemaJSON(t *testing.T) {
+	structs, err := ParseSQL(`
+		CREATE TABLE users (
+			id INT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			status ENUM('active','banned') NOT NULL DEFAULT 'active' COMMENT 'account state',
+			UNIQUE KEY uq_status (status)
+		);
+		CREATE TABLE posts (
+			id INT NOT NULL PRIMARY KEY,
+			user_id INT UNSIGNED NOT NULL,
+			price DECIMAL(10,2),
+			CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id)
+		);`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := MarshalSchemaJSON(structs, DialectMySQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := `{
+  "version": 1,
+  "dialect": "mysql",
+  "tables": [
+    {
+      "name": "users",
+      "go_name": "Users",
+      "columns": [
+        {
+          "name": "id",
+          "sql_type": "INT",
+          "nullable": false,
+          "unsigned": true,
+          "auto_increment": true,
+          "go_name": "Id",
+          "go_type": "uint32"
+        },
+        {
+          "name": "status",
+          "sql_type": "ENUM",
+          "nullable": false,
+          "default": "'active'",
+          "enum_values": [
+            "active",
+            "banned"
+          ],
+          "comment": "account state",
+          "go_name": "Status",
+          "go_type": "string"
+        }
+      ],
+      "primary_key": [
+        "id"
+      ],
+      "indexes": [
+        {
+          "name": "uq_status",
+          "columns": [
+            "status"
+          ],
+          "unique": true
+        }
+      ]
+    },
+    {
+      "name": "posts",
+      "go_name": "Posts",
+      "columns": [
+        {
+          "name": "id",
+          "sql_type": "INT",
+          "nullable": false,
+          "go_name": "Id",
+          "go_type": "int"
+        },
+        {
+          "name": "user_id",
+          "sql_type": "INT",
+          "nullable": false,
+          "unsigned": true,
+          "go_name": "UserId",
+          "go_type": "uint32"
+        },
+        {
+          "name": "price",
+          "sql_type": "DECIMAL",
+          "size": "10,2",
+          "nullable": true,
+          "go_name": "Price",
+          "go_type": "*float64"
+        }
+      ],
+      "primary_key": [
+        "id"
+      ],
+      "foreign_keys": [
+        {
+          "name": "fk_user",
+          "columns": [
+            "user_id"
+          ],
+          "ref_table": "users",
+          "ref_columns": [
+            "id"
+          ]
+        }
+      ]
+    }
+  ]
+}
+`
+	if string(data) != expected {
+		t.Errorf("Unexpected schema JSON.\nExpected:\n%s\nGot:\n%s", expected, data)
+	}
+}
+
+// TestParseSchemaJSON_RoundTrip tests that decoding the schema JSON gives back the parsed schema
+func TestParseSchemaJSON_RoundTrip(t *testing.T) {
+	structs, err := ParseSQL(`
+		CREATE TABLE orders (
+			id BIGINT NOT NULL AUTO_INCREMENT,
+			customer_id INT NOT NULL,
+			note TEXT COMMENT 'free text',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			note_length INT AS (CHAR_LENGTH(note)) STORED,
+			PRIMARY KEY (id),
+			INDEX idx_customer (customer_id),
+			INDEX idx_note (note(20), customer_id),
+			FOREIGN KEY (customer_id) REFERENCES customers(id)
+		);`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := MarshalSchemaJSON(structs, DialectMySQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	decoded, err := ParseSchemaJSON(data)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded, structs) {
+		t.Errorf("Expected the parsed schema back.\nExpected: %+v\nGot:      %+v", structs, decoded)
+	}
+	if GenerateGoCode(decoded, Config{}) != GenerateGoCode(structs, Config{}) {
+		t.Error("Expected the same generated code from the schema JSON")
+	}
+}
+
+// TestParseSchemaJSON_Defaults tests that Go names and types are derived when left out
+func TestParseSchemaJSON_Defaults(t *testing.T) {
+	structs, err := ParseSchemaJSON([]byte(`{
+		"version": 1,
+		"dialect": "postgres",
+		"tables": [{
+			"name": "audit_log",
+			"columns": [
+				{"name": "id", "sql_type": "uuid", "nullable": false},
+				{"name": "payload", "sql_type": "JSONB", "nullable": true}
+			],
+			"primary_key": ["id"]
+		}]
+	}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	def := structs[0]
+	if def.Name != "AuditLog" {
+		t.Errorf("Expected struct name AuditLog, got %s", def.Name)
+	}
+	if def.Fields[0].Name != "Id" || def.Fields[0].Type != "string" || def.Fields[0].SQLType != "UUID" || !def.Fields[0].PrimaryKey {
+		t.Errorf("Expected a string UUID primary key, got %+v", def.Fields[0])
+	}
+	if def.Fields[1].Type != goTypeForColumn(def.Fields[1], DialectPostgres) {
+		t.Errorf("Expected the PostgreSQL type mapping, got %s", def.Fields[1].Type)
+	}
+}
+
+// TestParseSchemaJSON_Errors tests invalid schema JSON documents
+func TestParseSchemaJSON_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected string
+	}{
+		{"Not JSON", `CREATE TABLE`, "invalid schema JSON"},
+		{"Unknown field", `{"version": 1, "tables": [], "tabels": []}`, "unknown field"},
+		{"Unsupported version", `{"version": 2, "tables": []}`, "unsupported schema version 2"},
+		{"Unknown dialect", `{"version": 1, "dialect": "oracle", "tables": []}`, "unknown dialect"},
+		{"Missing table name", `{"version": 1, "tables": [{"columns": []}]}`, "tables[0]: missing name"},
+		{"Duplicate table", `{"version": 1, "tables": [{"name": "t", "columns": []}, {"name": "T", "columns": []}]}`, "table T: defined twice"},
+		{"Duplicate column", `{"version": 1, "tables": [{"name": "t", "columns": [{"name": "a", "sql_type": "INT"}, {"name": "a", "sql_type": "INT"}]}]}`, "column a: defined twice"},
+		{"Unknown primary key column", `{"version": 1, "tables": [{"name": "t", "columns": [], "primary_key": ["id"]}]}`, "primary key column id does not exist"},
+		{"Unknown index column", `{"version": 1, "tables": [{"name": "t", "columns": [], "indexes": [{"name": "ix", "columns": ["a"]}]}]}`, "index ix: column a does not exist"},
+		{"Foreign key without table", `{"version": 1, "tables": [{"name": "t", "columns": [{"name": "a", "sql_type": "INT"}], "foreign_keys": [{"columns": ["a"]}]}]}`, "missing ref_table"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSchemaJSON([]byte(tt.json))
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -3,7 +3,7 @@
 echo "🔨 Building SQL to Go Converter..."
 
 # Build the binary
-go build -o sql-to-go main.go converter.go
+go build -o sql-to-go .
 
 if [ $? -eq 0 ]; then
     echo "✅ Build successful!"
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sqlxImports are the packages used by the generated sqlx queries and helpers
+var sqlxImports = []string{"context", "github.com/jmoiron/sqlx"}
+
+// generateSQLX generates the sqlx queries and helpers of a table: a SELECT column-list
+// constant, named INSERT and UPDATE statements and Get and Select functions.
+// Named parameters and selected columns match the db tags of the struct.
+func generateSQLX(def StructDef, config Config) string {
+	dialect := repositoryDialect(config)
+	table := quoteIdent(def.TableName, dialect)
+	columns := repositoryColumns(def)
+	var keys []FieldDef
+	for _, field := range columns {
+		if field.PrimaryKey {
+			keys = append(keys, field)
+		}
+	}
+
+	var output strings.Builder
+
+	// Column list, aliased to the db tag where the tag differs from the column name
+	selected := make([]string, len(columns))
+	for i, field := range columns {
+		selected[i] = quoteIdent(field.ColumnName, dialect)
+		if tag := toSnakeCase(field.ColumnName); tag != field.ColumnName {
+			selected[i] += " AS " + quoteIdent(tag, dialect)
+		}
+	}
+	columnsConst := def.Name + "Columns"
+	output.WriteString(fmt.Sprintf("// %s is the column list of %s for SELECT statements\n", columnsConst, def.TableName))
+	output.WriteString(fmt.Sprintf("const %s = %q\n", columnsConst, strings.Join(selected, ", ")))
+
+	// Named INSERT, leaving auto-increment, generated and current-time columns to the database
+	var inserted, params []string
+	for _, field := range columns {
+		if isInsertedColumn(field) {
+			inserted = append(inserted, field.ColumnName)
+			params = append(params, ":"+toSnakeCase(field.ColumnName))
+		}
+	}
+	if len(inserted) > 0 {
+		insertConst := "Insert" + def.Name + "Query"
+		output.WriteString(fmt.Sprintf("\n// %s is the named INSERT statement of %s for NamedExecContext\n", insertConst, def.TableName))
+		output.WriteString(fmt.Sprintf("const %s = %q\n", insertConst, fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+			table, quoteIdents(inserted, dialect), strings.Join(params, ", "))))
+	}
+
+	// Named UPDATE of the non-key columns the database doesn't set, by primary key
+	var assignments, conditions []string
+	for _, field := range columns {
+		assignment := fmt.Sprintf("%s = :%s", quoteIdent(field.ColumnName, dialect), toSnakeCase(field.ColumnName))
+		switch {
+		case field.PrimaryKey:
+			conditions = append(conditions, assignment)
+		case isUpdatedColumn(field):
+			assignments = append(assignments, assignment)
+		}
+	}
+	if len(keys) > 0 && len(assignments) > 0 {
+		updateConst := "Update" + def.Name + "Query"
+		output.WriteString(fmt.Sprintf("\n// %s is the named UPDATE statement of %s by primary key for NamedExecContext\n", updateConst, def.TableName))
+		output.WriteString(fmt.Sprintf("const %s = %q\n", updateConst, fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+			table, strings.Join(assignments, ", "), strings.Join(conditions, " AND "))))
+	}
+
+	if len(keys) > 0 {
+		where, keyParams, args := keyCondition(keys, dialect, 1)
+		name := "Get" + def.Name
+		output.WriteString(fmt.Sprintf("\n// %s returns the %s row with the given primary key\n", name, def.TableName))
+		output.WriteString(fmt.Sprintf("func %s(ctx context.Context, db sqlx.QueryerContext, %s) (*%s, error) {\n", name, keyParams, def.Name))
+		output.WriteString(fmt.Sprintf("\tvar row %s\n", def.Name))
+		output.WriteString(fmt.Sprintf("\tquery := \"SELECT \" + %s + %q\n", columnsConst, fmt.Sprintf(" FROM %s WHERE %s", table, where)))
+		output.WriteString(fmt.Sprintf("\tif err := sqlx.GetContext(ctx, db, &row, query%s); err != nil {\n", args))
+		output.WriteString("\t\treturn nil, err\n\t}\n")
+		output.WriteString("\treturn &row, nil\n")
+		output.WriteString("}\n")
+	}
+
+	order := ""
+	if len(keys) > 0 {
+		order = " ORDER BY " + quoteIdents(columnNames(keys), dialect)
+	}
+	name := "Select" + pluralize(def.Name)
+	output.WriteString(fmt.Sprintf("\n// %s returns the %s rows matching where, a condition with %s\n", name, def.TableName, placeholderDescription(dialect)))
+	output.WriteString("// parameters for args, or all rows if where is empty\n")
+	output.WriteString(fmt.Sprintf("func %s(ctx context.Context, db sqlx.QueryerContext, where string, args ...any) ([]%s, error) {\n", name, def.Name))
+	output.WriteString(fmt.Sprintf("\tquery := \"SELECT \" + %s + %q\n", columnsConst, " FROM "+table))
+	output.WriteString("\tif where != \"\" {\n")
+	output.WriteString("\t\tquery += \" WHERE \" + where\n")
+	output.WriteString("\t}\n")
+	if order != "" {
+		output.WriteString(fmt.Sprintf("\tquery += %q\n", order))
+	}
+	output.WriteString(fmt.Sprintf("\tvar rows []%s\n", def.Name))
+	output.WriteString("\tif err := sqlx.SelectContext(ctx, db, &rows, query, args...); err != nil {\n")
+	output.WriteString("\t\treturn nil, err\n\t}\n")
+	output.WriteString("\treturn rows, nil\n")
+	output.WriteString("}\n")
+
+	return output.String()
+}
+
+// placeholderDescription describes the query parameter placeholders of dialect
+func placeholderDescription(dialect Dialect) string {
+	if dialect == DialectPostgres {
+		return "$1, $2, ..."
+	}
+	return "?"
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// sqlxStub declares the parts of github.com/jmoiron/sqlx used by the generated helpers
+const sqlxStub = `package sqlx
+
+import (
+	"context"
+	"database/sql"
+)
+
+type QueryerContext interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func GetContext(ctx context.Context, q QueryerContext, dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+
+func SelectContext(ctx context.Context, q QueryerContext, dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+`
+
+// TestGenerateSQLX tests the named queries and helpers generated for sqlx
+func TestGenerateSQLX(t *testing.T) {
+	structs, err := ParseSQL(repositorySQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := `// UsersColumns is the column list of users for SELECT statements
+const UsersColumns = "id, name, email"
+
+// InsertUsersQuery is the named INSERT statement of users for NamedExecContext
+const InsertUsersQuery = "INSERT INTO users (name, email) VALUES (:name, :email)"
+
+// UpdateUsersQuery is the named UPDATE statement of users by primary key for NamedExecContext
+const UpdateUsersQuery = "UPDATE users SET name = :name, email = :email WHERE id = :id"
+
+// GetUsers returns the users row with the given primary key
+func GetUsers(ctx context.Context, db sqlx.QueryerContext, id int) (*Users, error) {
+	var row Users
+	query := "SELECT " + UsersColumns + " FROM users WHERE id = ?"
+	if err := sqlx.GetContext(ctx, db, &row, query, id); err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// SelectUsers returns the users rows matching where, a condition with ?
+// parameters for args, or all rows if where is empty
+func SelectUsers(ctx context.Context, db sqlx.QueryerContext, where string, args ...any) ([]Users, error) {
+	query := "SELECT " + UsersColumns + " FROM users"
+	if where != "" {
+		query += " WHERE " + where
+	}
+	query += " ORDER BY id"
+	var rows []Users
+	if err := sqlx.SelectContext(ctx, db, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+`
+	if code := generateSQLX(structs[0], Config{}); code != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, code)
+	}
+
+	// db tags are implied, and the output replaces the database/sql repository
+	code := GenerateGoCode(structs, Config{AddSQLX: true, AddRepository: true, Dialect: DialectPostgres})
+	for _, want := range []string{
+		"\"github.com/jmoiron/sqlx\"",
+		"Email *string `db:\"email\"`",
+		`query := "SELECT " + UserRolesColumns + " FROM user_roles WHERE user_id = $1 AND type = $2"`,
+		`const InsertEventsQuery = "INSERT INTO events (payload) VALUES (:payload)"`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Expected %q in:\n%s", want, code)
+		}
+	}
+	for _, unwanted := range []string{"DBTX", "UpdateUserRolesQuery", "GetEvents"} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("Expected no %s in:\n%s", unwanted, code)
+		}
+	}
+	typeCheck(t, code, map[string]string{"github.com/jmoiron/sqlx": sqlxStub})
+}
+
+// TestGenerateSQLX_ColumnAliases tests that selected columns match db tags that differ from column names
+func TestGenerateSQLX_ColumnAliases(t *testing.T) {
+	structs, _, err := ParseSQLWithOptions(`CREATE TABLE "Accounts" ("AccountID" SERIAL PRIMARY KEY, "DisplayName" TEXT NOT NULL);`,
+		ParseOptions{Dialect: DialectPostgres})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	code := generateSQLX(structs[0], Config{Dialect: DialectPostgres})
+	for _, want := range []string{
+		`const AccountsColumns = "\"AccountID\" AS account_id, \"DisplayName\" AS display_name"`,
+		`const InsertAccountsQuery = "INSERT INTO \"Accounts\" (\"DisplayName\") VALUES (:display_name)"`,
+		`WHERE \"AccountID\" = :account_id"`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Expected %q in:\n%s", want, code)
+		}
+	}
+}
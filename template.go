@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// templateExtension is the extension of template files, removed from the output file name
+const templateExtension = ".tmpl"
+
+// templateTarget is the --target name of the template generator, which is not registered
+// as it is loaded from the configured template files
+const templateTarget = "template"
+
+// TemplateData is the data templates are executed with
+type TemplateData struct {
+	Tables []StructDef // All tables, in order
+	Table  StructDef   // The table of a per-table template
+	Config Config
+}
+
+// TemplateGenerator generates files from user text/template files. A template named
+// "queries.sql.tmpl" is executed once with all tables and generates "queries.sql".
+// A template whose file name is itself a template, such as "{{snake .Table.Name}}.kt.tmpl",
+// is executed once per table and generates the file named by executing its name.
+type TemplateGenerator struct {
+	templates []*namedTemplate
+}
+
+// namedTemplate is a parsed template file with its parsed output file name
+type namedTemplate struct {
+	body     *template.Template
+	name     *template.Template // Output file name; nil for single-file templates
+	fileName string             // Output file name of single-file templates
+}
+
+// LoadTemplateGenerator parses the template files matching the globs
+func LoadTemplateGenerator(patterns ...string) (*TemplateGenerator, error) {
+	var paths []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no template files match %q", pattern)
+		}
+		sort.Strings(matches)
+		paths = append(paths, matches...)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no template files given")
+	}
+
+	gen := &TemplateGenerator{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := parseTemplateFile(filepath.Base(path), string(data))
+		if err != nil {
+			return nil, err
+		}
+		gen.templates = append(gen.templates, tmpl)
+	}
+	return gen, nil
+}
+
+// parseTemplateFile parses a template and its file name
+func parseTemplateFile(baseName, text string) (*namedTemplate, error) {
+	fileName := strings.TrimSuffix(baseName, templateExtension)
+
+	body, err := template.New(baseName).Funcs(templateFuncs(Config{})).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := &namedTemplate{body: body, fileName: fileName}
+	if strings.Contains(fileName, "{{") {
+		tmpl.name, err = template.New(baseName + " name").Funcs(templateFuncs(Config{})).Parse(fileName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+// templateFuncs returns the helper functions available in templates.
+// sqlType writes column types for the dialect of config.
+func templateFuncs(config Config) template.FuncMap {
+	return template.FuncMap{
+		"pascal": func(s string) string { return toPascalCase(toSnakeCase(s)) },
+		"snake":  toSnakeCase,
+		"camel":  toCamelCase,
+		"plural": pluralize,
+		"goType": func(field FieldDef) string { return field.Type },
+		"sqlType": func(field FieldDef) string {
+			return columnTypeDDL(field, config.Dialect)
+		},
+	}
+}
+
+// Name returns "template"
+func (g *TemplateGenerator) Name() string {
+	return templateTarget
+}
+
+// Extension returns the extension of the files the templates generate, such as ".kt",
+// or "" if they differ, so that output always goes to a directory
+func (g *TemplateGenerator) Extension() string {
+	extension := ""
+	for i, tmpl := range g.templates {
+		ext := filepath.Ext(tmpl.fileName)
+		if i > 0 && ext != extension {
+			return ""
+		}
+		extension = ext
+	}
+	return extension
+}
+
+// Generate executes the templates. With config.SingleFile the outputs of all templates
+// are concatenated into one file. Generated .go files are gofmt-formatted.
+func (g *TemplateGenerator) Generate(defs []StructDef, config Config) ([]File, error) {
+	funcs := templateFuncs(config)
+	data := TemplateData{Tables: defs, Config: config}
+
+	var files []File
+	for _, tmpl := range g.templates {
+		body := template.Must(tmpl.body.Clone()).Funcs(funcs)
+		if tmpl.name == nil {
+			file, err := executeTemplate(body, tmpl.fileName, data)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, file)
+			continue
+		}
+
+		name := template.Must(tmpl.name.Clone()).Funcs(funcs)
+		for _, def := range defs {
+			data.Table = def
+			var fileName strings.Builder
+			if err := name.Execute(&fileName, data); err != nil {
+				return nil, err
+			}
+			file, err := executeTemplate(body, fileName.String(), data)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, file)
+		}
+		data.Table = StructDef{}
+	}
+
+	if config.SingleFile {
+		var content strings.Builder
+		for _, file := range files {
+			content.WriteString(file.Content)
+		}
+		name := ""
+		if len(files) > 0 {
+			name = files[0].Name
+		}
+		return []File{{Name: name, Content: content.String()}}, nil
+	}
+	return files, nil
+}
+
+// executeTemplate executes a template into the file with the given name
+func executeTemplate(tmpl *template.Template, fileName string, data TemplateData) (File, error) {
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, data); err != nil {
+		return File{}, err
+	}
+
+	content := output.Bytes()
+	if filepath.Ext(fileName) == ".go" {
+		formatted, err := format.Source(content)
+		if err != nil {
+			return File{}, fmt.Errorf("%s: generated invalid Go: %w", fileName, err)
+		}
+		content = formatted
+	}
+	return File{Name: fileName, Content: string(content)}, nil
+}
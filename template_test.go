@@ -0,0 +1,112 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// templateSQL is the schema the template tests execute with
+const templateSQL = `
+CREATE TABLE order_items (id INT NOT NULL PRIMARY KEY, product_name VARCHAR(100) NOT NULL);
+CREATE TABLE users (id BIGINT NOT NULL PRIMARY KEY);`
+
+// TestTemplateGenerator tests single-file and per-table templates and their helper functions
+func TestTemplateGenerator(t *testing.T) {
+	structs, err := ParseSQL(templateSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	dir := t.TempDir()
+	writeTestFile(t, dir, "tables.md.tmpl", "{{range .Tables}}- {{.TableName}} ({{plural (snake .Name)}})\n{{end}}")
+	writeTestFile(t, dir, "{{snake .Table.Name}}.kt.tmpl", `data class {{pascal .Table.TableName}}(
+{{- range $i, $f := .Table.Fields}}{{if $i}}, {{end}}val {{camel $f.ColumnName}}: {{goType $f}} /* {{sqlType $f}} */{{end -}}
+)
+`)
+
+	gen, err := LoadTemplateGenerator(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if gen.Extension() != "" {
+		t.Errorf("Expected no extension for templates of different file types, got %q", gen.Extension())
+	}
+
+	files, err := gen.Generate(structs, Config{Dialect: DialectPostgres})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	expected := []File{
+		{"tables.md", "- order_items (order_items)\n- users (users)\n"},
+		{"order_items.kt", "data class OrderItems(val id: int /* INTEGER */, val productName: string /* VARCHAR(100) */)\n"},
+		{"users.kt", "data class Users(val id: int64 /* BIGINT */)\n"},
+	}
+	// Templates are executed in file name order
+	if len(files) != len(expected) {
+		t.Fatalf("Expected %d files, got %v", len(expected), files)
+	}
+	for i, want := range expected {
+		if files[i] != want {
+			t.Errorf("Expected %+v, got %+v", want, files[i])
+		}
+	}
+
+	files, err = gen.Generate(structs, Config{SingleFile: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(files) != 1 || !strings.HasPrefix(files[0].Content, "- order_items") || !strings.HasSuffix(files[0].Content, "(val id: int64 /* BIGINT */)\n") {
+		t.Errorf("Expected the concatenated outputs, got %v", files)
+	}
+}
+
+// TestTemplateGenerator_Go tests that generated Go is formatted and invalid Go is an error
+func TestTemplateGenerator_Go(t *testing.T) {
+	structs, err := ParseSQL(templateSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	dir := t.TempDir()
+	writeTestFile(t, dir, "tables.go.tmpl", "package models\nvar Tables = []string{ {{range .Tables}}{{printf \"%q\" .TableName}},{{end}} }\n")
+	gen, err := LoadTemplateGenerator(filepath.Join(dir, "tables.go.tmpl"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if gen.Extension() != ".go" {
+		t.Errorf("Expected the .go extension, got %q", gen.Extension())
+	}
+	files, err := gen.Generate(structs, Config{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if want := "package models\n\nvar Tables = []string{\"order_items\", \"users\"}\n"; files[0].Content != want {
+		t.Errorf("Expected %q, got %q", want, files[0].Content)
+	}
+
+	writeTestFile(t, dir, "broken.go.tmpl", "package models\nfunc {\n")
+	gen, err = LoadTemplateGenerator(filepath.Join(dir, "broken.go.tmpl"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := gen.Generate(structs, Config{}); err == nil || !strings.Contains(err.Error(), "broken.go: generated invalid Go") {
+		t.Errorf("Expected an invalid Go error, got: %v", err)
+	}
+}
+
+// TestLoadTemplateGenerator_Errors tests missing and invalid template files
+func TestLoadTemplateGenerator_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "bad.txt.tmpl", "{{range .Tables}")
+
+	tests := map[string]string{
+		filepath.Join(dir, "missing*.tmpl"): "no template files match",
+		filepath.Join(dir, "bad.txt.tmpl"):  "bad.txt.tmpl",
+	}
+	for pattern, want := range tests {
+		if _, err := LoadTemplateGenerator(pattern); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadTemplateGenerator(%q): expected an error containing %q, got: %v", pattern, want, err)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// tsIdentifierRegex matches property names that need no quotes in TypeScript
+var tsIdentifierRegex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsTypes maps Go types to the TypeScript types of their JSON encoding
+var tsTypes = map[string]string{
+	"bool":      "boolean",
+	"int":       "number",
+	"int8":      "number",
+	"int16":     "number",
+	"int32":     "number",
+	"int64":     "number",
+	"uint":      "number",
+	"uint8":     "number",
+	"uint16":    "number",
+	"uint32":    "number",
+	"uint64":    "number",
+	"float32":   "number",
+	"float64":   "number",
+	"string":    "string",
+	"time.Time": "string", // RFC 3339
+	"[]byte":    "string", // base64
+}
+
+// GenerateTypeScript generates a TypeScript interface for each struct definition,
+// describing the JSON the Go structs serialize to: property names follow the json
+// tags, nullable columns are "T | null" and ENUM columns are string literal unions.
+// int64 fields are numbers with a precision note, or strings with config.Int64AsString.
+func GenerateTypeScript(defs []StructDef, config Config) string {
+	if len(defs) == 0 {
+		return ""
+	}
+
+	var body strings.Builder
+	for i, def := range generatedDefs(defs, config) {
+		if i > 0 {
+			body.WriteString("\n")
+		}
+		body.WriteString(generateTSInterface(def, config))
+	}
+
+	return generateTSFile(defs, nil, body.String(), config)
+}
+
+// GenerateTypeScriptFiles generates one .ts file per table, keyed by file name (e.g. "order_items.ts").
+// Interfaces referenced by association fields are imported from their files.
+func GenerateTypeScriptFiles(defs []StructDef, config Config) map[string]string {
+	files := make(map[string]string)
+
+	typed := generatedDefs(defs, config)
+	modules := make(map[string]string)
+	for _, def := range typed {
+		modules[def.Name] = tsModuleName(def)
+	}
+
+	for i, def := range typed {
+		var imports []string
+		for _, name := range tsReferencedTypes(def) {
+			if module, ok := modules[name]; ok && name != def.Name {
+				imports = append(imports, fmt.Sprintf("import type { %s } from \"./%s\";", name, module))
+			}
+		}
+		files[tsModuleName(def)+".ts"] = generateTSFile(defs[i:i+1], imports, generateTSInterface(def, config), config)
+	}
+	return files
+}
+
+// tsModuleName returns the file name of a table's interface without the .ts extension
+func tsModuleName(def StructDef) string {
+	return strings.TrimSuffix(goFileName(def), ".go")
+}
+
+// generateTSFile assembles a TypeScript file: header comments, imports and body
+func generateTSFile(source []StructDef, imports []string, body string, config Config) string {
+	var output strings.Builder
+
+	// Build constraints don't apply to TypeScript files
+	config.BuildTags = ""
+	output.WriteString(generateFileHeader(source, config))
+	if len(imports) > 0 {
+		output.WriteString(strings.Join(imports, "\n"))
+		output.WriteString("\n\n")
+	}
+
+	output.WriteString(body)
+	return output.String()
+}
+
+// generateTSInterface generates the exported interface of a struct definition
+func generateTSInterface(def StructDef, config Config) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("/** %s is a row of the %s table. */\n", def.Name, def.TableName))
+	output.WriteString(fmt.Sprintf("export interface %s {\n", def.Name))
+
+	for _, field := range def.Fields {
+		var notes []string
+		if field.Comment != "" {
+			notes = append(notes, strings.Join(strings.Fields(field.Comment), " "))
+		}
+		if isInt64Field(field) && !config.Int64AsString {
+			notes = append(notes, "Values above Number.MAX_SAFE_INTEGER (2^53 - 1) lose precision.")
+		}
+		switch len(notes) {
+		case 0:
+		case 1:
+			output.WriteString(fmt.Sprintf("  /** %s */\n", notes[0]))
+		default:
+			output.WriteString("  /**\n")
+			for _, note := range notes {
+				output.WriteString(fmt.Sprintf("   * %s\n", note))
+			}
+			output.WriteString("   */\n")
+		}
+
+		name := tsPropertyName(jsonFieldName(field))
+		if field.Association != "" {
+			// Relations are omitted from the JSON when empty
+			output.WriteString(fmt.Sprintf("  %s?: %s;\n", name, tsAssociationType(field)))
+			continue
+		}
+		output.WriteString(fmt.Sprintf("  %s: %s;\n", name, tsFieldType(field, config)))
+	}
+
+	output.WriteString("}\n")
+	return output.String()
+}
+
+// tsFieldType returns the TypeScript type of a column field
+func tsFieldType(field FieldDef, config Config) string {
+	tsType := "unknown"
+	switch {
+	case len(field.EnumValues) > 0:
+		values := make([]string, len(field.EnumValues))
+		for i, value := range field.EnumValues {
+			values[i] = strconv.Quote(value)
+		}
+		tsType = strings.Join(values, " | ")
+	case isInt64Field(field) && config.Int64AsString:
+		tsType = "string"
+	default:
+		if t, ok := tsTypes[strings.TrimPrefix(field.Type, "*")]; ok {
+			tsType = t
+		}
+	}
+
+	if field.Nullable && tsType != "unknown" {
+		return tsType + " | null"
+	}
+	return tsType
+}
+
+// tsAssociationType returns the TypeScript type of an association field, such as User or Post[]
+func tsAssociationType(field FieldDef) string {
+	if name, ok := strings.CutPrefix(field.Type, "[]"); ok {
+		return name + "[]"
+	}
+	return strings.TrimPrefix(field.Type, "*")
+}
+
+// tsReferencedTypes returns the sorted interface names referenced by association fields
+func tsReferencedTypes(def StructDef) []string {
+	seen := make(map[string]bool)
+	for _, field := range def.Fields {
+		if field.Association != "" {
+			seen[strings.TrimSuffix(tsAssociationType(field), "[]")] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// tsPropertyName quotes property names that are not identifiers
+func tsPropertyName(name string) string {
+	if tsIdentifierRegex.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// isInt64Field reports whether a column holds 64-bit integers, which exceed the
+// integers JavaScript numbers represent exactly
+func isInt64Field(field FieldDef) bool {
+	switch strings.TrimPrefix(field.Type, "*") {
+	case "int64", "uint64":
+		return field.Association == ""
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// typeScriptSQL has a 64-bit key, an ENUM, nullable columns and a foreign key
+const typeScriptSQL = `
+CREATE TABLE users (
+	id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	email VARCHAR(255) NOT NULL COMMENT 'login email',
+	status ENUM('active','banned'),
+	created_at TIMESTAMP NOT NULL,
+	deleted_at DATETIME,
+	verified BOOLEAN NOT NULL
+);
+CREATE TABLE posts (
+	id INT NOT NULL PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);`
+
+// TestGenerateTypeScript tests the interfaces generated for the JSON encoding of the structs
+func TestGenerateTypeScript(t *testing.T) {
+	structs, err := ParseSQL(typeScriptSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := `// Code generated by sql-to-go; DO NOT EDIT.
+
+/** Users is a row of the users table. */
+export interface Users {
+  /** Values above Number.MAX_SAFE_INTEGER (2^53 - 1) lose precision. */
+  id: number;
+  /** login email */
+  email: string;
+  status: "active" | "banned" | null;
+  created_at: string;
+  deleted_at: string | null;
+  verified: boolean;
+  posts?: Posts[];
+}
+
+/** Posts is a row of the posts table. */
+export interface Posts {
+  id: number;
+  /** Values above Number.MAX_SAFE_INTEGER (2^53 - 1) lose precision. */
+  user_id: number;
+  user?: Users;
+}
+`
+	if code := GenerateTypeScript(structs, Config{AddHeader: true, AddAssociations: true}); code != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, code)
+	}
+}
+
+// TestGenerateTypeScript_Int64AsString tests that int64 fields are strings in both the JSON tags and TypeScript
+func TestGenerateTypeScript_Int64AsString(t *testing.T) {
+	structs, err := ParseSQL(typeScriptSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	config := Config{AddJSONTag: true, Int64AsString: true}
+	code := GenerateTypeScript(structs, config)
+	if !strings.Contains(code, "  id: string;\n") || !strings.Contains(code, "  user_id: string;\n") || strings.Contains(code, "MAX_SAFE_INTEGER") {
+		t.Errorf("Expected int64 fields typed as string, got:\n%s", code)
+	}
+
+	goCode := GenerateGoCode(structs, config)
+	if !strings.Contains(goCode, "`json:\"id,string\"`") || !strings.Contains(goCode, "`json:\"user_id,string\"`") {
+		t.Errorf("Expected string json tags on int64 fields, got:\n%s", goCode)
+	}
+	// posts.id is an INT
+	if !strings.Contains(goCode, "`json:\"id\"`") {
+		t.Errorf("Expected only int64 fields as strings, got:\n%s", goCode)
+	}
+}
+
+// TestGenerateTypeScriptFiles tests that interfaces used by association fields are imported
+func TestGenerateTypeScriptFiles(t *testing.T) {
+	structs, err := ParseSQL(typeScriptSQL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	files := GenerateTypeScriptFiles(structs, Config{AddAssociations: true})
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 files, got %v", sortedFileNames(files))
+	}
+	if !strings.HasPrefix(files["users.ts"], "import type { Posts } from \"./posts\";\n\n") {
+		t.Errorf("Unexpected users.ts:\n%s", files["users.ts"])
+	}
+	if !strings.HasPrefix(files["posts.ts"], "import type { Users } from \"./users\";\n\n") {
+		t.Errorf("Unexpected posts.ts:\n%s", files["posts.ts"])
+	}
+}
+
+// TestTSFieldType tests TypeScript types of fields without a JSON equivalent and property quoting
+func TestTSFieldType(t *testing.T) {
+	if got := tsFieldType(FieldDef{Type: "json.RawMessage", Nullable: true}, Config{}); got != "unknown" {
+		t.Errorf("Expected unknown for a type override, got %q", got)
+	}
+	if got := tsFieldType(FieldDef{Type: "*uint64", Nullable: true}, Config{Int64AsString: true}); got != "string | null" {
+		t.Errorf("Expected string | null, got %q", got)
+	}
+	if got := tsPropertyName("first name"); got != `"first name"` {
+		t.Errorf("Expected a quoted property name, got %q", got)
+	}
+}